@@ -20,6 +20,7 @@ const (
 	ServiceBrokerSummary = "GetBrokerSummary"
 	ServiceBrokerList    = "GetBrokerCodeList"
 	ServiceStocksList    = "GetSecuritiesStock"
+	ServiceIndexSummary  = "GetIndexSummary"
 
 	/**
 	List referrer header
@@ -28,4 +29,10 @@ const (
 	ReferrerStockSummary  = "https://www.idx.co.id/en/market-data/trading-summary/stock-summary"
 	ReferrerBrokerList    = "https://www.idx.co.id/id/anggota-bursa-dan-partisipan/profil-anggota-bursa"
 	ReferrerStocksList    = "https://www.idx.co.id/id/data-pasar/data-saham/daftar-saham"
+	ReferrerIndexSummary  = "https://www.idx.co.id/en/market-data/trading-summary/index-summary"
+
+	/**
+	List index code
+	**/
+	IndexComposite = "COMPOSITE" // IHSG
 )
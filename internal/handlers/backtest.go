@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RunBacktest(c *gin.Context) {
+	start := time.Now()
+
+	var cfg models.BacktestConfig
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "detail": err.Error()})
+		return
+	}
+
+	if err := services.NormalizeBacktestConfig(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := services.RunBacktest(cfg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	duration := time.Since(start)
+
+	// Gagal simpan tetap kirim hasil, run_id null dan alasannya di save_error
+	var runID *uint64
+	saveError := ""
+	if err := services.SaveBacktestRun(report, duration, nil); err != nil {
+		saveError = err.Error()
+	} else {
+		runID = &report.RunID
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":         "backtest_engine",
+		"run_id":       runID,
+		"save_error":   saveError,
+		"config":       report.Config,
+		"data_start":   report.DataStart.Format("2006-01-02"),
+		"data_end":     report.DataEnd.Format("2006-01-02"),
+		"data_hash":    report.DataHash,
+		"code_version": report.CodeVersion,
+		"trading_days": report.TradingDays,
+		"summary":      report.Summary,
+		"metrics":      report.Metrics,
+		"trades":       report.Trades,
+		"equity_curve": report.EquityCurve,
+		"process_time": duration.String(),
+		"process_ms":   duration.Milliseconds(),
+	})
+}
+
+func ListScreeners(c *gin.Context) {
+	params := gin.H{}
+	for _, name := range services.ScreenerNames() {
+		params[name] = services.ScreenerParamDefaults(name)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"screeners":     services.ScreenerNames(),
+		"params":        params,
+		"sweep_metrics": services.SweepMetrics(),
+	})
+}
+
+func RunParameterSweep(c *gin.Context) {
+	start := time.Now()
+
+	var cfg models.SweepConfig
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "detail": err.Error()})
+		return
+	}
+
+	if err := services.NormalizeSweepConfig(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := services.RunParameterSweep(cfg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":                 "parameter_sweep",
+		"screener":             report.Screener,
+		"metric":               report.Metric,
+		"combinations":         report.Combinations,
+		"workers":              report.Workers,
+		"results":              report.Results,
+		"walk_forward":         report.WalkForward,
+		"walk_forward_summary": report.WFSummary,
+		"save_errors":          report.SaveErrors,
+		"process_time":         duration.String(),
+		"process_ms":           duration.Milliseconds(),
+	})
+}
+
+func RunMonteCarlo(c *gin.Context) {
+	start := time.Now()
+
+	var cfg models.MonteCarloConfig
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "detail": err.Error()})
+		return
+	}
+
+	if err := services.NormalizeMonteCarloConfig(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := services.RunMonteCarlo(cfg)
+	if errors.Is(err, services.ErrNoTrades) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		backtestRunError(c, err)
+		return
+	}
+
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":          "monte_carlo",
+		"run_id":        report.RunID,
+		"save_error":    report.SaveError,
+		"config":        report.Config,
+		"total_trades":  report.TotalTrades,
+		"original":      report.Original,
+		"distributions": report.Distributions,
+		"process_time":  duration.String(),
+		"process_ms":    duration.Milliseconds(),
+	})
+}
+
+func RunSignalStudy(c *gin.Context) {
+	start := time.Now()
+
+	var cfg models.SignalStudyConfig
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "detail": err.Error()})
+		return
+	}
+
+	if err := services.NormalizeSignalStudyConfig(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := services.RunSignalStudy(cfg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":          "signal_study",
+		"config":        report.Config,
+		"signal_days":   report.SignalDays,
+		"total_signals": report.TotalSignals,
+		"overall":       report.Overall,
+		"categories":    report.Categories,
+		"events":        report.Events,
+		"process_time":  duration.String(),
+		"process_ms":    duration.Milliseconds(),
+	})
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultRunListLimit = 50
+	maxRunListLimit     = 500
+)
+
+func parseRunID(raw string) (uint64, error) {
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid run id %q", raw)
+	}
+	return id, nil
+}
+
+func backtestRunError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrBacktestRunNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+func ListBacktestRuns(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultRunListLimit)))
+	if err != nil || limit <= 0 || limit > maxRunListLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be 1-%d", maxRunListLimit)})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+		return
+	}
+
+	runs, err := services.ListBacktestRuns(c.Query("screener"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"count":  len(runs),
+		"limit":  limit,
+		"offset": offset,
+		"data":   runs,
+	})
+}
+
+func GetBacktestRun(c *gin.Context) {
+	id, err := parseRunID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail, err := services.GetBacktestRunDetail(id)
+	if err != nil {
+		backtestRunError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+func CompareBacktestRuns(c *gin.Context) {
+	a, err := parseRunID(c.Query("a"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query a: " + err.Error()})
+		return
+	}
+	b, err := parseRunID(c.Query("b"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query b: " + err.Error()})
+		return
+	}
+
+	comparison, err := services.CompareBacktestRuns(a, b)
+	if err != nil {
+		backtestRunError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+func RerunBacktest(c *gin.Context) {
+	start := time.Now()
+
+	id, err := parseRunID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, comparison, err := services.RerunBacktest(id)
+	if err != nil {
+		backtestRunError(c, err)
+		return
+	}
+
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":         "backtest_rerun",
+		"rerun_of":     id,
+		"run_id":       report.RunID,
+		"reproduced":   comparison.SameData && comparison.SameResult,
+		"comparison":   comparison,
+		"summary":      report.Summary,
+		"metrics":      report.Metrics,
+		"process_time": duration.String(),
+		"process_ms":   duration.Milliseconds(),
+	})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ComputeMarketBreadth(c *gin.Context) {
+	start := time.Now()
+
+	var req models.BreadthComputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "detail": err.Error()})
+		return
+	}
+
+	if err := services.NormalizeBreadthComputeRequest(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows, err := services.ComputeMarketBreadth(req.StartDate, req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Market breadth computed",
+		"start_date":   req.StartDate,
+		"end_date":     req.EndDate,
+		"trading_days": len(rows),
+		"process_time": duration.String(),
+		"process_ms":   duration.Milliseconds(),
+	})
+}
+
+func GetMarketBreadth(c *gin.Context) {
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "120"))
+	if err != nil || days <= 0 || days > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be 1-1000"})
+		return
+	}
+
+	data, err := services.GetMarketBreadth(asOf, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var latest *models.MarketBreadth
+	if len(data) > 0 {
+		latest = &data[len(data)-1]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":   "market_breadth",
+		"as_of":  asOf,
+		"latest": latest,
+		"total":  len(data),
+		"data":   data,
+	})
+}
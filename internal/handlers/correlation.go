@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func UploadUniverse(c *gin.Context) {
+	start := time.Now()
+
+	var req models.UniverseUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "detail": err.Error()})
+		return
+	}
+
+	if err := services.NormalizeUniverseUpload(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := repositories.UpsertUniverse(req.Universe, req.Codes, req.Replace); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed upload universe", "detail": err.Error()})
+		return
+	}
+
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "universe uploaded",
+		"universe":     req.Universe,
+		"replace":      req.Replace,
+		"total":        len(req.Codes),
+		"process_time": duration.String(),
+		"process_ms":   duration.Milliseconds(),
+	})
+}
+
+func ListUniverses(c *gin.Context) {
+	data, err := repositories.GetUniverses()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total": len(data),
+		"data":  data,
+	})
+}
+
+// correlationError: universe nggak ada atau belum ada transaksi = 404, selain itu error server
+func correlationError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrUniverseNotFound) || errors.Is(err, services.ErrNoTradingData) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+func GetCorrelation(c *gin.Context) {
+	start := time.Now()
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req models.CorrelationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.NormalizeCorrelationRequest(&req, true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := services.GetCorrelationReport(asOf, req)
+	if err != nil {
+		correlationError(c, err)
+		return
+	}
+
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":         "correlation",
+		"data":         data,
+		"process_time": duration.String(),
+		"process_ms":   duration.Milliseconds(),
+	})
+}
+
+func GetCorrelatedPeers(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultPeerLimit)))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be 1-100"})
+		return
+	}
+
+	var req models.CorrelationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.NormalizeCorrelationRequest(&req, false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := services.GetCorrelatedPeers(code, asOf, req, limit)
+	if err != nil {
+		correlationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":       "correlated_peers",
+		"stock_code": code,
+		"as_of":      asOf,
+		"window":     req.Window,
+		"total":      len(data),
+		"data":       data,
+	})
+}
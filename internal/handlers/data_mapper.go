@@ -93,3 +93,32 @@ func MapIDXTradingSummaryToModel(s models.TradingSummary) models.TradingSummaryD
 		UpdatedAt: time.Now(),
 	}
 }
+
+func MapIDXIndexSummaryToModel(s models.IndexSummary) models.IndexSummaryDB {
+	var tradeDate time.Time
+	if s.Date != "" {
+		t, err := time.Parse("2006-01-02T15:04:05", s.Date)
+		if err == nil {
+			tradeDate = t
+		}
+	}
+
+	return models.IndexSummaryDB{
+		TradeDate: tradeDate,
+		IndexCode: s.IndexCode,
+
+		Previous: s.Previous,
+		High:     s.Highest,
+		Low:      s.Lowest,
+		Close:    s.Close,
+		Change:   s.Change,
+
+		Volume:        int64(s.Volume),
+		Value:         s.Value,
+		Frequency:     int64(s.Frequency),
+		MarketCapital: s.MarketCapital,
+
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
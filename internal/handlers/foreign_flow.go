@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetStockForeignFlow(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "60"))
+	if err != nil || days <= 0 || days > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be 1-500"})
+		return
+	}
+
+	data, err := services.GetStockForeignFlow(code, asOf, days)
+	if err != nil {
+		stockDataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":  "stock_foreign_flow",
+		"as_of": asOf,
+		"data":  data,
+	})
+}
+
+func GetMarketForeignFlow(c *gin.Context) {
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "1"))
+	if err != nil || days <= 0 || days > 250 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be 1-250"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be 1-200"})
+		return
+	}
+
+	data, err := services.GetMarketForeignFlow(asOf, days, limit)
+	if err != nil {
+		stockDataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":  "market_foreign_flow",
+		"as_of": asOf,
+		"data":  data,
+	})
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadFinancials terima JSON {"data": [...]} atau multipart form dengan file CSV di field "file".
+// ?figures=ytd|quarter wajib: angka laba rugi kumulatif dari awal tahun buku atau per kuartal.
+func UploadFinancials(c *gin.Context) {
+	start := time.Now()
+
+	figures, err := services.NormalizeFinancialFigures(c.Query("figures"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var rows []models.FinancialUploadRow
+	source := services.FinancialSourceUpload
+
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required", "detail": err.Error()})
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed open file", "detail": err.Error()})
+			return
+		}
+		defer f.Close()
+
+		if rows, err = services.ParseFinancialCSV(f); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		source = services.FinancialSourceCSV
+	} else {
+		var req models.FinancialUploadRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "detail": err.Error()})
+			return
+		}
+		rows = req.Data
+	}
+
+	data, err := services.NormalizeFinancialUpload(rows, source)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if figures == services.FiguresYTD {
+		if err := services.DecumulateFinancials(data); err != nil {
+			if errors.Is(err, services.ErrMissingQuarter) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := repositories.UpsertFinancialSummaries(data); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed upload financials", "detail": err.Error()})
+		return
+	}
+
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "financials uploaded",
+		"source":       source,
+		"figures":      figures,
+		"total":        len(data),
+		"process_time": duration.String(),
+		"process_ms":   duration.Milliseconds(),
+	})
+}
+
+func ListStockFinancials(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	data, err := repositories.GetStockFinancials(code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stock_code": code,
+		"total":      len(data),
+		"data":       data,
+	})
+}
+
+func GetStockValuation(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := services.GetStockValuation(code, asOf)
+	if err != nil {
+		stockDataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":  "stock_valuation",
+		"as_of": asOf,
+		"data":  data,
+	})
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"indonesia-stocks-api/internal/constants"
+	"indonesia-stocks-api/internal/helpers"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InsertIndexSummary sync ringkasan indeks harian (termasuk IHSG) dari IDX
+func InsertIndexSummary(c *gin.Context) {
+	start := time.Now()
+
+	var req SyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	dates, err := helpers.GenerateDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	success := 0
+	failed := []string{}
+	totalRows := 0
+
+	for _, date := range dates {
+		data, err := services.FetchIDX[models.IndexSummary](
+			constants.IDXBaseURL,
+			constants.ModuleTradingSummary,
+			constants.ServiceIndexSummary,
+			date,
+		)
+
+		if err != nil {
+			failed = append(failed, date)
+			continue
+		}
+
+		// Hari libur balikin data kosong, skip aja
+		if len(data) == 0 {
+			continue
+		}
+
+		indexSummary := make([]models.IndexSummaryDB, 0, len(data))
+		for _, d := range data {
+			indexSummary = append(indexSummary, MapIDXIndexSummaryToModel(d))
+		}
+
+		if err := repositories.InsertIndexSummary(indexSummary); err != nil {
+			failed = append(failed, date)
+			continue
+		}
+
+		success++
+		totalRows += len(indexSummary)
+
+		time.Sleep(300 * time.Millisecond)
+	}
+
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Index summary sync completed",
+		"start_date":   req.StartDate,
+		"end_date":     req.EndDate,
+		"success_days": success,
+		"failed_days":  failed,
+		"total_rows":   totalRows,
+		"process_time": duration.String(),
+		"process_ms":   duration.Milliseconds(),
+		"execute_date": time.Now().Format("2006-01-02"),
+	})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetStockLevels(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lookback, err := strconv.Atoi(c.DefaultQuery("lookback", strconv.Itoa(services.DefaultLevelLookback)))
+	if err != nil || lookback < 20 || lookback > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lookback must be 20-500"})
+		return
+	}
+
+	data, err := services.GetStockLevels(code, asOf, lookback)
+	if err != nil {
+		stockDataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":     "support_resistance",
+		"as_of":    asOf,
+		"lookback": lookback,
+		"data":     data,
+	})
+}
+
+// applyZoneLevels ganti resistance/support hasil SQL dengan zona swing pivot lalu hitung ulang
+// label screener. Saham tanpa zona di satu sisi tetap pakai nilai rolling di sisi itu.
+// less = urutan ORDER BY SQL kalau urutannya ikut skor yang dihitung ulang, nil = urutan tetap.
+func applyZoneLevels[T any](asOf string, f models.ScreenerFilter, rows []T, code func(T) string, levels func(*T) (*float64, *float64), relabel func(*T), less func(a, b T) bool) error {
+	if f.LevelSource != services.LevelSourceZones || len(rows) == 0 {
+		return nil
+	}
+
+	codes := make([]string, 0, len(rows))
+	for _, r := range rows {
+		codes = append(codes, code(r))
+	}
+
+	zones, err := services.ScreenerLevelsAsOf(asOf, codes)
+	if err != nil {
+		return err
+	}
+
+	for i := range rows {
+		z, ok := zones[code(rows[i])]
+		if !ok {
+			continue
+		}
+		res, sup := levels(&rows[i])
+		if res != nil && z.Resistance > 0 {
+			*res = z.Resistance
+		}
+		if sup != nil && z.Support > 0 {
+			*sup = z.Support
+		}
+		relabel(&rows[i])
+	}
+
+	if less != nil {
+		sort.SliceStable(rows, func(i, j int) bool { return less(rows[i], rows[j]) })
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetStockLiquidity(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	window, err := strconv.Atoi(c.DefaultQuery("window", strconv.Itoa(services.DefaultLiquidityWindow)))
+	if err != nil || window < 5 || window > services.MaxLiquidityWindow {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("window must be 5-%d", services.MaxLiquidityWindow)})
+		return
+	}
+	positionValue, err := strconv.ParseFloat(c.DefaultQuery("position_value", strconv.Itoa(services.DefaultLiquidityPosition)), 64)
+	if err != nil || positionValue <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "position_value must be > 0"})
+		return
+	}
+	participation, err := strconv.ParseFloat(c.DefaultQuery("participation", strconv.FormatFloat(services.DefaultParticipationPct, 'f', -1, 64)), 64)
+	if err != nil || participation <= 0 || participation > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "participation must be 0-100"})
+		return
+	}
+
+	data, err := services.GetStockLiquidity(code, asOf, window, positionValue, participation)
+	if err != nil {
+		stockDataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":  "stock_liquidity",
+		"as_of": asOf,
+		"data":  data,
+	})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetMarketCapRanking(c *gin.Context) {
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rankBy := c.DefaultQuery("rank_by", services.RankByMarketCap)
+	if !slices.Contains(services.MarketCapRankOptions(), rankBy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rank_by", "options": services.MarketCapRankOptions()})
+		return
+	}
+
+	buckets, err := services.ParseSizeBuckets(c.QueryArray("size"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be 1-1000"})
+		return
+	}
+
+	data, tradeDate, err := services.GetMarketCapRanking(asOf, rankBy, buckets, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":       "market_cap_ranking",
+		"as_of":      asOf,
+		"trade_date": tradeDate.Format("2006-01-02"),
+		"rank_by":    rankBy,
+		"size":       buckets,
+		"total":      len(data),
+		"data":       data,
+	})
+}
+
+func GetStockMarketCap(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "60"))
+	if err != nil || days <= 0 || days > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be 1-1000"})
+		return
+	}
+
+	data, err := services.GetStockMarketCap(code, asOf, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":       "stock_market_cap",
+		"stock_code": code,
+		"as_of":      asOf,
+		"total":      len(data),
+		"data":       data,
+	})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ScanNonRegular(c *gin.Context) {
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := strconv.Atoi(c.DefaultQuery("history", strconv.Itoa(services.DefaultNonRegularHistory)))
+	if err != nil || history < 5 || history > 250 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "history must be 5-250"})
+		return
+	}
+	minRatio, err := strconv.ParseFloat(c.DefaultQuery("min_ratio", "3"), 64)
+	if err != nil || minRatio <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid min_ratio"})
+		return
+	}
+	minValue, err := strconv.ParseFloat(c.DefaultQuery("min_value", "1000000000"), 64)
+	if err != nil || minValue < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid min_value"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be 1-500"})
+		return
+	}
+
+	data, err := services.ScanNonRegular(asOf, history, minRatio, minValue, limit)
+	if err != nil {
+		stockDataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode": "non_regular_scan",
+		"data": data,
+	})
+}
+
+func GetStockNonRegular(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "60"))
+	if err != nil || days <= 0 || days > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be 1-500"})
+		return
+	}
+
+	data, err := services.GetStockNonRegular(code, asOf, days, services.DefaultNonRegularHistory)
+	if err != nil {
+		stockDataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":       "stock_non_regular",
+		"stock_code": code,
+		"as_of":      asOf,
+		"total":      len(data),
+		"data":       data,
+	})
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ScanOrderBook(c *gin.Context) {
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be 1-1000"})
+		return
+	}
+
+	flag := c.Query("flag")
+	if err := services.ValidateOrderBookFlag(flag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := services.ScanOrderBook(asOf, flag, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":  "order_book_pressure",
+		"as_of": asOf,
+		"flag":  flag,
+		"total": len(data),
+		"data":  data,
+	})
+}
+
+func GetStockOrderBook(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "20"))
+	if err != nil || days <= 0 || days > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be 1-500"})
+		return
+	}
+
+	data, err := services.GetStockOrderBook(code, asOf, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":       "stock_order_book",
+		"stock_code": code,
+		"as_of":      asOf,
+		"total":      len(data),
+		"data":       data,
+	})
+}
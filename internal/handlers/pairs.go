@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func parsePairLookback(c *gin.Context) (int, error) {
+	lookback, err := strconv.Atoi(c.DefaultQuery("lookback", strconv.Itoa(services.DefaultPairLookback)))
+	if err != nil || lookback < services.MinPairLookback || lookback > services.MaxPairLookback {
+		return 0, fmt.Errorf("lookback must be %d-%d", services.MinPairLookback, services.MaxPairLookback)
+	}
+	return lookback, nil
+}
+
+func parsePairSignificance(c *gin.Context) (float64, error) {
+	significance, err := strconv.ParseFloat(c.DefaultQuery("significance", strconv.FormatFloat(services.DefaultPairSignificance, 'f', -1, 64)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("significance must be 0.01, 0.05 or 0.10")
+	}
+	if _, err := services.PairCriticalValue(significance); err != nil {
+		return 0, err
+	}
+	return significance, nil
+}
+
+// pairError: saham tanpa harga = 404, overlap kurang = 422, selain itu error server
+func pairError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrNoTradingData):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotEnoughOverlap):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func GetPair(c *gin.Context) {
+	a := strings.ToUpper(c.Query("a"))
+	b := strings.ToUpper(c.Query("b"))
+	if a == "" || b == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a and b stock code are required"})
+		return
+	}
+	if a == b {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a and b must be different"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lookback, err := parsePairLookback(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	significance, err := parsePairSignificance(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := services.GetPairReport(a, b, asOf, lookback, significance)
+	if err != nil {
+		pairError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":     "pair",
+		"as_of":    asOf,
+		"lookback": lookback,
+		"data":     data,
+	})
+}
+
+func ScanPairs(c *gin.Context) {
+	start := time.Now()
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lookback, err := parsePairLookback(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	minCorr, err := strconv.ParseFloat(c.DefaultQuery("min_corr", strconv.FormatFloat(services.DefaultPairMinCorr, 'f', -1, 64)), 64)
+	if err != nil || minCorr < -1 || minCorr > 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_corr must be -1 to 1"})
+		return
+	}
+	maxHalfLife, err := strconv.ParseFloat(c.DefaultQuery("max_half_life", strconv.FormatFloat(services.DefaultPairMaxHalfLife, 'f', -1, 64)), 64)
+	if err != nil || maxHalfLife <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "max_half_life must be > 0"})
+		return
+	}
+	significance, err := parsePairSignificance(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultPairScanLimit)))
+	if err != nil || limit <= 0 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be 1-200"})
+		return
+	}
+
+	var req models.CorrelationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.NormalizeCorrelationRequest(&req, true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, tested, err := services.ScanPairs(asOf, req, lookback, minCorr, maxHalfLife, significance, limit)
+	if err != nil {
+		correlationError(c, err)
+		return
+	}
+
+	// Tiap pair diuji terpisah, segini kira-kira yang lolos cuma karena kebetulan
+	expectedFalse := float64(tested) * significance
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":                     "pair_scan",
+		"as_of":                    asOf,
+		"universe":                 req.Universe,
+		"sector":                   req.Sector,
+		"lookback":                 lookback,
+		"min_corr":                 minCorr,
+		"max_half_life":            maxHalfLife,
+		"significance":             significance,
+		"pairs_tested":             tested,
+		"expected_false_positives": expectedFalse,
+		"total":                    len(data),
+		"data":                     data,
+		"process_time":             duration.String(),
+		"process_ms":               duration.Milliseconds(),
+	})
+}
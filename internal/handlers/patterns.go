@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetStockPatterns(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "20"))
+	if err != nil || days <= 0 || days > 250 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be 1-250"})
+		return
+	}
+
+	data, err := services.GetStockPatterns(code, asOf, days)
+	if err != nil {
+		stockDataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":     "stock_patterns",
+		"as_of":    asOf,
+		"days":     days,
+		"patterns": services.PatternNames(),
+		"data":     data,
+	})
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseAsOf baca query param as_of (YYYY-MM-DD), default hari ini.
+// Screener cuma boleh lihat data sampai tanggal ini biar hasilnya sama persis
+// kayak kalau dijalankan di hari itu.
+func parseAsOf(c *gin.Context, fallbackKeys ...string) (string, error) {
+	asOf := c.Query("as_of")
+	for _, key := range fallbackKeys {
+		if asOf != "" {
+			break
+		}
+		asOf = c.Query(key)
+	}
+
+	if asOf == "" {
+		return time.Now().Format("2006-01-02"), nil
+	}
+
+	if _, err := time.Parse("2006-01-02", asOf); err != nil {
+		return "", fmt.Errorf("invalid as_of, format: YYYY-MM-DD")
+	}
+
+	return asOf, nil
+}
+
+// parseScreenerParams ambil override param screener dari query (cuma key yang diminta, >= 0)
+func parseScreenerParams(c *gin.Context, keys ...string) (models.ScreenerParams, error) {
+	params := models.ScreenerParams{}
+	for _, key := range keys {
+		raw := c.Query(key)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("%s must be a number >= 0", key)
+		}
+		params[key] = v
+	}
+	return params, nil
+}
+
+// stockDataError: saham tanpa data transaksi/laporan = 404, selain itu error server
+func stockDataError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrNoTradingData) || errors.Is(err, services.ErrNoFinancialData) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func parseRegimeConfig(c *gin.Context) (models.RegimeConfig, error) {
+	var cfg models.RegimeConfig
+	if err := c.ShouldBindQuery(&cfg); err != nil {
+		return cfg, err
+	}
+	err := services.NormalizeRegimeConfig(&cfg)
+	return cfg, err
+}
+
+// applyRegime ambil regime pasar as_of lalu suppress / downgrade sinyal beli kalau regime-nya hostile.
+// Balikin jumlah baris yang kena. Regime selalu dilaporkan, regime_action cuma nentuin sinyal diubah atau nggak.
+// Regime cuma pelengkap: kalau gagal, sinyal dikirim apa adanya, regime null dan alasannya di string error.
+func applyRegime[T any](asOf string, cfg models.RegimeConfig, rows []T, status func(*T) *string) ([]T, *models.MarketRegime, int, string) {
+	regime, err := services.CurrentMarketRegime(asOf)
+	if err != nil {
+		return rows, nil, 0, err.Error()
+	}
+
+	kept := make([]T, 0, len(rows))
+	adjusted := 0
+	for _, r := range rows {
+		s := status(&r)
+		newStatus, keep := services.ConditionSignal(*s, regime, cfg)
+		if !keep || newStatus != *s {
+			adjusted++
+		}
+		if !keep {
+			continue
+		}
+		*s = newStatus
+		kept = append(kept, r)
+	}
+	return kept, regime, adjusted, ""
+}
+
+func GetMarketRegime(c *gin.Context) {
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "60"))
+	if err != nil || days <= 0 || days > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be 1-1000"})
+		return
+	}
+
+	data, err := services.GetMarketRegime(asOf, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var latest *models.MarketRegime
+	if len(data) > 0 {
+		latest = &data[len(data)-1]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":   "market_regime",
+		"as_of":  asOf,
+		"latest": latest,
+		"total":  len(data),
+		"data":   data,
+	})
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ComputeRelativeStrength(c *gin.Context) {
+	start := time.Now()
+
+	var req models.RSComputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "detail": err.Error()})
+		return
+	}
+
+	if err := services.NormalizeRSComputeRequest(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, rows, err := services.ComputeRelativeStrength(req.StartDate, req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Relative strength computed",
+		"start_date":   req.StartDate,
+		"end_date":     req.EndDate,
+		"trading_days": days,
+		"total_rows":   rows,
+		"process_time": duration.String(),
+		"process_ms":   duration.Milliseconds(),
+	})
+}
+
+func GetRSRanking(c *gin.Context) {
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	minRating, err := strconv.Atoi(c.DefaultQuery("min_rating", "80"))
+	if err != nil || minRating < 1 || minRating > 99 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_rating must be 1-99"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be 1-1000"})
+		return
+	}
+
+	data, tradeDate, err := services.GetRSRanking(asOf, minRating, limit)
+	if err != nil {
+		notComputedError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":       "rs_ranking",
+		"as_of":      asOf,
+		"trade_date": tradeDate.Format("2006-01-02"),
+		"min_rating": minRating,
+		"total":      len(data),
+		"data":       data,
+	})
+}
+
+func GetStockRelativeStrength(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "250"))
+	if err != nil || days <= 0 || days > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be 1-1000"})
+		return
+	}
+
+	data, missing, err := services.GetStockRelativeStrength(code, asOf, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"mode":         "stock_relative_strength",
+		"stock_code":   code,
+		"as_of":        asOf,
+		"total":        len(data),
+		"missing_days": missing,
+		"data":         data,
+	}
+	if missing > 0 {
+		response["note"] = "RS not computed yet for some trading days in range, run POST /rs/compute to fill the history"
+	}
+	c.JSON(http.StatusOK, response)
+}
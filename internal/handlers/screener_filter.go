@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func parseScreenerFilter(c *gin.Context) (models.ScreenerFilter, error) {
+	var f models.ScreenerFilter
+	if err := c.ShouldBindQuery(&f); err != nil {
+		return f, err
+	}
+	err := services.NormalizeScreenerFilter(&f)
+	return f, err
+}
+
+// notComputedError: breadth / RS yang belum di-compute = 409, selain itu error server
+func notComputedError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrBreadthNotComputed) || errors.Is(err, services.ErrRSNotComputed) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// filterScreenerRows buang baris yang nggak lolos filter, urutan hasil screener tetap
+func filterScreenerRows[T any](asOf string, f models.ScreenerFilter, rows []T, code func(T) string) ([]T, map[string]*models.ScreenerAnnotation, error) {
+	if !f.Active() {
+		return rows, nil, nil
+	}
+
+	codes := make([]string, 0, len(rows))
+	for _, r := range rows {
+		codes = append(codes, code(r))
+	}
+
+	annotations, err := services.ApplyScreenerFilter(asOf, codes, f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kept := make([]T, 0, len(rows))
+	for _, r := range rows {
+		if _, ok := annotations[code(r)]; ok {
+			kept = append(kept, r)
+		}
+	}
+	return kept, annotations, nil
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func UploadStockSectors(c *gin.Context) {
+	start := time.Now()
+
+	var req models.SectorUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "detail": err.Error()})
+		return
+	}
+
+	if err := services.NormalizeSectorUpload(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := repositories.UpsertStockSectors(req.Data, req.Replace); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed upload sectors", "detail": err.Error()})
+		return
+	}
+
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "sectors uploaded",
+		"replace":      req.Replace,
+		"total":        len(req.Data),
+		"process_time": duration.String(),
+		"process_ms":   duration.Milliseconds(),
+	})
+}
+
+func ListStockSectors(c *gin.Context) {
+	data, err := repositories.GetStockSectors()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total": len(data),
+		"data":  data,
+	})
+}
+
+func GetSectorRotation(c *gin.Context) {
+	start := time.Now()
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rankBy := c.DefaultQuery("rank_by", "1M")
+	if !slices.Contains(services.SectorRankOptions(), rankBy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rank_by", "options": services.SectorRankOptions()})
+		return
+	}
+
+	data, err := services.GetSectorRotation(asOf, rankBy)
+	if err != nil {
+		stockDataError(c, err)
+		return
+	}
+
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":         "sector_rotation",
+		"data":         data,
+		"process_time": duration.String(),
+		"process_ms":   duration.Milliseconds(),
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"indonesia-stocks-api/internal/repositories"
 	"indonesia-stocks-api/internal/services"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -90,7 +91,25 @@ func InsertTradingSummary(c *gin.Context) {
 func GetTopAccumulation(c *gin.Context) {
 	days := 7
 
-	data, err := repositories.GetTopAccumulation(days)
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := parseScreenerFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	regimeCfg, err := parseRegimeConfig(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := repositories.GetTopAccumulation(days, asOf, nil)
 	if err != nil {
 		c.JSON(500, gin.H{
 			"error": err.Error(),
@@ -98,18 +117,65 @@ func GetTopAccumulation(c *gin.Context) {
 		return
 	}
 
+	err = applyZoneLevels(asOf, filter, data, func(r models.TopAccumulation) string { return r.StockCode },
+		func(r *models.TopAccumulation) (*float64, *float64) { return &r.LastRes20, nil }, repositories.LabelTopAccumulation,
+		func(a, b models.TopAccumulation) bool {
+			// sama dengan ORDER BY breakout_score DESC, net_foreign DESC di query
+			if a.BreakoutScore != b.BreakoutScore {
+				return a.BreakoutScore > b.BreakoutScore
+			}
+			return a.NetForeign > b.NetForeign
+		})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, annotations, err := filterScreenerRows(asOf, filter, data, func(r models.TopAccumulation) string { return r.StockCode })
+	if err != nil {
+		notComputedError(c, err)
+		return
+	}
+
+	data, regime, adjusted, regimeError := applyRegime(asOf, regimeCfg, data, func(r *models.TopAccumulation) *string { return &r.DisplayStatus })
+
 	c.JSON(200, gin.H{
-		"mode":        "top_accumulation",
-		"period_days": days,
-		"total":       len(data),
-		"data":        data,
+		"mode":            "top_accumulation",
+		"as_of":           asOf,
+		"period_days":     days,
+		"total":           len(data),
+		"data":            data,
+		"filter":          filter,
+		"annotations":     annotations,
+		"regime":          regime,
+		"regime_config":   regimeCfg,
+		"regime_adjusted": adjusted,
+		"regime_error":    regimeError,
 	})
 }
 
 func GetTopAccumulationEod(c *gin.Context) {
 	days := 60
 
-	data, err := repositories.GetTopAccumulationEOD(days)
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := parseScreenerFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	regimeCfg, err := parseRegimeConfig(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := repositories.GetTopAccumulationEOD(days, asOf, nil)
 	if err != nil {
 		c.JSON(500, gin.H{
 			"error": err.Error(),
@@ -117,11 +183,33 @@ func GetTopAccumulationEod(c *gin.Context) {
 		return
 	}
 
+	err = applyZoneLevels(asOf, filter, data, func(r models.TopAccumulationEod) string { return r.StockCode },
+		func(r *models.TopAccumulationEod) (*float64, *float64) { return &r.LastRes20, &r.LastSup20 }, repositories.LabelTopAccumulationEOD, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, annotations, err := filterScreenerRows(asOf, filter, data, func(r models.TopAccumulationEod) string { return r.StockCode })
+	if err != nil {
+		notComputedError(c, err)
+		return
+	}
+
+	data, regime, adjusted, regimeError := applyRegime(asOf, regimeCfg, data, func(r *models.TopAccumulationEod) *string { return &r.DisplayStatus })
+
 	c.JSON(200, gin.H{
-		"mode":        "top_accumulation_end_of_day",
-		"period_days": days,
-		"total":       len(data),
-		"data":        data,
+		"mode":            "top_accumulation_end_of_day",
+		"as_of":           asOf,
+		"period_days":     days,
+		"total":           len(data),
+		"data":            data,
+		"filter":          filter,
+		"annotations":     annotations,
+		"regime":          regime,
+		"regime_config":   regimeCfg,
+		"regime_adjusted": adjusted,
+		"regime_error":    regimeError,
 	})
 }
 
@@ -174,13 +262,33 @@ func RunBacktestEOD(c *gin.Context) {
 }
 
 func GetTopScalping(c *gin.Context) {
-	tradeDate := c.Query("date")
+	// "date" masih diterima biar client lama nggak rusak
+	tradeDate, err := parseAsOf(c, "date")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := parseScreenerFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	if tradeDate == "" {
-		tradeDate = time.Now().Format("2006-01-02")
+	regimeCfg, err := parseRegimeConfig(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Stop & target berbasis ATR (?atr_stop_mult=2&atr_target_mult=3), default persen tetap
+	params, err := parseScreenerParams(c, "atr_stop_mult", "atr_target_mult")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	data, err := repositories.GetTopSwinger(tradeDate)
+	data, err := services.GetTopSwinger(tradeDate, params)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -188,17 +296,50 @@ func GetTopScalping(c *gin.Context) {
 		return
 	}
 
+	data, annotations, err := filterScreenerRows(tradeDate, filter, data, func(r models.TopSwinger) string { return r.StockCode })
+	if err != nil {
+		notComputedError(c, err)
+		return
+	}
+
+	data, regime, adjusted, regimeError := applyRegime(tradeDate, regimeCfg, data, func(r *models.TopSwinger) *string { return &r.DisplayStatus })
+
 	c.JSON(http.StatusOK, gin.H{
-		"date":  tradeDate,
-		"total": len(data),
-		"data":  data,
+		"date":            tradeDate,
+		"as_of":           tradeDate,
+		"total":           len(data),
+		"data":            data,
+		"filter":          filter,
+		"annotations":     annotations,
+		"regime":          regime,
+		"regime_config":   regimeCfg,
+		"regime_adjusted": adjusted,
+		"regime_error":    regimeError,
 	})
 }
 
 func GetSilentAccumulation(c *gin.Context) {
 	days := 7
 
-	data, err := repositories.GetSilentAccumulation(days)
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := parseScreenerFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	regimeCfg, err := parseRegimeConfig(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := repositories.GetSilentAccumulation(days, asOf, nil)
 	if err != nil {
 		c.JSON(500, gin.H{
 			"error": err.Error(),
@@ -206,16 +347,31 @@ func GetSilentAccumulation(c *gin.Context) {
 		return
 	}
 
+	data, annotations, err := filterScreenerRows(asOf, filter, data, func(r models.SilentAccumulation) string { return r.StockCode })
+	if err != nil {
+		notComputedError(c, err)
+		return
+	}
+
+	data, regime, adjusted, regimeError := applyRegime(asOf, regimeCfg, data, func(r *models.SilentAccumulation) *string { return &r.DisplayStatus })
+
 	c.JSON(200, gin.H{
-		"mode":        "silent_accumulation_end_of_day",
-		"period_days": days,
-		"total":       len(data),
-		"data":        data,
+		"mode":            "silent_accumulation_end_of_day",
+		"as_of":           asOf,
+		"period_days":     days,
+		"total":           len(data),
+		"data":            data,
+		"filter":          filter,
+		"annotations":     annotations,
+		"regime":          regime,
+		"regime_config":   regimeCfg,
+		"regime_adjusted": adjusted,
+		"regime_error":    regimeError,
 	})
 }
 
 func StatisticSingleStock(c *gin.Context) {
-	code := c.Query("stock_code")
+	code := strings.ToUpper(c.Query("stock_code"))
 
 	if code == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -230,9 +386,39 @@ func StatisticSingleStock(c *gin.Context) {
 		return
 	}
 
+	// Blok tambahan di bawah best-effort: kalau gagal (data kurang, tabel belum ada)
+	// nilainya null dan alasannya masuk warnings, statistik utama tetap dikirim
+	today := time.Now().Format("2006-01-02")
+	warnings := []string{}
+
+	// trend_status cuma lihat close/volume/strength, pola candle & chart dari OHLC terpisah
+	var patterns *models.StockPatterns
+	if len(data) > 0 {
+		if patterns, err = services.GetStockPatterns(code, today, 20); err != nil {
+			warnings = append(warnings, "patterns: "+err.Error())
+		}
+	}
+
+	orderBook, err := services.GetStockOrderBook(code, today, 20)
+	if err != nil {
+		warnings = append(warnings, "order_book: "+err.Error())
+	}
+
+	// RS rating & RS line vs IHSG buat di-plot (terbaru dulu)
+	relativeStrength, missing, err := services.GetStockRelativeStrength(code, today, 250)
+	if err != nil {
+		warnings = append(warnings, "relative_strength: "+err.Error())
+	} else if missing > 0 {
+		warnings = append(warnings, fmt.Sprintf("relative_strength: %d trading days not computed yet, run POST /rs/compute", missing))
+	}
+
 	c.JSON(200, gin.H{
-		"mode":        "Single Stock Statistic",
-		"target_date": time.Now(),
-		"data":        data,
+		"mode":              "Single Stock Statistic",
+		"target_date":       time.Now(),
+		"data":              data,
+		"patterns":          patterns,
+		"order_book":        orderBook,
+		"relative_strength": relativeStrength,
+		"warnings":          warnings,
 	})
 }
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetStockVolatility(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "60"))
+	if err != nil || days <= 0 || days > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be 1-500"})
+		return
+	}
+
+	data, err := services.GetStockVolatility(code, asOf, days)
+	if err != nil {
+		stockDataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":       "stock_volatility",
+		"stock_code": code,
+		"as_of":      asOf,
+		"latest":     data[len(data)-1],
+		"total":      len(data),
+		"data":       data,
+	})
+}
+
+func ScanSqueeze(c *gin.Context) {
+	start := time.Now()
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	minValue, err := strconv.ParseFloat(c.DefaultQuery("min_value", strconv.Itoa(services.DefaultSqueezeMinValue)), 64)
+	if err != nil || minValue < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_value must be >= 0"})
+		return
+	}
+	includeReleased := c.DefaultQuery("released", "true") == "true"
+
+	data, err := services.ScanSqueeze(asOf, minValue, includeReleased)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	duration := time.Since(start)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":         "volatility_squeeze",
+		"as_of":        asOf,
+		"min_value":    minValue,
+		"released":     includeReleased,
+		"total":        len(data),
+		"data":         data,
+		"process_time": duration.String(),
+		"process_ms":   duration.Milliseconds(),
+	})
+}
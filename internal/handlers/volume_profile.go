@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"indonesia-stocks-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetVolumeProfile(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	window, err := strconv.Atoi(c.DefaultQuery("window", strconv.Itoa(services.DefaultProfileWindow)))
+	if err != nil || window < 5 || window > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "window must be 5-1000"})
+		return
+	}
+	bins, err := strconv.Atoi(c.DefaultQuery("bins", strconv.Itoa(services.DefaultProfileBins)))
+	if err != nil || bins < 5 || bins > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bins must be 5-200"})
+		return
+	}
+
+	data, err := services.GetVolumeProfile(code, asOf, window, bins)
+	if err != nil {
+		stockDataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":   "volume_profile",
+		"as_of":  asOf,
+		"window": window,
+		"data":   data,
+	})
+}
+
+func GetAnchoredVWAP(c *gin.Context) {
+	code := strings.ToUpper(c.Query("stock_code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stock Code is required"})
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// ?anchors=ipo,52w_low,2025-04-30 (tanggal bebas, mis. rilis laporan keuangan)
+	anchors, err := services.ParseAVWAPAnchors(c.DefaultQuery("anchors", services.DefaultAVWAPAnchors))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "60"))
+	if err != nil || days <= 0 || days > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be 1-1000"})
+		return
+	}
+
+	data, err := services.GetAnchoredVWAP(code, asOf, anchors, days)
+	if err != nil {
+		stockDataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":       "anchored_vwap",
+		"stock_code": code,
+		"as_of":      asOf,
+		"total":      len(data),
+		"data":       data,
+	})
+}
@@ -0,0 +1,246 @@
+package models
+
+import "time"
+
+// DailyBar = satu baris OHLCV per saham per hari, dipakai engine backtest
+type DailyBar struct {
+	StockCode string    `db:"stock_code" json:"stock_code"`
+	StockName string    `db:"stock_name" json:"stock_name"`
+	TradeDate time.Time `db:"trade_date" json:"trade_date"`
+	Previous  float64   `db:"previous_price" json:"previous_price"`
+	Open      float64   `db:"open_price" json:"open_price"`
+	High      float64   `db:"high_price" json:"high_price"`
+	Low       float64   `db:"low_price" json:"low_price"`
+	Close     float64   `db:"close_price" json:"close_price"`
+	Volume    float64   `db:"volume" json:"volume"`
+	Value     float64   `db:"value" json:"value"`
+
+	// Input screener selain OHLCV, ikut di-hash biar revisi data kelihatan di data_hash
+	ForeignBuy    float64 `db:"foreign_buy" json:"foreign_buy"`
+	ForeignSell   float64 `db:"foreign_sell" json:"foreign_sell"`
+	CloseStrength float64 `db:"close_strength" json:"close_strength"`
+	Frequency     float64 `db:"frequency" json:"frequency"`
+
+	ListingBoard string `db:"listing_board" json:"listing_board"` // buat batas ARA/ARB
+}
+
+// ScreenerSignal = output screener yang sudah diseragamkan,
+// jadi screener apa pun bisa dipakai sebagai sinyal entry backtest
+type ScreenerSignal struct {
+	StockCode     string    `json:"stock_code"`
+	StockName     string    `json:"stock_name"`
+	SignalDate    time.Time `json:"signal_date"`
+	Price         float64   `json:"price"`
+	StopLoss      float64   `json:"stop_loss"`   // 0 = screener nggak kasih level
+	TakeProfit    float64   `json:"take_profit"` // 0 = screener nggak kasih level
+	DisplayStatus string    `json:"display_status"`
+	Category      string    `json:"category"` // HAKA, BREAKOUT, BOW, dst (dari DisplayStatus)
+}
+
+type ExitRules struct {
+	StopLossPct     float64 `json:"stop_loss_pct"`     // cut loss dari harga entry, misal 5 = -5%
+	TakeProfitPct   float64 `json:"take_profit_pct"`   // misal 10 = +10%
+	TrailingStopPct float64 `json:"trailing_stop_pct"` // dari harga tertinggi sejak entry
+	MaxHoldDays     int     `json:"max_hold_days"`     // time stop, dalam hari bursa
+	MACrossPeriod   int     `json:"ma_cross_period"`   // exit kalau close di bawah MA ini
+	// Pakai StopLoss/TakeProfit dari screener (contoh: TopSwinger) kalau ada
+	UseSignalLevels bool `json:"use_signal_levels"`
+}
+
+// ExecutionConfig = model eksekusi IDX: fraksi harga, lot, ARA/ARB, fee broker & pajak.
+// Fee pakai pointer biar bisa bedain "nggak dikirim" (pakai default) dan 0 beneran.
+type ExecutionConfig struct {
+	BuyFeePct     *float64 `json:"buy_fee_pct"`
+	SellFeePct    *float64 `json:"sell_fee_pct"`
+	SellTaxPct    *float64 `json:"sell_tax_pct"`
+	PositionValue float64  `json:"position_value"` // nominal IDR per trade
+}
+
+// PortfolioConfig aktif kalau InitialCapital > 0: modal terbatas, max posisi, dan sizing
+type PortfolioConfig struct {
+	InitialCapital float64 `json:"initial_capital"`
+	MaxPositions   int     `json:"max_positions"`
+	Sizing         string  `json:"sizing"`           // equal_weight | fixed_risk | volatility
+	RiskPct        float64 `json:"risk_pct"`         // % equity yang dirisikokan per trade
+	ATRPeriod      int     `json:"atr_period"`       // buat sizing volatility
+	MaxPositionPct float64 `json:"max_position_pct"` // batas nilai satu posisi dari equity, 0 = bebas
+}
+
+type EquityPoint struct {
+	Date           time.Time `db:"trade_date" json:"date"`
+	Cash           float64   `db:"cash" json:"cash"`
+	PositionsValue float64   `db:"positions_value" json:"positions_value"`
+	Equity         float64   `db:"equity" json:"equity"`
+	OpenPositions  int       `db:"open_positions" json:"open_positions"`
+	DailyReturnPct float64   `db:"daily_return_pct" json:"daily_return_pct"`
+}
+
+type BacktestConfig struct {
+	Screener         string          `json:"screener" binding:"required"`
+	Params           ScreenerParams  `json:"params"`                        // override threshold screener
+	StartDate        string          `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate          string          `json:"end_date" binding:"required"`   // YYYY-MM-DD
+	EntryMode        string          `json:"entry_mode"`                    // next_open (default) | close
+	MaxSignalsPerDay int             `json:"max_signals_per_day"`           // 0 = semua sinyal
+	Exit             ExitRules       `json:"exit"`
+	Execution        ExecutionConfig `json:"execution"`
+	Portfolio        PortfolioConfig `json:"portfolio"`
+	BenchmarkIndex   string          `json:"benchmark_index"`    // default COMPOSITE (IHSG)
+	RiskFreeRatePct  float64         `json:"risk_free_rate_pct"` // tahunan, buat Sharpe/Sortino
+}
+
+type BacktestTrade struct {
+	StockCode       string    `db:"stock_code" json:"stock_code"`
+	StockName       string    `db:"stock_name" json:"stock_name"`
+	SignalDate      time.Time `db:"signal_date" json:"signal_date"`
+	SignalStatus    string    `db:"signal_status" json:"signal_status"`
+	EntryDate       time.Time `db:"entry_date" json:"entry_date"`
+	EntryPrice      float64   `db:"entry_price" json:"entry_price"`
+	ExitDate        time.Time `db:"exit_date" json:"exit_date"`
+	ExitPrice       float64   `db:"exit_price" json:"exit_price"`
+	ExitReason      string    `db:"exit_reason" json:"exit_reason"`
+	HoldingDays     int       `db:"holding_days" json:"holding_days"`
+	ReturnPct       float64   `db:"return_pct" json:"return_pct"`
+	StopLossLevel   float64   `db:"stop_loss_level" json:"stop_loss_level"`
+	TakeProfitLevel float64   `db:"take_profit_level" json:"take_profit_level"`
+
+	Lots         int     `db:"lots" json:"lots"`
+	BuyFee       float64 `db:"buy_fee" json:"buy_fee"`
+	SellFee      float64 `db:"sell_fee" json:"sell_fee"` // fee broker + pajak jual
+	NetPnL       float64 `db:"net_pnl" json:"net_pnl"`
+	NetReturnPct float64 `db:"net_return_pct" json:"net_return_pct"`
+}
+
+type BacktestSummary struct {
+	TotalSignals   int     `json:"total_signals"`
+	TotalTrades    int     `json:"total_trades"`
+	WinCount       int     `json:"win_count"`
+	LoseCount      int     `json:"lose_count"`
+	BreakEvenCount int     `json:"break_even_count"` // net return persis 0
+	WinRate        float64 `json:"win_rate"`
+	AvgReturnPct   float64 `json:"avg_return_pct"`
+	AvgHoldingDays float64 `json:"avg_holding_days"`
+
+	AvgNetReturnPct float64        `json:"avg_net_return_pct"`
+	TotalNetPnL     float64        `json:"total_net_pnl"`
+	TotalFees       float64        `json:"total_fees"`
+	RejectedEntries map[string]int `json:"rejected_entries"` // alasan -> jumlah (ARA, lot kurang)
+	DelayedExits    int            `json:"delayed_exits"`    // exit yang ketahan ARB
+
+	// Cuma terisi di mode portfolio
+	InitialCapital float64 `json:"initial_capital,omitempty"`
+	FinalEquity    float64 `json:"final_equity,omitempty"`
+	TotalReturnPct float64 `json:"total_return_pct,omitempty"`
+}
+
+type MonthlyReturn struct {
+	Month              string   `json:"month"` // YYYY-MM
+	ReturnPct          float64  `json:"return_pct"`
+	BenchmarkReturnPct *float64 `json:"benchmark_return_pct"`
+}
+
+// CurveStats = statistik dari satu kurva nilai (equity strategi atau indeks benchmark)
+type CurveStats struct {
+	StartValue          float64  `json:"start_value"`
+	EndValue            float64  `json:"end_value"`
+	TotalReturnPct      float64  `json:"total_return_pct"`
+	CAGRPct             float64  `json:"cagr_pct"`
+	MaxDrawdownPct      float64  `json:"max_drawdown_pct"`
+	MaxDrawdownDays     int      `json:"max_drawdown_days"` // hari bursa dari puncak sampai pulih
+	VolatilityPct       float64  `json:"volatility_pct"`    // disetahunkan
+	Sharpe              float64  `json:"sharpe"`
+	Sortino             *float64 `json:"sortino"`
+	Calmar              *float64 `json:"calmar"`
+	TradingDaysObserved int      `json:"trading_days_observed"`
+}
+
+type BenchmarkComparison struct {
+	IndexCode       string     `json:"index_code"`
+	Stats           CurveStats `json:"stats"`
+	ExcessReturnPct *float64   `json:"excess_return_pct"` // strategi - benchmark, null di mode per sinyal
+	Beta            *float64   `json:"beta"`
+	Correlation     *float64   `json:"correlation"`
+}
+
+type BacktestMetrics struct {
+	Equity         *CurveStats          `json:"equity"`                // null di mode per sinyal (tanpa modal)
+	EquityNote     string               `json:"equity_note,omitempty"` // alasan equity null
+	ProfitFactor   *float64             `json:"profit_factor"`
+	ExpectancyPct  float64              `json:"expectancy_pct"`
+	ExpectancyIDR  float64              `json:"expectancy_idr"`
+	AvgWinPct      float64              `json:"avg_win_pct"`
+	AvgLossPct     float64              `json:"avg_loss_pct"`
+	AvgHoldingDays float64              `json:"avg_holding_days"`
+	ExposurePct    float64              `json:"exposure_pct"` // % hari bursa ada posisi terbuka
+	MonthlyReturns []MonthlyReturn      `json:"monthly_returns"`
+	Benchmark      *BenchmarkComparison `json:"benchmark"`
+}
+
+type BacktestReport struct {
+	RunID       uint64          `json:"run_id,omitempty"` // terisi setelah disimpan
+	Config      BacktestConfig  `json:"config"`
+	DataStart   time.Time       `json:"data_start"`
+	DataEnd     time.Time       `json:"data_end"`
+	DataHash    string          `json:"data_hash"` // sha256 bar input, beda hash = data berubah
+	CodeVersion string          `json:"code_version"`
+	TradingDays int             `json:"trading_days"`
+	Summary     BacktestSummary `json:"summary"`
+	Trades      []BacktestTrade `json:"trades"`
+	EquityCurve []EquityPoint   `json:"equity_curve,omitempty"`
+	Metrics     BacktestMetrics `json:"metrics"`
+}
+
+// BacktestRun = baris t_backtest_run, kolom JSON di-decode ke field di bawahnya
+type BacktestRun struct {
+	ID          uint64    `db:"id" json:"id"`
+	Screener    string    `db:"screener" json:"screener"`
+	CodeVersion string    `db:"code_version" json:"code_version"`
+	DataStart   time.Time `db:"data_start" json:"data_start"`
+	DataEnd     time.Time `db:"data_end" json:"data_end"`
+	DataHash    string    `db:"data_hash" json:"data_hash"`
+	TotalTrades int       `db:"total_trades" json:"total_trades"`
+	RerunOf     *uint64   `db:"rerun_of" json:"rerun_of"`
+	DurationMs  int64     `db:"duration_ms" json:"duration_ms"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+
+	ConfigJSON  string `db:"config_json" json:"-"`
+	SummaryJSON string `db:"summary_json" json:"-"`
+	MetricsJSON string `db:"metrics_json" json:"-"`
+
+	Config  BacktestConfig  `db:"-" json:"config"`
+	Summary BacktestSummary `db:"-" json:"summary"`
+	Metrics BacktestMetrics `db:"-" json:"metrics"`
+}
+
+type BacktestTradeDB struct {
+	RunID uint64 `db:"run_id"`
+	BacktestTrade
+}
+
+type BacktestEquityDB struct {
+	RunID uint64 `db:"run_id"`
+	EquityPoint
+}
+
+type BacktestRunDetail struct {
+	Run         BacktestRun     `json:"run"`
+	Trades      []BacktestTrade `json:"trades"`
+	EquityCurve []EquityPoint   `json:"equity_curve"`
+}
+
+type RunMetricDelta struct {
+	Metric string   `json:"metric"`
+	A      *float64 `json:"a"`
+	B      *float64 `json:"b"`
+	Delta  *float64 `json:"delta"` // b - a
+}
+
+type BacktestRunComparison struct {
+	A             BacktestRun      `json:"a"`
+	B             BacktestRun      `json:"b"`
+	SameData      bool             `json:"same_data"`
+	SameCode      bool             `json:"same_code"`
+	SameResult    bool             `json:"same_result"` // semua metrik selisih 0
+	ConfigChanges []string         `json:"config_changes"`
+	Deltas        []RunMetricDelta `json:"deltas"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type MarketBreadth struct {
+	TradeDate     time.Time `db:"trade_date" json:"trade_date"`
+	TotalStocks   int       `db:"total_stocks" json:"total_stocks"`
+	Advancers     int       `db:"advancers" json:"advancers"`
+	Decliners     int       `db:"decliners" json:"decliners"`
+	Unchanged     int       `db:"unchanged" json:"unchanged"`
+	ADLine        int64     `db:"ad_line" json:"ad_line"`
+	NewHighs52W   int       `db:"new_highs_52w" json:"new_highs_52w"`
+	NewLows52W    int       `db:"new_lows_52w" json:"new_lows_52w"`
+	PctAboveMA20  float64   `db:"pct_above_ma20" json:"pct_above_ma20"`
+	PctAboveMA50  float64   `db:"pct_above_ma50" json:"pct_above_ma50"`
+	PctAboveMA200 float64   `db:"pct_above_ma200" json:"pct_above_ma200"`
+	EMA19         float64   `db:"ema19" json:"-"`
+	EMA39         float64   `db:"ema39" json:"-"`
+	McClellanOsc  float64   `db:"mcclellan_osc" json:"mcclellan_osc"`
+	McClellanSum  float64   `db:"mcclellan_sum" json:"mcclellan_sum"`
+	ARACount      int       `db:"ara_count" json:"ara_count"`
+	ARBCount      int       `db:"arb_count" json:"arb_count"`
+	Regime        string    `db:"-" json:"regime"` // risk_on / neutral / risk_off dari breadth
+}
+
+type BreadthComputeRequest struct {
+	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate   string `json:"end_date"`
+}
@@ -0,0 +1,63 @@
+package models
+
+type StockUniverse struct {
+	UniverseCode string `db:"universe_code" json:"universe_code"`
+	StockCode    string `db:"stock_code" json:"stock_code"`
+}
+
+type UniverseSummary struct {
+	UniverseCode string `db:"universe_code" json:"universe_code"`
+	TotalStocks  int    `db:"total_stocks" json:"total_stocks"`
+}
+
+type UniverseUploadRequest struct {
+	Universe string   `json:"universe" binding:"required"` // LQ45, IDX30, WATCHLIST_A, dst
+	Replace  bool     `json:"replace"`                     // true = isi universe diganti total
+	Codes    []string `json:"codes" binding:"required,min=1"`
+}
+
+// CorrelationRequest di-bind dari query param, pilih salah satu: universe, sector, atau codes
+type CorrelationRequest struct {
+	Universe string   `form:"universe" json:"universe,omitempty"`
+	Sector   string   `form:"sector" json:"sector,omitempty"`
+	Codes    []string `form:"codes" json:"codes,omitempty"` // watchlist ad hoc, comma-separated
+	Window   int      `form:"window" json:"window"`         // jumlah return harian, default 60
+	Clusters int      `form:"clusters" json:"clusters"`     // jumlah cluster hasil potong dendrogram
+}
+
+// ClusterMerge = satu langkah penggabungan (format linkage ala scipy):
+// id < jumlah saham = saham di Codes, selebihnya cluster hasil merge ke (id - jumlah saham)
+type ClusterMerge struct {
+	Left     int     `json:"left"`
+	Right    int     `json:"right"`
+	Distance float64 `json:"distance"` // sqrt(2 * (1 - korelasi)), average linkage
+	Size     int     `json:"size"`
+}
+
+type StockCluster struct {
+	Cluster        int      `json:"cluster"`
+	Codes          []string `json:"codes"`
+	AvgCorrelation *float64 `json:"avg_correlation,omitempty"` // rata-rata korelasi antar anggota
+}
+
+type CorrelationReport struct {
+	Universe       string         `json:"universe"`
+	AsOf           string         `json:"as_of"`
+	StartDate      string         `json:"start_date"`
+	EndDate        string         `json:"end_date"`
+	Window         int            `json:"window"`
+	Codes          []string       `json:"codes"`
+	Matrix         [][]*float64   `json:"matrix"` // urut sesuai Codes, null kalau data barengan kurang
+	Order          []string       `json:"order"`  // urutan daun dendrogram, buat heatmap
+	Linkage        []ClusterMerge `json:"linkage"`
+	Clusters       []StockCluster `json:"clusters"`
+	AvgCorrelation *float64       `json:"avg_correlation"` // rata-rata semua pasangan
+	Skipped        []string       `json:"skipped"`         // saham yang datanya kurang di window
+}
+
+type CorrelatedPeer struct {
+	StockCode    string  `json:"stock_code"`
+	Sector       string  `json:"sector,omitempty"`
+	Correlation  float64 `json:"correlation"`
+	Observations int     `json:"observations"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// ForeignFlowRow = kolom foreign mentah dari t_trading_summary (buy/sell dalam lembar)
+type ForeignFlowRow struct {
+	StockCode   string    `db:"stock_code"`
+	StockName   string    `db:"stock_name"`
+	TradeDate   time.Time `db:"trade_date"`
+	Close       float64   `db:"close_price"`
+	Volume      float64   `db:"volume"`
+	Value       float64   `db:"value"`
+	ForeignBuy  float64   `db:"foreign_buy"`
+	ForeignSell float64   `db:"foreign_sell"`
+}
+
+// Semua nilai foreign dalam Rupiah = lembar x close
+type ForeignFlowDaily struct {
+	TradeDate       time.Time `json:"trade_date"`
+	Close           float64   `json:"close"`
+	ForeignBuyVal   float64   `json:"foreign_buy_val"`
+	ForeignSellVal  float64   `json:"foreign_sell_val"`
+	NetForeignVal   float64   `json:"net_foreign_val"`
+	CumNetForeign   float64   `json:"cum_net_foreign_val"`
+	ForeignSharePct float64   `json:"foreign_share_pct"` // (buy + sell) / (2 x volume)
+}
+
+type ForeignStreak struct {
+	Direction string  `json:"direction"` // net_buy / net_sell / flat
+	Days      int     `json:"days"`
+	NetValue  float64 `json:"net_value"`
+}
+
+type StockForeignFlow struct {
+	StockCode          string             `json:"stock_code"`
+	StockName          string             `json:"stock_name"`
+	AsOf               string             `json:"as_of"`
+	Days               int                `json:"days"`
+	CumNetForeign      float64            `json:"cum_net_foreign_val"`
+	FormattedCumNet    string             `json:"formatted_cum_net_foreign"`
+	AvgForeignSharePct float64            `json:"avg_foreign_share_pct"`
+	CurrentStreak      ForeignStreak      `json:"current_streak"`
+	LongestBuyStreak   ForeignStreak      `json:"longest_buy_streak"`
+	LongestSellStreak  ForeignStreak      `json:"longest_sell_streak"`
+	Daily              []ForeignFlowDaily `json:"daily"` // terbaru dulu
+}
+
+type ForeignFlowRank struct {
+	StockCode       string        `json:"stock_code"`
+	StockName       string        `json:"stock_name"`
+	Close           float64       `json:"close"`
+	ForeignBuyVal   float64       `json:"foreign_buy_val"`
+	ForeignSellVal  float64       `json:"foreign_sell_val"`
+	NetForeignVal   float64       `json:"net_foreign_val"`
+	FormattedNet    string        `json:"formatted_net_foreign"`
+	Value           float64       `json:"value"`
+	ForeignSharePct float64       `json:"foreign_share_pct"`
+	Streak          ForeignStreak `json:"streak"`
+}
+
+type MarketForeignFlowDay struct {
+	TradeDate       time.Time `json:"trade_date"`
+	ForeignBuyVal   float64   `json:"foreign_buy_val"`
+	ForeignSellVal  float64   `json:"foreign_sell_val"`
+	NetForeignVal   float64   `json:"net_foreign_val"`
+	Value           float64   `json:"value"`
+	ForeignSharePct float64   `json:"foreign_share_pct"`
+}
+
+type MarketForeignFlow struct {
+	AsOf       string                 `json:"as_of"`
+	Days       int                    `json:"days"` // window ranking (hari bursa)
+	DateFrom   time.Time              `json:"date_from"`
+	DateTo     time.Time              `json:"date_to"`
+	Total      MarketForeignFlowDay   `json:"total"`
+	Daily      []MarketForeignFlowDay `json:"daily"` // terbaru dulu
+	TopNetBuy  []ForeignFlowRank      `json:"top_net_buy"`
+	TopNetSell []ForeignFlowRank      `json:"top_net_sell"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+type FinancialSummary struct {
+	StockCode     string    `db:"stock_code" json:"stock_code"`
+	FiscalYear    int       `db:"fiscal_year" json:"fiscal_year"`
+	FiscalQuarter int       `db:"fiscal_quarter" json:"fiscal_quarter"`
+	PeriodEnd     time.Time `db:"period_end" json:"period_end"`
+	PublishedDate time.Time `db:"published_date" json:"published_date"`
+	Revenue       float64   `db:"revenue" json:"revenue"`
+	NetIncome     float64   `db:"net_income" json:"net_income"`
+	TotalEquity   float64   `db:"total_equity" json:"total_equity"`
+	EPS           *float64  `db:"eps" json:"eps"`
+	DER           *float64  `db:"der" json:"der"`
+	Source        string    `db:"source" json:"source"`
+}
+
+// FinancialUploadRow = satu baris upload (JSON atau CSV dengan header nama kolom yang sama).
+// Revenue, laba & EPS YTD seperti laporan IDX (?figures=ytd) atau angka kuartal itu saja (?figures=quarter),
+// disimpan selalu sebagai angka kuartal.
+type FinancialUploadRow struct {
+	StockCode     string   `json:"stock_code" binding:"required"`
+	FiscalYear    int      `json:"fiscal_year" binding:"required"`
+	FiscalQuarter int      `json:"fiscal_quarter" binding:"required"`
+	PeriodEnd     string   `json:"period_end"`     // YYYY-MM-DD, kosong = akhir kuartal
+	PublishedDate string   `json:"published_date"` // YYYY-MM-DD, kosong = period_end + 90 hari
+	Revenue       float64  `json:"revenue"`
+	NetIncome     float64  `json:"net_income"`
+	TotalEquity   float64  `json:"total_equity"`
+	EPS           *float64 `json:"eps"`
+	DER           *float64 `json:"der"`
+}
+
+type FinancialUploadRequest struct {
+	Data []FinancialUploadRow `json:"data" binding:"required,min=1,dive"`
+}
+
+// StockValuation = rasio fundamental terhadap close hari bursa terakhir <= as_of,
+// cuma pakai laporan yang sudah terbit di tanggal itu
+type StockValuation struct {
+	StockCode     string   `json:"stock_code"`
+	TradeDate     string   `json:"trade_date"`
+	Close         float64  `json:"close"`
+	LatestPeriod  string   `json:"latest_period"` // 2025Q2
+	PublishedDate string   `json:"published_date"`
+	QuartersUsed  int      `json:"quarters_used"` // kuartal buat TTM, < 4 berarti TTM belum lengkap
+	RevenueTTM    float64  `json:"revenue_ttm"`
+	NetIncomeTTM  float64  `json:"net_income_ttm"`
+	TotalEquity   float64  `json:"total_equity"`
+	EPSTTM        *float64 `json:"eps_ttm"`
+	BVPS          *float64 `json:"bvps"`
+	PER           *float64 `json:"per"` // null kalau laba TTM <= 0
+	PBV           *float64 `json:"pbv"`
+	ROE           *float64 `json:"roe"` // laba TTM / ekuitas terakhir, %
+	DER           *float64 `json:"der"`
+}
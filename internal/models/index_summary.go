@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+type IndexSummary struct {
+	No            int     `json:"No"`
+	IndexCode     string  `json:"IndexCode"`
+	Date          string  `json:"Date"`
+	Previous      float64 `json:"Previous"`
+	Highest       float64 `json:"Highest"`
+	Lowest        float64 `json:"Lowest"`
+	Close         float64 `json:"Close"`
+	Change        float64 `json:"Change"`
+	Volume        float64 `json:"Volume"`
+	Value         float64 `json:"Value"`
+	Frequency     float64 `json:"Frequency"`
+	MarketCapital float64 `json:"MarketCapital"`
+}
+
+type IndexSummaryDB struct {
+	ID uint64 `db:"id"`
+
+	TradeDate time.Time `db:"trade_date"`
+	IndexCode string    `db:"index_code"`
+
+	Previous float64 `db:"previous_price"`
+	High     float64 `db:"high_price"`
+	Low      float64 `db:"low_price"`
+	Close    float64 `db:"close_price"`
+	Change   float64 `db:"change_price"`
+
+	Volume        int64   `db:"volume"`
+	Value         float64 `db:"value"`
+	Frequency     int64   `db:"frequency"`
+	MarketCapital float64 `db:"market_capital"`
+
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type IndexClose struct {
+	TradeDate time.Time `db:"trade_date" json:"trade_date"`
+	Close     float64   `db:"close_price" json:"close_price"`
+}
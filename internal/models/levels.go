@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// PriceZone = cluster swing pivot di harga yang berdekatan
+type PriceZone struct {
+	Kind      string    `json:"kind"` // support / resistance relatif ke close terakhir
+	Low       float64   `json:"low"`
+	High      float64   `json:"high"`
+	Mid       float64   `json:"mid"`
+	Touches   int       `json:"touches"`
+	Strength  float64   `json:"strength"` // 0-100: jumlah sentuhan, kebaruan, volume di pivot
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+type PivotPoints struct {
+	Method string  `json:"method"` // classic / fibonacci
+	P      float64 `json:"p"`
+	R1     float64 `json:"r1"`
+	R2     float64 `json:"r2"`
+	R3     float64 `json:"r3"`
+	S1     float64 `json:"s1"`
+	S2     float64 `json:"s2"`
+	S3     float64 `json:"s3"`
+}
+
+type StockLevels struct {
+	StockCode           string        `json:"stock_code"`
+	StockName           string        `json:"stock_name"`
+	AsOf                string        `json:"as_of"`
+	LastClose           float64       `json:"last_close"`
+	Zones               []PriceZone   `json:"zones"` // urut harga naik
+	NearestResistance   *PriceZone    `json:"nearest_resistance"`
+	NearestSupport      *PriceZone    `json:"nearest_support"`
+	DistToResistancePct *float64      `json:"dist_to_resistance_pct"` // ke batas bawah zona resistance
+	DistToSupportPct    *float64      `json:"dist_to_support_pct"`    // ke batas atas zona support
+	Pivots              []PivotPoints `json:"pivots"`                 // dari bar as_of, berlaku untuk sesi berikutnya
+}
+
+// ScreenerLevels = resistance/support pengganti LastRes20/LastSup20, 0 = nggak ada zona di sisi itu
+type ScreenerLevels struct {
+	Resistance float64
+	Support    float64
+}
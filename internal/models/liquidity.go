@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// LiquidityBar = kolom t_trading_summary yang dipakai hitung likuiditas
+type LiquidityBar struct {
+	StockCode       string    `db:"stock_code"`
+	StockName       string    `db:"stock_name"`
+	TradeDate       time.Time `db:"trade_date"`
+	Previous        float64   `db:"previous_price"`
+	Close           float64   `db:"close_price"`
+	Volume          float64   `db:"volume"`
+	Value           float64   `db:"value"`
+	Frequency       float64   `db:"frequency"`
+	TradeableShares float64   `db:"tradeable_shares"`
+}
+
+type StockLiquidity struct {
+	StockCode            string   `json:"stock_code"`
+	StockName            string   `json:"stock_name"`
+	Window               int      `json:"window"` // hari bursa
+	DaysTraded           int      `json:"days_traded"`
+	ZeroVolumeDays       int      `json:"zero_volume_days"` // termasuk hari tanpa data (suspend)
+	AvgValue             float64  `json:"avg_value"`        // ADV dalam Rupiah
+	MedianValue          float64  `json:"median_value"`
+	AvgVolume            float64  `json:"avg_volume"`
+	AvgFrequency         float64  `json:"avg_frequency"`
+	FreeFloatTurnoverPct *float64 `json:"free_float_turnover_pct"` // rata-rata volume / tradeable_shares per hari
+	Amihud               *float64 `json:"amihud"`                  // rata-rata |return %| per Rp1M transaksi, makin kecil makin likuid
+	LiquidityScore       float64  `json:"liquidity_score"`         // 0-100, percentile vs semua saham
+	Tier                 string   `json:"tier"`
+	PositionValue        float64  `json:"position_value"`
+	ParticipationPct     float64  `json:"participation_pct"`
+	DaysToEnter          *float64 `json:"days_to_enter"` // posisi / (ADV x participation)
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type StockMarketCap struct {
+	StockCode          string    `db:"stock_code" json:"stock_code"`
+	StockName          string    `db:"stock_name" json:"stock_name"`
+	TradeDate          time.Time `db:"trade_date" json:"trade_date"`
+	Close              float64   `db:"close_price" json:"close"`
+	ListedShares       float64   `db:"listed_shares" json:"listed_shares"` // fallback ke m_list_stocks.total_shares
+	TradeableShares    float64   `db:"tradeable_shares" json:"tradeable_shares"`
+	MarketCap          float64   `db:"-" json:"market_cap"`
+	FreeFloatPct       float64   `db:"-" json:"free_float_pct"`
+	FreeFloatMarketCap float64   `db:"-" json:"free_float_market_cap"`
+	SizeBucket         string    `db:"-" json:"size_bucket"` // big / mid / small / micro
+	Rank               int       `db:"-" json:"rank,omitempty"`
+}
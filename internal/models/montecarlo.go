@@ -0,0 +1,53 @@
+package models
+
+// MonteCarloConfig: sumber trade salah satu dari backtest (dijalankan dulu) atau run_id (trade tersimpan)
+type MonteCarloConfig struct {
+	Backtest             *BacktestConfig `json:"backtest,omitempty"`
+	RunID                *uint64         `json:"run_id,omitempty"`
+	Simulations          int             `json:"simulations"`             // default 1000
+	Seed                 *int64          `json:"seed"`                    // default 42, seed sama = hasil sama
+	Method               string          `json:"method"`                  // bootstrap | reshuffle | both (default)
+	InitialCapital       float64         `json:"initial_capital"`         // default dari portfolio / 100jt
+	PositionFractionPct  float64         `json:"position_fraction_pct"`   // % equity per trade, default 100/max_positions atau 10
+	RuinDrawdownPct      float64         `json:"ruin_drawdown_pct"`       // drawdown yang dianggap "bangkrut", default 50
+	LosingStreakAlertMin int             `json:"losing_streak_alert_min"` // hitung peluang losing streak >= N, default 5
+}
+
+type Percentiles struct {
+	P5   float64 `json:"p5"`
+	P25  float64 `json:"p25"`
+	P50  float64 `json:"p50"`
+	P75  float64 `json:"p75"`
+	P95  float64 `json:"p95"`
+	Mean float64 `json:"mean"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+}
+
+type PathStats struct {
+	FinalEquity     float64 `json:"final_equity"`
+	TotalReturnPct  float64 `json:"total_return_pct"`
+	MaxDrawdownPct  float64 `json:"max_drawdown_pct"`
+	MaxLosingStreak int     `json:"max_losing_streak"`
+}
+
+type MonteCarloDistribution struct {
+	Method            string      `json:"method"`
+	Simulations       int         `json:"simulations"`
+	FinalEquity       Percentiles `json:"final_equity"`
+	TotalReturnPct    Percentiles `json:"total_return_pct"`
+	MaxDrawdownPct    Percentiles `json:"max_drawdown_pct"`
+	MaxLosingStreak   Percentiles `json:"max_losing_streak"`
+	ProbLossPct       float64     `json:"prob_loss_pct"`        // % simulasi yang berakhir rugi
+	ProbRuinPct       float64     `json:"prob_ruin_pct"`        // % simulasi yang drawdown >= ruin_drawdown_pct
+	ProbLongStreakPct float64     `json:"prob_long_streak_pct"` // % simulasi dengan losing streak >= alert
+}
+
+type MonteCarloReport struct {
+	RunID         *uint64                  `json:"run_id"` // run sumber trade (tersimpan atau baru disimpan)
+	SaveError     string                   `json:"save_error,omitempty"`
+	Config        MonteCarloConfig         `json:"config"`
+	TotalTrades   int                      `json:"total_trades"`
+	Original      PathStats                `json:"original"` // urutan trade asli
+	Distributions []MonteCarloDistribution `json:"distributions"`
+}
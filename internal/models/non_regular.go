@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+type NonRegularRow struct {
+	StockCode           string    `db:"stock_code"`
+	StockName           string    `db:"stock_name"`
+	TradeDate           time.Time `db:"trade_date"`
+	Close               float64   `db:"close_price"`
+	Volume              float64   `db:"volume"`
+	Value               float64   `db:"value"`
+	NonRegularVolume    float64   `db:"non_regular_volume"`
+	NonRegularValue     float64   `db:"non_regular_value"`
+	NonRegularFrequency float64   `db:"non_regular_frequency"`
+}
+
+// NonRegularActivity = transaksi pasar negosiasi satu saham di satu hari dibanding histori
+type NonRegularActivity struct {
+	StockCode           string    `json:"stock_code"`
+	StockName           string    `json:"stock_name"`
+	TradeDate           time.Time `json:"trade_date"`
+	Close               float64   `json:"close"`
+	NonRegularVolume    float64   `json:"non_regular_volume"`
+	NonRegularValue     float64   `json:"non_regular_value"`
+	FormattedValue      string    `json:"formatted_non_regular_value"`
+	NonRegularFrequency float64   `json:"non_regular_frequency"`
+	NonRegularSharePct  float64   `json:"non_regular_share_pct"` // nego / value harian
+
+	CrossingPrice      *float64 `json:"crossing_price"`       // value / volume nego
+	CrossingPremiumPct *float64 `json:"crossing_premium_pct"` // vs close pasar reguler, + = di atas close
+
+	HistoryDays       int      `json:"history_days"`
+	ActiveDays        int      `json:"active_days"` // hari ada transaksi nego di histori
+	AvgHistoryValue   float64  `json:"avg_history_value"`
+	Ratio             *float64 `json:"ratio"` // value hari ini / rata-rata histori
+	ZScore            *float64 `json:"z_score"`
+	Unusual           bool     `json:"unusual"`
+	NextClose         *float64 `json:"next_close,omitempty"` // close hari bursa berikutnya (kalau sudah ada)
+	NextVsCrossingPct *float64 `json:"next_vs_crossing_pct,omitempty"`
+}
+
+type NonRegularScan struct {
+	AsOf        string               `json:"as_of"`
+	TradeDate   time.Time            `json:"trade_date"`
+	HistoryDays int                  `json:"history_days"`
+	MinRatio    float64              `json:"min_ratio"`
+	MinValue    float64              `json:"min_value"`
+	Total       int                  `json:"total"`
+	Data        []NonRegularActivity `json:"data"`
+}
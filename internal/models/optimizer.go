@@ -0,0 +1,58 @@
+package models
+
+type WalkForwardConfig struct {
+	InSampleDays  int `json:"in_sample_days"`  // hari bursa buat optimasi
+	OutSampleDays int `json:"out_sample_days"` // hari bursa buat validasi, sekaligus step geser window
+}
+
+type SweepConfig struct {
+	Base        BacktestConfig       `json:"base" binding:"required"`
+	Grid        map[string][]float64 `json:"grid" binding:"required"` // nama param -> kandidat nilai
+	Metric      string               `json:"metric"`                  // metrik ranking, lihat /backtest/sweep/metrics
+	Workers     int                  `json:"workers"`                 // default jumlah core
+	TopN        int                  `json:"top_n"`
+	WalkForward *WalkForwardConfig   `json:"walk_forward"`
+}
+
+type SweepResult struct {
+	RunID   *uint64          `json:"run_id,omitempty"` // cuma kombinasi terbaik yang disimpan sebagai run
+	Params  ScreenerParams   `json:"params"`
+	Score   *float64         `json:"score"` // null kalau metrik nggak terdefinisi (misal 0 trade)
+	Summary BacktestSummary  `json:"summary"`
+	Metrics *BacktestMetrics `json:"metrics,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+type WalkForwardWindow struct {
+	InSampleStart    string          `json:"in_sample_start"`
+	InSampleEnd      string          `json:"in_sample_end"`
+	OutSampleStart   string          `json:"out_sample_start"`
+	OutSampleEnd     string          `json:"out_sample_end"`
+	BestParams       ScreenerParams  `json:"best_params"`
+	InSampleScore    *float64        `json:"in_sample_score"`
+	OutSampleScore   *float64        `json:"out_sample_score"`
+	OutSampleSummary BacktestSummary `json:"out_sample_summary"`
+	OutSampleRunID   *uint64         `json:"out_sample_run_id,omitempty"`
+}
+
+type WalkForwardSummary struct {
+	Windows            int      `json:"windows"`
+	AvgInSampleScore   *float64 `json:"avg_in_sample_score"`
+	AvgOutSampleScore  *float64 `json:"avg_out_sample_score"`
+	Efficiency         *float64 `json:"efficiency"` // OOS / IS, jauh di bawah 1 = curiga overfit
+	OutSampleTrades    int      `json:"out_sample_trades"`
+	OutSampleNetPnL    float64  `json:"out_sample_net_pnl"`
+	OutSampleWinRate   float64  `json:"out_sample_win_rate"`
+	DistinctBestParams int      `json:"distinct_best_params"` // makin banyak = param makin nggak stabil
+}
+
+type SweepReport struct {
+	Screener     string              `json:"screener"`
+	Metric       string              `json:"metric"`
+	Combinations int                 `json:"combinations"`
+	Workers      int                 `json:"workers"`
+	Results      []SweepResult       `json:"results"`
+	WalkForward  []WalkForwardWindow `json:"walk_forward,omitempty"`
+	WFSummary    *WalkForwardSummary `json:"walk_forward_summary,omitempty"`
+	SaveErrors   []string            `json:"save_errors,omitempty"` // run yang gagal disimpan, hasil tetap dikirim
+}
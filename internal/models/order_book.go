@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// OrderBookRow = snapshot bid/offer terbaik di penutupan
+type OrderBookRow struct {
+	StockCode    string    `db:"stock_code"`
+	StockName    string    `db:"stock_name"`
+	TradeDate    time.Time `db:"trade_date"`
+	Previous     float64   `db:"previous_price"`
+	Close        float64   `db:"close_price"`
+	Bid          float64   `db:"bid_price"`
+	BidVolume    float64   `db:"bid_volume"`
+	Offer        float64   `db:"offer_price"`
+	OfferVolume  float64   `db:"offer_volume"`
+	ListingBoard string    `db:"listing_board"`
+}
+
+type OrderBookPressure struct {
+	StockCode   string    `json:"stock_code"`
+	StockName   string    `json:"stock_name"`
+	TradeDate   time.Time `json:"trade_date"`
+	Close       float64   `json:"close"`
+	Bid         float64   `json:"bid"`
+	BidVolume   float64   `json:"bid_volume"`
+	Offer       float64   `json:"offer"`
+	OfferVolume float64   `json:"offer_volume"`
+
+	ImbalancePct  float64  `json:"imbalance_pct"`   // (bid vol - offer vol) / total, -100..100
+	BidOfferRatio *float64 `json:"bid_offer_ratio"` // null kalau offer kosong
+	SpreadTicks   *int     `json:"spread_ticks"`    // null kalau salah satu sisi kosong
+	SpreadPct     *float64 `json:"spread_pct"`
+
+	OfferSwept bool   `json:"offer_swept"` // offer habis, bid masih antri
+	BidEmpty   bool   `json:"bid_empty"`   // bid kosong, offer numpuk
+	AtARA      bool   `json:"at_ara"`
+	AtARB      bool   `json:"at_arb"`
+	Status     string `json:"status"`
+}
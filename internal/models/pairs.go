@@ -0,0 +1,32 @@
+package models
+
+type PairStats struct {
+	StockA        string   `json:"stock_a"`
+	StockB        string   `json:"stock_b"`
+	Observations  int      `json:"observations"`
+	Correlation   float64  `json:"correlation"` // korelasi return harian
+	HedgeRatio    float64  `json:"hedge_ratio"` // ln(A) = intercept + hedge_ratio * ln(B)
+	Intercept     float64  `json:"intercept"`
+	ADFStat       float64  `json:"adf_stat"` // Dickey-Fuller t-stat residual Engle-Granger
+	Significance  float64  `json:"significance"`
+	CriticalValue float64  `json:"critical_value"`
+	Cointegrated  bool     `json:"cointegrated"`
+	HalfLifeDays  *float64 `json:"half_life_days"` // null kalau spread nggak mean reverting
+	SpreadMean    float64  `json:"spread_mean"`
+	SpreadStd     float64  `json:"spread_std"`
+	ZScore        float64  `json:"z_score"` // spread terakhir
+	Signal        string   `json:"signal"`
+}
+
+type PairSpreadPoint struct {
+	Date   string  `json:"date"`
+	Spread float64 `json:"spread"`
+	ZScore float64 `json:"z_score"`
+}
+
+type PairReport struct {
+	PairStats
+	StartDate string            `json:"start_date"`
+	EndDate   string            `json:"end_date"`
+	Spread    []PairSpreadPoint `json:"spread"`
+}
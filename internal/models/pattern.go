@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// PatternHit = satu pola yang terdeteksi di satu tanggal
+type PatternHit struct {
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"` // candle / chart
+	Bias      string    `json:"bias"` // bullish / bearish / neutral
+	Date      time.Time `json:"date"`
+	Confirmed bool      `json:"confirmed"` // chart pattern: sudah breakout neckline/range
+	Detail    string    `json:"detail,omitempty"`
+}
+
+type StockPatterns struct {
+	StockCode string       `json:"stock_code"`
+	StockName string       `json:"stock_name"`
+	AsOf      string       `json:"as_of"`
+	Latest    []PatternHit `json:"latest"`  // pola di bar terakhir <= as_of
+	History   []PatternHit `json:"history"` // pola N hari bursa terakhir, terbaru dulu
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type MarketRegime struct {
+	TradeDate     time.Time `json:"trade_date"`
+	Regime        string    `json:"regime"` // bull / bear / choppy
+	IndexCode     string    `json:"index_code"`
+	IndexClose    float64   `json:"index_close"`
+	MA50          float64   `json:"ma50"`
+	MA200         float64   `json:"ma200"`
+	TrendScore    int       `json:"trend_score"`              // -3..3 dari posisi close, MA50 vs MA200, slope MA50
+	VolatilityPct float64   `json:"volatility_pct"`           // volatilitas 20 hari IHSG, disetahunkan
+	VolPercentile float64   `json:"vol_percentile"`           // posisi volatilitas sekarang vs 1 tahun terakhir
+	BreadthRegime string    `json:"breadth_regime,omitempty"` // kosong kalau breadth hari itu belum dihitung
+	PctAboveMA50  *float64  `json:"pct_above_ma50,omitempty"`
+	Score         int       `json:"score"` // trend + breadth
+}
+
+// RegimeConfig = cara screener menyikapi regime pasar, di-bind dari query param
+// (?regime_action=downgrade&hostile_regime=bear,choppy)
+type RegimeConfig struct {
+	Action  string   `form:"regime_action" json:"regime_action,omitempty"` // kosong = cuma laporan, suppress / downgrade
+	Hostile []string `form:"hostile_regime" json:"hostile_regime,omitempty"`
+}
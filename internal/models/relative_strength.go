@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type RelativeStrength struct {
+	TradeDate     time.Time `db:"trade_date" json:"trade_date"`
+	StockCode     string    `db:"stock_code" json:"stock_code"`
+	StockName     string    `db:"stock_name" json:"stock_name"`
+	Close         float64   `db:"close_price" json:"close"`
+	Return3M      *float64  `db:"return_3m" json:"return_3m"`
+	Return6M      *float64  `db:"return_6m" json:"return_6m"`
+	Return12M     *float64  `db:"return_12m" json:"return_12m"`
+	WeightedScore float64   `db:"weighted_score" json:"weighted_score"`
+	RSRating      int       `db:"rs_rating" json:"rs_rating"`
+	RSLine        *float64  `db:"rs_line" json:"rs_line"`
+	RSLineNewHigh bool      `db:"rs_line_new_high" json:"rs_line_new_high"` // RS line di titik tertinggi 52 minggu
+}
+
+type RSComputeRequest struct {
+	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate   string `json:"end_date"`
+}
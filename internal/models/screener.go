@@ -0,0 +1,126 @@
+package models
+
+// ScreenerParams = threshold screener yang bisa di-override (buat backtest & parameter sweep)
+type ScreenerParams map[string]float64
+
+// Get ambil nilai param, fallback ke default kalau nggak di-set
+func (p ScreenerParams) Get(key string, def float64) float64 {
+	if v, ok := p[key]; ok {
+		return v
+	}
+	return def
+}
+
+// ScreenerFilter = filter tambahan yang dijalankan setelah query screener,
+// di-bind dari query param (?pattern=hammer,bull_flag&pattern_within=3)
+type ScreenerFilter struct {
+	Patterns      []string `form:"pattern" json:"pattern,omitempty"`
+	PatternWithin int      `form:"pattern_within" json:"pattern_within,omitempty"` // hari bursa ke belakang, default 1 (hari as_of saja)
+
+	// Jarak ke zona S/R (swing pivot cluster), dalam % dari close
+	WithLevels        bool     `form:"levels" json:"levels,omitempty"` // cuma anotasi, tanpa filter
+	MaxDistResistance *float64 `form:"max_dist_res" json:"max_dist_res,omitempty"`
+	MinDistResistance *float64 `form:"min_dist_res" json:"min_dist_res,omitempty"` // ruang naik minimal, lolos kalau nggak ada resistance
+	MaxDistSupport    *float64 `form:"max_dist_sup" json:"max_dist_sup,omitempty"`
+	// Sumber resistance/support buat label breakout/HAKA/BOW di top-accumulation & top-accumulation-eod:
+	// rolling (default, high/low 20 hari) | zones (zona swing pivot)
+	LevelSource string `form:"level_source" json:"level_source,omitempty"`
+
+	// Transaksi pasar nego nggak biasa (value >= ratio x rata-rata 60 hari)
+	MinNonRegularRatio *float64 `form:"min_nr_ratio" json:"min_nr_ratio,omitempty"`
+
+	// Tekanan bid/offer di penutupan
+	WithOrderBook         bool     `form:"order_book" json:"order_book,omitempty"` // cuma anotasi
+	MinOrderBookImbalance *float64 `form:"min_ob_imbalance" json:"min_ob_imbalance,omitempty"`
+
+	// RS rating 1-99 vs universe, RS line vs IHSG
+	MinRSRating   *int `form:"min_rs" json:"min_rs,omitempty"`
+	RSLineNewHigh bool `form:"rs_line_high" json:"rs_line_high,omitempty"`
+
+	// Likuiditas 20 hari bursa (ADV, skor 0-100, lama masuk posisi di participation % dari ADV)
+	MinADV            *float64 `form:"min_adv" json:"min_adv,omitempty"`
+	MinLiquidityScore *float64 `form:"min_liq_score" json:"min_liq_score,omitempty"`
+	MaxDaysToEnter    *float64 `form:"max_days_to_enter" json:"max_days_to_enter,omitempty"`
+	PositionValue     float64  `form:"position_value" json:"position_value,omitempty"` // default Rp1M
+	ParticipationPct  float64  `form:"participation" json:"participation,omitempty"`   // default 10% ADV
+
+	// Ukuran emiten: bucket big/mid/small/micro, market cap (Rp) dan free float (%)
+	SizeBuckets     []string `form:"size" json:"size,omitempty"`
+	MinMarketCap    *float64 `form:"min_mcap" json:"min_mcap,omitempty"`
+	MaxMarketCap    *float64 `form:"max_mcap" json:"max_mcap,omitempty"`
+	MinFreeFloatPct *float64 `form:"min_free_float" json:"min_free_float,omitempty"`
+
+	// Fundamental dari laporan yang sudah terbit per as_of (PER & PBV null = nggak lolos max_*)
+	MaxPER *float64 `form:"max_per" json:"max_per,omitempty"`
+	MaxPBV *float64 `form:"max_pbv" json:"max_pbv,omitempty"`
+	MinROE *float64 `form:"min_roe" json:"min_roe,omitempty"`
+	MaxDER *float64 `form:"max_der" json:"max_der,omitempty"`
+
+	// Gate regime pasar dari breadth (?breadth_regime=risk_on,neutral), kalau nggak masuk semua sinyal dibuang
+	BreadthRegimes []string `form:"breadth_regime" json:"breadth_regime,omitempty"`
+}
+
+func (f ScreenerFilter) Active() bool {
+	return len(f.Patterns) > 0 || f.LevelsActive() || f.MinNonRegularRatio != nil || f.OrderBookActive() || f.RSActive() || f.LiquidityActive() || f.MarketCapActive() || f.FundamentalsActive() || len(f.BreadthRegimes) > 0
+}
+
+func (f ScreenerFilter) FundamentalsActive() bool {
+	return f.MaxPER != nil || f.MaxPBV != nil || f.MinROE != nil || f.MaxDER != nil
+}
+
+func (f ScreenerFilter) MarketCapActive() bool {
+	return len(f.SizeBuckets) > 0 || f.MinMarketCap != nil || f.MaxMarketCap != nil || f.MinFreeFloatPct != nil
+}
+
+func (f ScreenerFilter) LiquidityActive() bool {
+	return f.MinADV != nil || f.MinLiquidityScore != nil || f.MaxDaysToEnter != nil
+}
+
+func (f ScreenerFilter) RSActive() bool {
+	return f.MinRSRating != nil || f.RSLineNewHigh
+}
+
+func (f ScreenerFilter) OrderBookActive() bool {
+	return f.WithOrderBook || f.MinOrderBookImbalance != nil
+}
+
+func (f ScreenerFilter) LevelsActive() bool {
+	return f.WithLevels || f.MaxDistResistance != nil || f.MinDistResistance != nil || f.MaxDistSupport != nil
+}
+
+// ScreenerAnnotation = info tambahan per saham hasil filter
+type ScreenerAnnotation struct {
+	Patterns []string `json:"patterns,omitempty"`
+
+	Resistance          *float64 `json:"resistance,omitempty"` // mid zona resistance terdekat
+	Support             *float64 `json:"support,omitempty"`
+	DistToResistancePct *float64 `json:"dist_to_resistance_pct,omitempty"`
+	DistToSupportPct    *float64 `json:"dist_to_support_pct,omitempty"`
+
+	NonRegularValue    *float64 `json:"non_regular_value,omitempty"`
+	NonRegularRatio    *float64 `json:"non_regular_ratio,omitempty"`
+	CrossingPrice      *float64 `json:"crossing_price,omitempty"`
+	CrossingPremiumPct *float64 `json:"crossing_premium_pct,omitempty"`
+
+	OrderBookImbalancePct *float64 `json:"order_book_imbalance_pct,omitempty"`
+	SpreadTicks           *int     `json:"spread_ticks,omitempty"`
+	OrderBookStatus       string   `json:"order_book_status,omitempty"`
+
+	RSRating      *int  `json:"rs_rating,omitempty"`
+	RSLineNewHigh *bool `json:"rs_line_new_high,omitempty"`
+
+	AvgValue       *float64 `json:"avg_value,omitempty"`
+	LiquidityScore *float64 `json:"liquidity_score,omitempty"`
+	DaysToEnter    *float64 `json:"days_to_enter,omitempty"`
+
+	MarketCap    *float64 `json:"market_cap,omitempty"`
+	FreeFloatPct *float64 `json:"free_float_pct,omitempty"`
+	SizeBucket   string   `json:"size_bucket,omitempty"`
+
+	PER *float64 `json:"per,omitempty"`
+	PBV *float64 `json:"pbv,omitempty"`
+	ROE *float64 `json:"roe,omitempty"`
+	DER *float64 `json:"der,omitempty"`
+
+	BreadthRegime string `json:"breadth_regime,omitempty"` // risk_on / neutral / risk_off, beda dengan regime bull/bear/choppy
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+type StockSector struct {
+	StockCode string `db:"stock_code" json:"stock_code" binding:"required"`
+	Sector    string `db:"sector" json:"sector" binding:"required"`
+	SubSector string `db:"sub_sector" json:"sub_sector"`
+}
+
+type SectorUploadRequest struct {
+	Replace bool          `json:"replace"` // true = hapus mapping lama dulu
+	Data    []StockSector `json:"data" binding:"required,min=1,dive"`
+}
+
+// SectorBar = bar harian saham yang sudah ada sektornya
+type SectorBar struct {
+	StockCode   string    `db:"stock_code"`
+	Sector      string    `db:"sector"`
+	TradeDate   time.Time `db:"trade_date"`
+	Previous    float64   `db:"previous_price"`
+	Close       float64   `db:"close_price"`
+	Volume      float64   `db:"volume"`
+	Value       float64   `db:"value"`
+	ForeignBuy  float64   `db:"foreign_buy"`
+	ForeignSell float64   `db:"foreign_sell"`
+}
+
+type SectorHorizonStats struct {
+	Horizon           string   `json:"horizon"` // 1W / 1M / 3M
+	Days              int      `json:"days"`
+	ReturnPct         float64  `json:"return_pct"` // value-weighted
+	EqualWeightReturn float64  `json:"equal_weight_return_pct"`
+	Advancers         int      `json:"advancers"`
+	Decliners         int      `json:"decliners"`
+	BreadthPct        float64  `json:"breadth_pct"` // % saham naik di periode ini
+	NetForeignVal     float64  `json:"net_foreign_val"`
+	FormattedForeign  string   `json:"formatted_net_foreign"`
+	VolumeExpansion   *float64 `json:"volume_expansion"` // rata-rata value harian vs 60 hari sebelumnya
+	Rank              int      `json:"rank"`
+}
+
+type SectorRotation struct {
+	Sector     string               `json:"sector"`
+	Stocks     int                  `json:"stocks"`
+	Horizons   []SectorHorizonStats `json:"horizons"`
+	RSRatio    *float64             `json:"rs_ratio"`    // > 100 = lebih kuat dari benchmark
+	RSMomentum *float64             `json:"rs_momentum"` // > 100 = RS-Ratio lagi naik
+	Quadrant   string               `json:"quadrant"`    // leading / weakening / lagging / improving
+	Rank       int                  `json:"rank"`
+}
+
+type SectorRotationReport struct {
+	AsOf      string           `json:"as_of"`
+	TradeDate time.Time        `json:"trade_date"`
+	Benchmark string           `json:"benchmark"`
+	RankBy    string           `json:"rank_by"`
+	Sectors   []SectorRotation `json:"sectors"`
+}
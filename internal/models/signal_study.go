@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+type SignalStudyConfig struct {
+	Screener      string         `json:"screener" binding:"required"`
+	Params        ScreenerParams `json:"params"`
+	StartDate     string         `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate       string         `json:"end_date" binding:"required"`   // YYYY-MM-DD
+	Horizons      []int          `json:"horizons"`                      // hari bursa, default 1,3,5,10,20
+	IncludeEvents bool           `json:"include_events"`                // sertakan detail tiap sinyal
+}
+
+// HorizonStats = statistik forward return di satu horizon
+type HorizonStats struct {
+	Horizon      int     `json:"horizon"`
+	Samples      int     `json:"samples"`
+	AvgReturnPct float64 `json:"avg_return_pct"`
+	MedReturnPct float64 `json:"median_return_pct"`
+	HitRatePct   float64 `json:"hit_rate_pct"` // % sinyal yang return > 0
+	AvgMFEPct    float64 `json:"avg_mfe_pct"`  // maximum favorable excursion
+	AvgMAEPct    float64 `json:"avg_mae_pct"`  // maximum adverse excursion
+	BestPct      float64 `json:"best_pct"`
+	WorstPct     float64 `json:"worst_pct"`
+}
+
+type CategoryStudy struct {
+	Category string         `json:"category"`
+	Signals  int            `json:"signals"`
+	Horizons []HorizonStats `json:"horizons"`
+}
+
+type ForwardReturn struct {
+	Horizon   int     `json:"horizon"`
+	ReturnPct float64 `json:"return_pct"`
+	MFEPct    float64 `json:"mfe_pct"`
+	MAEPct    float64 `json:"mae_pct"`
+}
+
+type SignalEvent struct {
+	StockCode     string          `json:"stock_code"`
+	SignalDate    time.Time       `json:"signal_date"`
+	Category      string          `json:"category"`
+	DisplayStatus string          `json:"display_status"`
+	EntryPrice    float64         `json:"entry_price"`
+	Forward       []ForwardReturn `json:"forward"`
+}
+
+type SignalStudyReport struct {
+	Config       SignalStudyConfig `json:"config"`
+	SignalDays   int               `json:"signal_days"`
+	TotalSignals int               `json:"total_signals"`
+	Overall      CategoryStudy     `json:"overall"`
+	Categories   []CategoryStudy   `json:"categories"`
+	Events       []SignalEvent     `json:"events,omitempty"`
+}
@@ -153,6 +153,8 @@ type TopSwinger struct {
 	VolChangePct  float64 `db:"vol_change_pct" json:"vol_change_pct"`
 	SwingScore    float64 `db:"swing_score" json:"swing_score"`
 	EntryPrice    float64 `db:"entry_price" json:"entry_price"`
+	ATR14         float64 `db:"atr14" json:"atr14"`
+	ATRPct        float64 `db:"atr_pct" json:"atr_pct"`
 	StopLoss      float64 `db:"stop_loss" json:"stop_loss"`
 	TakeProfit    float64 `db:"take_profit" json:"take_profit"`
 	VolMultiplier float64 `db:"vol_multiplier" json:"vol_multiplier"`
@@ -233,5 +235,5 @@ type StatisticSingleStock struct {
 
 type StatisticSingleStockMapped struct {
 	StockCode string                 `db:"code" json:"Stock Code"`
-	Details   []StatisticSingleStock `json:details`
+	Details   []StatisticSingleStock `json:"Details"`
 }
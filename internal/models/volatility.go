@@ -0,0 +1,22 @@
+package models
+
+type StockVolatility struct {
+	StockCode     string  `json:"stock_code"`
+	TradeDate     string  `json:"trade_date"`
+	Close         float64 `json:"close"`
+	HV20          float64 `json:"hv20"` // volatilitas historis 20 hari, % disetahunkan
+	HV60          float64 `json:"hv60"`
+	ATR14         float64 `json:"atr14"`
+	ATRPct        float64 `json:"atr_pct"` // ATR14 / close
+	BBUpper       float64 `json:"bb_upper"`
+	BBMiddle      float64 `json:"bb_middle"`
+	BBLower       float64 `json:"bb_lower"`
+	BBBandwidth   float64 `json:"bb_bandwidth"`   // (upper - lower) / middle, %
+	BBWPercentile float64 `json:"bbw_percentile"` // posisi bandwidth sekarang vs 120 hari bursa
+	Squeeze       bool    `json:"squeeze"`
+	SqueezeDays   int     `json:"squeeze_days"` // berapa hari berturut-turut dalam squeeze
+	Released      bool    `json:"released"`     // kemarin squeeze, hari ini bandwidth keluar
+	StopLoss      float64 `json:"stop_loss"`    // close - 2 ATR, dibulatkan ke fraksi
+	TakeProfit    float64 `json:"take_profit"`  // close + 3 ATR
+	AvgValue20    float64 `json:"avg_value_20"`
+}
@@ -0,0 +1,39 @@
+package models
+
+type VolumeProfileBin struct {
+	PriceLow    float64 `json:"price_low"`
+	PriceHigh   float64 `json:"price_high"`
+	Volume      float64 `json:"volume"`
+	Pct         float64 `json:"pct"`
+	InValueArea bool    `json:"in_value_area"`
+}
+
+type VolumeProfile struct {
+	StockCode         string             `json:"stock_code"`
+	StartDate         string             `json:"start_date"`
+	EndDate           string             `json:"end_date"`
+	Bars              int                `json:"bars"`
+	Close             float64            `json:"close"`
+	POC               float64            `json:"poc"` // harga tengah bin dengan volume terbesar
+	VAH               float64            `json:"vah"` // batas atas value area (70% volume)
+	VAL               float64            `json:"val"`
+	ClosePosition     string             `json:"close_position"`      // above_value / in_value / below_value
+	OverheadSupplyPct float64            `json:"overhead_supply_pct"` // % volume di atas close = supply yang harus ditembus
+	Bins              []VolumeProfileBin `json:"bins"`
+}
+
+type AVWAPPoint struct {
+	Date  string  `json:"date"`
+	VWAP  float64 `json:"vwap"`
+	Close float64 `json:"close"`
+}
+
+type AnchoredVWAP struct {
+	Anchor      string       `json:"anchor"` // ipo / 52w_low / 52w_high / tanggal
+	AnchorDate  string       `json:"anchor_date"`
+	Truncated   bool         `json:"truncated"` // data mulai setelah tanggal anchor (mis. IPO sebelum data tersimpan)
+	VWAP        float64      `json:"vwap"`
+	Close       float64      `json:"close"`
+	DistancePct float64      `json:"distance_pct"` // close vs VWAP
+	Series      []AVWAPPoint `json:"series"`
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/database"
+	"indonesia-stocks-api/internal/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// GetTradingDates = list hari bursa (yang ada datanya) di antara start dan end
+func GetTradingDates(startDate, endDate string) ([]time.Time, error) {
+	query := `
+		SELECT DISTINCT trade_date
+		FROM t_trading_summary
+		WHERE trade_date BETWEEN ? AND ?
+		ORDER BY trade_date`
+
+	dates := []time.Time{}
+	err := database.DB.Select(&dates, query, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return dates, nil
+}
+
+// GetDailyBars ambil OHLCV semua saham di range tanggal, urut per saham lalu tanggal
+func GetDailyBars(startDate, endDate string) ([]models.DailyBar, error) {
+	query := `
+		SELECT
+			t.stock_code, t.stock_name, t.trade_date,
+			t.previous_price, t.open_price, t.high_price, t.low_price, t.close_price,
+			t.volume, t.value, t.foreign_buy, t.foreign_sell, t.close_strength, t.frequency,
+			COALESCE(m.listing_board, '') AS listing_board
+		FROM t_trading_summary t
+		LEFT JOIN m_list_stocks m ON m.stock_code = t.stock_code
+		WHERE t.trade_date BETWEEN ? AND ?
+		ORDER BY t.stock_code, t.trade_date`
+
+	rows := []models.DailyBar{}
+	err := database.DB.Select(&rows, query, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetStockBars = GetDailyBars tapi cuma untuk saham tertentu
+func GetStockBars(stockCodes []string, startDate, endDate string) ([]models.DailyBar, error) {
+	if len(stockCodes) == 0 {
+		return []models.DailyBar{}, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT
+			t.stock_code, t.stock_name, t.trade_date,
+			t.previous_price, t.open_price, t.high_price, t.low_price, t.close_price,
+			t.volume, t.value, t.foreign_buy, t.foreign_sell, t.close_strength, t.frequency,
+			COALESCE(m.listing_board, '') AS listing_board
+		FROM t_trading_summary t
+		LEFT JOIN m_list_stocks m ON m.stock_code = t.stock_code
+		WHERE t.stock_code IN (?)
+		  AND t.trade_date BETWEEN ? AND ?
+		ORDER BY t.stock_code, t.trade_date`, stockCodes, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []models.DailyBar{}
+	err = database.DB.Select(&rows, database.DB.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetLatestTradingDate = hari bursa terakhir yang ada datanya <= as_of
+func GetLatestTradingDate(asOf string) (time.Time, error) {
+	var date *time.Time
+	err := database.DB.Get(&date, `SELECT MAX(trade_date) FROM t_trading_summary WHERE trade_date <= ?`, asOf)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if date == nil {
+		return time.Time{}, fmt.Errorf("no trading data up to %s", asOf)
+	}
+	return *date, nil
+}
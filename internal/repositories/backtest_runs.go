@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"indonesia-stocks-api/internal/database"
+	"indonesia-stocks-api/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Batas baris per batch insert, biar jumlah placeholder nggak lewat limit MySQL (65535)
+const backtestInsertBatch = 1000
+
+// SaveBacktestRun simpan header run + trades + equity curve dalam satu transaksi
+func SaveBacktestRun(run models.BacktestRun, trades []models.BacktestTrade, curve []models.EquityPoint) (uint64, error) {
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		INSERT INTO t_backtest_run (
+			screener, config_json, code_version, data_start, data_end, data_hash,
+			summary_json, metrics_json, total_trades, rerun_of, duration_ms, created_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`,
+		run.Screener, run.ConfigJSON, run.CodeVersion, run.DataStart, run.DataEnd, run.DataHash,
+		run.SummaryJSON, run.MetricsJSON, run.TotalTrades, run.RerunOf, run.DurationMs,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	runID := uint64(id)
+
+	tradeRows := make([]models.BacktestTradeDB, 0, len(trades))
+	for _, t := range trades {
+		tradeRows = append(tradeRows, models.BacktestTradeDB{RunID: runID, BacktestTrade: t})
+	}
+	if err := insertBacktestTrades(tx, tradeRows); err != nil {
+		return 0, err
+	}
+
+	equityRows := make([]models.BacktestEquityDB, 0, len(curve))
+	for _, p := range curve {
+		equityRows = append(equityRows, models.BacktestEquityDB{RunID: runID, EquityPoint: p})
+	}
+	if err := insertBacktestEquity(tx, equityRows); err != nil {
+		return 0, err
+	}
+
+	return runID, tx.Commit()
+}
+
+func insertBacktestTrades(tx *sqlx.Tx, rows []models.BacktestTradeDB) error {
+	query := `
+	INSERT INTO t_backtest_trade (
+		run_id, stock_code, stock_name, signal_date, signal_status,
+		entry_date, entry_price, exit_date, exit_price, exit_reason,
+		holding_days, return_pct, stop_loss_level, take_profit_level,
+		lots, buy_fee, sell_fee, net_pnl, net_return_pct
+	)
+	VALUES (
+		:run_id, :stock_code, :stock_name, :signal_date, :signal_status,
+		:entry_date, :entry_price, :exit_date, :exit_price, :exit_reason,
+		:holding_days, :return_pct, :stop_loss_level, :take_profit_level,
+		:lots, :buy_fee, :sell_fee, :net_pnl, :net_return_pct
+	)`
+
+	for start := 0; start < len(rows); start += backtestInsertBatch {
+		end := min(start+backtestInsertBatch, len(rows))
+		if _, err := tx.NamedExec(query, rows[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertBacktestEquity(tx *sqlx.Tx, rows []models.BacktestEquityDB) error {
+	query := `
+	INSERT INTO t_backtest_equity (
+		run_id, trade_date, cash, positions_value, equity, open_positions, daily_return_pct
+	)
+	VALUES (
+		:run_id, :trade_date, :cash, :positions_value, :equity, :open_positions, :daily_return_pct
+	)`
+
+	for start := 0; start < len(rows); start += backtestInsertBatch {
+		end := min(start+backtestInsertBatch, len(rows))
+		if _, err := tx.NamedExec(query, rows[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const backtestRunColumns = `
+	id, screener, config_json, code_version, data_start, data_end, data_hash,
+	summary_json, metrics_json, total_trades, rerun_of, duration_ms, created_at`
+
+// ListBacktestRuns = run terbaru dulu, screener kosong = semua
+func ListBacktestRuns(screener string, limit, offset int) ([]models.BacktestRun, error) {
+	query := `SELECT ` + backtestRunColumns + `
+		FROM t_backtest_run
+		WHERE (? = '' OR screener = ?)
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?`
+
+	rows := []models.BacktestRun{}
+	err := database.DB.Select(&rows, query, screener, screener, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetBacktestRun balikin nil kalau id nggak ada
+func GetBacktestRun(id uint64) (*models.BacktestRun, error) {
+	query := `SELECT ` + backtestRunColumns + `
+		FROM t_backtest_run
+		WHERE id = ?`
+
+	rows := []models.BacktestRun{}
+	if err := database.DB.Select(&rows, query, id); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	return &rows[0], nil
+}
+
+func GetBacktestTrades(runID uint64) ([]models.BacktestTrade, error) {
+	query := `
+		SELECT
+			stock_code, stock_name, signal_date, signal_status,
+			entry_date, entry_price, exit_date, exit_price, exit_reason,
+			holding_days, return_pct, stop_loss_level, take_profit_level,
+			lots, buy_fee, sell_fee, net_pnl, net_return_pct
+		FROM t_backtest_trade
+		WHERE run_id = ?
+		ORDER BY id`
+
+	rows := []models.BacktestTrade{}
+	err := database.DB.Select(&rows, query, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func GetBacktestEquity(runID uint64) ([]models.EquityPoint, error) {
+	query := `
+		SELECT trade_date, cash, positions_value, equity, open_positions, daily_return_pct
+		FROM t_backtest_equity
+		WHERE run_id = ?
+		ORDER BY trade_date`
+
+	rows := []models.EquityPoint{}
+	err := database.DB.Select(&rows, query, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"indonesia-stocks-api/internal/database"
+	"indonesia-stocks-api/internal/models"
+)
+
+func UpsertMarketBreadth(rows []models.MarketBreadth) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	query := `
+	INSERT INTO t_market_breadth (
+		trade_date, total_stocks, advancers, decliners, unchanged, ad_line,
+		new_highs_52w, new_lows_52w, pct_above_ma20, pct_above_ma50, pct_above_ma200,
+		ema19, ema39, mcclellan_osc, mcclellan_sum, ara_count, arb_count, created_at
+	)
+	VALUES (
+		:trade_date, :total_stocks, :advancers, :decliners, :unchanged, :ad_line,
+		:new_highs_52w, :new_lows_52w, :pct_above_ma20, :pct_above_ma50, :pct_above_ma200,
+		:ema19, :ema39, :mcclellan_osc, :mcclellan_sum, :ara_count, :arb_count, NOW()
+	)
+	ON DUPLICATE KEY UPDATE
+		total_stocks = VALUES(total_stocks),
+		advancers = VALUES(advancers),
+		decliners = VALUES(decliners),
+		unchanged = VALUES(unchanged),
+		ad_line = VALUES(ad_line),
+		new_highs_52w = VALUES(new_highs_52w),
+		new_lows_52w = VALUES(new_lows_52w),
+		pct_above_ma20 = VALUES(pct_above_ma20),
+		pct_above_ma50 = VALUES(pct_above_ma50),
+		pct_above_ma200 = VALUES(pct_above_ma200),
+		ema19 = VALUES(ema19),
+		ema39 = VALUES(ema39),
+		mcclellan_osc = VALUES(mcclellan_osc),
+		mcclellan_sum = VALUES(mcclellan_sum),
+		ara_count = VALUES(ara_count),
+		arb_count = VALUES(arb_count)
+	`
+
+	_, err := database.DB.NamedExec(query, rows)
+	return err
+}
+
+const marketBreadthColumns = `
+	trade_date, total_stocks, advancers, decliners, unchanged, ad_line,
+	new_highs_52w, new_lows_52w, pct_above_ma20, pct_above_ma50, pct_above_ma200,
+	ema19, ema39, mcclellan_osc, mcclellan_sum, ara_count, arb_count`
+
+func GetMarketBreadth(startDate, endDate string) ([]models.MarketBreadth, error) {
+	query := `
+		SELECT ` + marketBreadthColumns + `
+		FROM t_market_breadth
+		WHERE trade_date BETWEEN ? AND ?
+		ORDER BY trade_date`
+
+	rows := []models.MarketBreadth{}
+	err := database.DB.Select(&rows, query, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetLastMarketBreadth = baris breadth terakhir sebelum tanggal tertentu, nil kalau belum ada
+func GetLastMarketBreadth(before string) (*models.MarketBreadth, error) {
+	query := `
+		SELECT ` + marketBreadthColumns + `
+		FROM t_market_breadth
+		WHERE trade_date < ?
+		ORDER BY trade_date DESC
+		LIMIT 1`
+
+	rows := []models.MarketBreadth{}
+	if err := database.DB.Select(&rows, query, before); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	return &rows[0], nil
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"indonesia-stocks-api/internal/database"
+	"indonesia-stocks-api/internal/models"
+)
+
+// GetStockForeignFlow = data foreign satu saham, N hari bursa terakhir sampai as_of
+func GetStockForeignFlow(stockCode, asOf string, days int) ([]models.ForeignFlowRow, error) {
+	query := `
+		SELECT * FROM (
+			SELECT stock_code, stock_name, trade_date, close_price, volume, value, foreign_buy, foreign_sell
+			FROM t_trading_summary
+			WHERE stock_code = ?
+			  AND trade_date <= ?
+			ORDER BY trade_date DESC
+			LIMIT ?
+		) x
+		ORDER BY trade_date`
+
+	rows := []models.ForeignFlowRow{}
+	err := database.DB.Select(&rows, query, stockCode, asOf, days)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetMarketForeignFlow = data foreign semua saham di N hari bursa terakhir sampai as_of
+func GetMarketForeignFlow(asOf string, days int) ([]models.ForeignFlowRow, error) {
+	query := `
+		WITH Dates AS (
+			SELECT DISTINCT trade_date
+			FROM t_trading_summary
+			WHERE trade_date <= ?
+			ORDER BY trade_date DESC
+			LIMIT ?
+		)
+		SELECT t.stock_code, t.stock_name, t.trade_date, t.close_price, t.volume, t.value, t.foreign_buy, t.foreign_sell
+		FROM t_trading_summary t
+		JOIN Dates d ON d.trade_date = t.trade_date
+		ORDER BY t.stock_code, t.trade_date`
+
+	rows := []models.ForeignFlowRow{}
+	err := database.DB.Select(&rows, query, asOf, days)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
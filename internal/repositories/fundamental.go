@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"indonesia-stocks-api/internal/database"
+	"indonesia-stocks-api/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func UpsertFinancialSummaries(rows []models.FinancialSummary) error {
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT INTO t_financial_summary (
+		stock_code, fiscal_year, fiscal_quarter, period_end, published_date,
+		revenue, net_income, total_equity, eps, der, source, updated_at
+	)
+	VALUES (
+		:stock_code, :fiscal_year, :fiscal_quarter, :period_end, :published_date,
+		:revenue, :net_income, :total_equity, :eps, :der, :source, NOW()
+	)
+	ON DUPLICATE KEY UPDATE
+		period_end = VALUES(period_end),
+		published_date = VALUES(published_date),
+		revenue = VALUES(revenue),
+		net_income = VALUES(net_income),
+		total_equity = VALUES(total_equity),
+		eps = VALUES(eps),
+		der = VALUES(der),
+		source = VALUES(source),
+		updated_at = NOW()
+	`
+
+	for start := 0; start < len(rows); start += backtestInsertBatch {
+		end := min(start+backtestInsertBatch, len(rows))
+		if _, err := tx.NamedExec(query, rows[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+const financialColumns = `
+	stock_code, fiscal_year, fiscal_quarter, period_end, published_date,
+	revenue, net_income, total_equity, eps, der, source`
+
+// GetPublishedFinancials = laporan yang sudah terbit <= as_of, urut per saham lalu periode.
+// codes kosong = semua saham.
+func GetPublishedFinancials(stockCodes []string, asOf string) ([]models.FinancialSummary, error) {
+	query := `
+		SELECT ` + financialColumns + `
+		FROM t_financial_summary
+		WHERE published_date <= ?`
+	args := []any{asOf}
+
+	if len(stockCodes) > 0 {
+		query += ` AND stock_code IN (?)`
+		args = append(args, stockCodes)
+	}
+	query += ` ORDER BY stock_code, period_end`
+
+	query, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []models.FinancialSummary{}
+	err = database.DB.Select(&rows, database.DB.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func GetStockFinancials(stockCode string) ([]models.FinancialSummary, error) {
+	rows := []models.FinancialSummary{}
+	err := database.DB.Select(&rows, `
+		SELECT `+financialColumns+`
+		FROM t_financial_summary
+		WHERE stock_code = ?
+		ORDER BY period_end DESC`, stockCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"indonesia-stocks-api/internal/database"
+	"indonesia-stocks-api/internal/models"
+)
+
+func InsertIndexSummary(summaries []models.IndexSummaryDB) error {
+	query := `
+	INSERT INTO t_index_summary (
+		trade_date,
+		index_code,
+		previous_price,
+		high_price,
+		low_price,
+		close_price,
+		change_price,
+		volume,
+		value,
+		frequency,
+		market_capital,
+		created_at,
+		updated_at
+	)
+	VALUES (
+		:trade_date,
+		:index_code,
+		:previous_price,
+		:high_price,
+		:low_price,
+		:close_price,
+		:change_price,
+		:volume,
+		:value,
+		:frequency,
+		:market_capital,
+		:created_at,
+		:updated_at
+	)
+	ON DUPLICATE KEY UPDATE
+		previous_price = VALUES(previous_price),
+		high_price = VALUES(high_price),
+		low_price = VALUES(low_price),
+		close_price = VALUES(close_price),
+		change_price = VALUES(change_price),
+		volume = VALUES(volume),
+		value = VALUES(value),
+		frequency = VALUES(frequency),
+		market_capital = VALUES(market_capital),
+		updated_at = NOW()
+	`
+
+	_, err := database.DB.NamedExec(query, summaries)
+	return err
+}
+
+func GetIndexCloses(indexCode, startDate, endDate string) ([]models.IndexClose, error) {
+	query := `
+		SELECT trade_date, close_price
+		FROM t_index_summary
+		WHERE index_code = ?
+		  AND trade_date BETWEEN ? AND ?
+		ORDER BY trade_date`
+
+	rows := []models.IndexClose{}
+	err := database.DB.Select(&rows, query, indexCode, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
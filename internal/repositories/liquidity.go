@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"indonesia-stocks-api/internal/database"
+	"indonesia-stocks-api/internal/models"
+)
+
+// GetLiquidityBars = bar semua saham di range tanggal, urut per saham lalu tanggal
+func GetLiquidityBars(startDate, endDate string) ([]models.LiquidityBar, error) {
+	query := `
+		SELECT
+			stock_code, stock_name, trade_date, previous_price, close_price,
+			volume, value, frequency, tradeable_shares
+		FROM t_trading_summary
+		WHERE trade_date BETWEEN ? AND ?
+		ORDER BY stock_code, trade_date`
+
+	rows := []models.LiquidityBar{}
+	err := database.DB.Select(&rows, query, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"indonesia-stocks-api/internal/database"
+	"indonesia-stocks-api/internal/models"
+)
+
+const marketCapSelect = `
+		SELECT
+			t.stock_code, t.stock_name, t.trade_date, t.close_price,
+			COALESCE(NULLIF(t.listed_shares, 0), m.total_shares, 0) AS listed_shares,
+			t.tradeable_shares
+		FROM t_trading_summary t
+		LEFT JOIN m_list_stocks m ON m.stock_code = t.stock_code`
+
+// GetMarketCapsByDate = jumlah saham & harga semua emiten di satu hari bursa
+func GetMarketCapsByDate(tradeDate string) ([]models.StockMarketCap, error) {
+	query := marketCapSelect + `
+		WHERE t.trade_date = ?
+		ORDER BY t.stock_code`
+
+	rows := []models.StockMarketCap{}
+	err := database.DB.Select(&rows, query, tradeDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func GetStockMarketCaps(stockCode, startDate, endDate string) ([]models.StockMarketCap, error) {
+	query := marketCapSelect + `
+		WHERE t.stock_code = ?
+		  AND t.trade_date BETWEEN ? AND ?
+		ORDER BY t.trade_date`
+
+	rows := []models.StockMarketCap{}
+	err := database.DB.Select(&rows, query, stockCode, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"indonesia-stocks-api/internal/database"
+	"indonesia-stocks-api/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// GetNonRegularHistory = data pasar reguler + nego N hari bursa terakhir sampai as_of.
+// stockCodes kosong = semua saham.
+func GetNonRegularHistory(asOf string, days int, stockCodes []string) ([]models.NonRegularRow, error) {
+	query := `
+		WITH Dates AS (
+			SELECT DISTINCT trade_date
+			FROM t_trading_summary
+			WHERE trade_date <= ?
+			ORDER BY trade_date DESC
+			LIMIT ?
+		)
+		SELECT
+			t.stock_code, t.stock_name, t.trade_date, t.close_price, t.volume, t.value,
+			t.non_regular_volume, t.non_regular_value, t.non_regular_frequency
+		FROM t_trading_summary t
+		JOIN Dates d ON d.trade_date = t.trade_date`
+	args := []any{asOf, days}
+
+	if len(stockCodes) > 0 {
+		query += `
+		WHERE t.stock_code IN (?)`
+		args = append(args, stockCodes)
+	}
+	query += `
+		ORDER BY t.stock_code, t.trade_date`
+
+	query, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []models.NonRegularRow{}
+	err = database.DB.Select(&rows, database.DB.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetStockNonRegular = histori nego satu saham dari start sampai end
+func GetStockNonRegular(stockCode, startDate, endDate string) ([]models.NonRegularRow, error) {
+	query := `
+		SELECT
+			stock_code, stock_name, trade_date, close_price, volume, value,
+			non_regular_volume, non_regular_value, non_regular_frequency
+		FROM t_trading_summary
+		WHERE stock_code = ?
+		  AND trade_date BETWEEN ? AND ?
+		ORDER BY trade_date`
+
+	rows := []models.NonRegularRow{}
+	err := database.DB.Select(&rows, query, stockCode, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
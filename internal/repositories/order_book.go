@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"indonesia-stocks-api/internal/database"
+	"indonesia-stocks-api/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const orderBookColumns = `
+	t.stock_code, t.stock_name, t.trade_date, t.previous_price, t.close_price,
+	t.bid_price, t.bid_volume, t.offer_price, t.offer_volume,
+	COALESCE(m.listing_board, '') AS listing_board`
+
+// GetOrderBookAsOf = snapshot bid/offer semua saham (atau saham tertentu) di hari bursa terakhir <= as_of
+func GetOrderBookAsOf(asOf string, stockCodes []string) ([]models.OrderBookRow, error) {
+	query := `
+		SELECT ` + orderBookColumns + `
+		FROM t_trading_summary t
+		LEFT JOIN m_list_stocks m ON m.stock_code = t.stock_code
+		WHERE t.trade_date = (SELECT MAX(trade_date) FROM t_trading_summary WHERE trade_date <= ?)`
+	args := []any{asOf}
+
+	if len(stockCodes) > 0 {
+		query += `
+		  AND t.stock_code IN (?)`
+		args = append(args, stockCodes)
+	}
+
+	query, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []models.OrderBookRow{}
+	err = database.DB.Select(&rows, database.DB.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetStockOrderBook = histori snapshot bid/offer satu saham, N hari bursa terakhir sampai as_of
+func GetStockOrderBook(stockCode, asOf string, days int) ([]models.OrderBookRow, error) {
+	query := `
+		SELECT ` + orderBookColumns + `
+		FROM t_trading_summary t
+		LEFT JOIN m_list_stocks m ON m.stock_code = t.stock_code
+		WHERE t.stock_code = ?
+		  AND t.trade_date <= ?
+		ORDER BY t.trade_date DESC
+		LIMIT ?`
+
+	rows := []models.OrderBookRow{}
+	err := database.DB.Select(&rows, query, stockCode, asOf, days)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"indonesia-stocks-api/internal/database"
+	"indonesia-stocks-api/internal/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func UpsertRelativeStrength(rows []models.RelativeStrength) error {
+	query := `
+	INSERT INTO t_relative_strength (
+		trade_date, stock_code, stock_name, close_price,
+		return_3m, return_6m, return_12m, weighted_score,
+		rs_rating, rs_line, rs_line_new_high, created_at
+	)
+	VALUES (
+		:trade_date, :stock_code, :stock_name, :close_price,
+		:return_3m, :return_6m, :return_12m, :weighted_score,
+		:rs_rating, :rs_line, :rs_line_new_high, NOW()
+	)
+	ON DUPLICATE KEY UPDATE
+		stock_name = VALUES(stock_name),
+		close_price = VALUES(close_price),
+		return_3m = VALUES(return_3m),
+		return_6m = VALUES(return_6m),
+		return_12m = VALUES(return_12m),
+		weighted_score = VALUES(weighted_score),
+		rs_rating = VALUES(rs_rating),
+		rs_line = VALUES(rs_line),
+		rs_line_new_high = VALUES(rs_line_new_high)
+	`
+
+	for start := 0; start < len(rows); start += backtestInsertBatch {
+		end := min(start+backtestInsertBatch, len(rows))
+		if _, err := database.DB.NamedExec(query, rows[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const relativeStrengthColumns = `
+	trade_date, stock_code, stock_name, close_price,
+	return_3m, return_6m, return_12m, weighted_score,
+	rs_rating, rs_line, rs_line_new_high`
+
+// GetRelativeStrengthByDate = RS semua saham (atau saham tertentu) di satu tanggal, rating tertinggi dulu
+func GetRelativeStrengthByDate(tradeDate time.Time, stockCodes []string) ([]models.RelativeStrength, error) {
+	query := `
+		SELECT ` + relativeStrengthColumns + `
+		FROM t_relative_strength
+		WHERE trade_date = ?`
+	args := []any{tradeDate}
+
+	if len(stockCodes) > 0 {
+		query += `
+		  AND stock_code IN (?)`
+		args = append(args, stockCodes)
+	}
+	query += `
+		ORDER BY rs_rating DESC, weighted_score DESC, stock_code`
+
+	query, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []models.RelativeStrength{}
+	err = database.DB.Select(&rows, database.DB.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetStockRelativeStrength = histori RS satu saham, N hari terakhir sampai as_of (terbaru dulu)
+func GetStockRelativeStrength(stockCode, asOf string, days int) ([]models.RelativeStrength, error) {
+	query := `
+		SELECT ` + relativeStrengthColumns + `
+		FROM t_relative_strength
+		WHERE stock_code = ?
+		  AND trade_date <= ?
+		ORDER BY trade_date DESC
+		LIMIT ?`
+
+	rows := []models.RelativeStrength{}
+	err := database.DB.Select(&rows, query, stockCode, asOf, days)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetRelativeStrengthDates = tanggal yang RS-nya sudah pernah dihitung di antara start dan end
+func GetRelativeStrengthDates(startDate, endDate time.Time) ([]time.Time, error) {
+	query := `
+		SELECT DISTINCT trade_date
+		FROM t_relative_strength
+		WHERE trade_date BETWEEN ? AND ?
+		ORDER BY trade_date`
+
+	dates := []time.Time{}
+	err := database.DB.Select(&dates, query, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return dates, nil
+}
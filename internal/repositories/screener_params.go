@@ -0,0 +1,46 @@
+package repositories
+
+import "indonesia-stocks-api/internal/models"
+
+// Default threshold tiap screener. Key di sini juga jadi daftar param yang boleh di-sweep.
+var (
+	TopAccumulationParams = models.ScreenerParams{
+		"min_avg_value":          1000000000, // Likuid (Min 1M)
+		"vol_active_ratio":       0.5,        // last_volume > avg_vol20 * ratio
+		"avg_close_strength_min": 60,         // Close Mantap
+	}
+
+	TopAccumulationEODParams = models.ScreenerParams{
+		"min_avg_value": 1000000000,
+		"rsi_min":       30,
+		"rsi_max":       70,
+	}
+
+	SilentAccumulationParams = models.ScreenerParams{
+		"min_avg_value":           500000000,
+		"vol_spike_ratio":         2,  // Ledakan volume harian vs avg_vol20
+		"local_participation_max": 50, // Institusi dominan
+	}
+
+	TopSwingerParams = models.ScreenerParams{
+		"max_price":           500,
+		"min_value":           2000000000,
+		"avg_strength_min":    40,
+		"vol_multiplier_min":  2, // syarat alternatif kalau harga belum naik
+		"boom_vol_multiplier": 3, // skor tertinggi & label BOOM VOLUME
+		"atr_stop_mult":       0, // > 0: stop = close - mult x ATR14, 0 = low - 4%
+		"atr_target_mult":     0, // > 0: target = close + mult x ATR14, 0 = close + 10%
+	}
+)
+
+// withDefaults gabungin override user di atas default screener
+func withDefaults(defaults, params models.ScreenerParams) models.ScreenerParams {
+	merged := models.ScreenerParams{}
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range params {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"indonesia-stocks-api/internal/database"
+	"indonesia-stocks-api/internal/models"
+)
+
+// UpsertStockSectors simpan mapping sektor, replace = kosongkan tabel dulu (dalam satu transaksi)
+func UpsertStockSectors(sectors []models.StockSector, replace bool) error {
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if replace {
+		if _, err := tx.Exec(`DELETE FROM m_stock_sector`); err != nil {
+			return err
+		}
+	}
+
+	query := `
+	INSERT INTO m_stock_sector (stock_code, sector, sub_sector, updated_at)
+	VALUES (:stock_code, :sector, :sub_sector, NOW())
+	ON DUPLICATE KEY UPDATE
+		sector = VALUES(sector),
+		sub_sector = VALUES(sub_sector),
+		updated_at = NOW()
+	`
+
+	for start := 0; start < len(sectors); start += backtestInsertBatch {
+		end := min(start+backtestInsertBatch, len(sectors))
+		if _, err := tx.NamedExec(query, sectors[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func GetStockSectors() ([]models.StockSector, error) {
+	rows := []models.StockSector{}
+	err := database.DB.Select(&rows, `
+		SELECT stock_code, sector, sub_sector
+		FROM m_stock_sector
+		ORDER BY sector, stock_code`)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetSectorBars = bar harian semua saham yang punya mapping sektor
+func GetSectorBars(startDate, endDate string) ([]models.SectorBar, error) {
+	query := `
+		SELECT
+			t.stock_code, s.sector, t.trade_date, t.previous_price, t.close_price,
+			t.volume, t.value, t.foreign_buy, t.foreign_sell
+		FROM t_trading_summary t
+		JOIN m_stock_sector s ON s.stock_code = t.stock_code
+		WHERE t.trade_date BETWEEN ? AND ?
+		ORDER BY t.stock_code, t.trade_date`
+
+	rows := []models.SectorBar{}
+	err := database.DB.Select(&rows, query, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
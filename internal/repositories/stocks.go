@@ -5,6 +5,7 @@ import (
 	"indonesia-stocks-api/internal/database"
 	"indonesia-stocks-api/internal/helpers"
 	"indonesia-stocks-api/internal/models"
+	"time"
 )
 
 func UpsertStocks(stocks []models.StocksList) error {
@@ -135,12 +136,14 @@ func InsertTradingSummary(summaries []models.TradingSummaryDB) error {
 	return err
 }
 
-func GetTopAccumulation(days int) ([]models.TopAccumulation, error) {
+func GetTopAccumulation(days int, asOf string, params models.ScreenerParams) ([]models.TopAccumulation, error) {
+	p := withDefaults(TopAccumulationParams, params)
+
 	query := `
 		WITH DailyMetrics AS (
 			SELECT 
 				stock_code, stock_name, trade_date, close_price, volume, close_strength, value, high_price,
-				(foreign_buy - foreign_sell) as daily_net_foreign,
+				((foreign_buy - foreign_sell) * close_price) as daily_net_foreign, -- nilai (Rp), samain dengan screener EOD
 				AVG(close_price) OVER (PARTITION BY stock_code ORDER BY trade_date ROWS BETWEEN 19 PRECEDING AND CURRENT ROW) as ma20,
 				AVG(close_price) OVER (PARTITION BY stock_code ORDER BY trade_date ROWS BETWEEN 49 PRECEDING AND CURRENT ROW) as ma50,
 				MAX(high_price) OVER (PARTITION BY stock_code ORDER BY trade_date ROWS BETWEEN 20 PRECEDING AND 1 PRECEDING) as resistance_20,
@@ -148,6 +151,7 @@ func GetTopAccumulation(days int) ([]models.TopAccumulation, error) {
 				((close_price - LAG(close_price) OVER (PARTITION BY stock_code ORDER BY trade_date)) / 
 				 NULLIF(LAG(close_price) OVER (PARTITION BY stock_code ORDER BY trade_date), 0)) * 100 as change_pct
 			FROM t_trading_summary
+			WHERE trade_date <= ?                       -- Point-in-time: nggak boleh intip data setelah as_of
 		),
 		Screener AS (
 			SELECT 
@@ -165,7 +169,7 @@ func GetTopAccumulation(days int) ([]models.TopAccumulation, error) {
 				SUBSTRING_INDEX(GROUP_CONCAT(avg_vol20 ORDER BY trade_date DESC), ',', 1) + 0 as last_avg_vol20,
 				SUBSTRING_INDEX(GROUP_CONCAT(change_pct ORDER BY trade_date DESC), ',', 1) + 0 as last_change
 			FROM DailyMetrics
-			WHERE trade_date >= CURDATE() - INTERVAL ? DAY
+			WHERE trade_date >= DATE(?) - INTERVAL ? DAY
 			GROUP BY stock_code, stock_name
 		)
 		SELECT 
@@ -175,9 +179,9 @@ func GetTopAccumulation(days int) ([]models.TopAccumulation, error) {
 		WHERE 
 			net_foreign > 0                             -- Borong Asing
 			AND last_price > last_ma20                  -- Tren Naik
-			AND avg_value >= 1000000000                 -- Likuid (Min 1M)
-			AND last_volume > (last_avg_vol20 * 0.5)    -- Volume Aktif
-			AND avg_close_strength >= 60                -- Close Mantap
+			AND avg_value >= ?                          -- Likuid (Min 1M)
+			AND last_volume > (last_avg_vol20 * ?)      -- Volume Aktif
+			AND avg_close_strength >= ?                 -- Close Mantap
 		ORDER BY 
 			breakout_score DESC,                        -- Urutan Breakout Teratas
 			net_foreign DESC                            -- Lalu nominal foreign
@@ -185,73 +189,88 @@ func GetTopAccumulation(days int) ([]models.TopAccumulation, error) {
 	`
 
 	rows := []models.TopAccumulation{}
-	err := database.DB.Select(&rows, query, days)
+	err := database.DB.Select(&rows, query, asOf, asOf, days,
+		p["min_avg_value"], p["vol_active_ratio"], p["avg_close_strength_min"])
 	if err != nil {
 		return nil, err
 	}
 
 	for i := range rows {
-		// 1. Format Display Angka
-		rows[i].FormattedNetForeign = helpers.FormatBigNumber(rows[i].NetForeign)
-		rows[i].FormattedAvgValue = helpers.FormatBigNumber(rows[i].AvgValue)
+		LabelTopAccumulation(&rows[i])
+	}
 
-		// 2. Kalkulasi Jarak & Sinyal
-		isSuperBullish := rows[i].LastPrice > rows[i].Ma50
-		isBreakout := rows[i].LastPrice > rows[i].LastRes20
-		isNearRes := rows[i].LastPrice >= (rows[i].LastRes20 * 0.97)
+	return rows, nil
+}
 
-		diffRes := ((rows[i].LastPrice - rows[i].LastRes20) / rows[i].LastRes20) * 100
-		distStr := fmt.Sprintf("(%.1f%% To Res)", diffRes)
-		if diffRes >= 0 {
-			distStr = fmt.Sprintf("(+%.1f%% Above Res)", diffRes)
-		}
+// LabelTopAccumulation isi format angka & status dari LastRes20/Ma20/Ma50.
+// Dipanggil ulang kalau resistance diganti (level_source=zones).
+func LabelTopAccumulation(r *models.TopAccumulation) {
+	if r.LastRes20 > 0 {
+		r.BreakoutScore = r.LastPrice / r.LastRes20
+	}
 
-		// 3. Tentukan Status & Action
-		trendLabel := "BULLISH"
-		if isSuperBullish {
-			trendLabel = "SUPER BULLISH"
-		}
+	// 1. Format Display Angka
+	r.FormattedNetForeign = helpers.FormatBigNumber(r.NetForeign)
+	r.FormattedAvgValue = helpers.FormatBigNumber(r.AvgValue)
 
-		actionLabel := "HOLD / WATCH"
-		if isBreakout {
-			actionLabel = "🚀 BREAKOUT! (BUY)"
-		} else if isNearRes {
-			actionLabel = "⚔️ TESTING RES (SIAP HAKA)"
-		}
+	// 2. Kalkulasi Jarak & Sinyal
+	isSuperBullish := r.LastPrice > r.Ma50
+	isBreakout := r.LastPrice > r.LastRes20
+	isNearRes := r.LastPrice >= (r.LastRes20 * 0.97)
 
-		changeLabel := fmt.Sprintf("[+%.2f%% Today]", rows[i].LastChange)
-		if rows[i].LastChange < 0 {
-			changeLabel = fmt.Sprintf("[%.2f%% Today]", rows[i].LastChange)
-		}
+	diffRes := ((r.LastPrice - r.LastRes20) / r.LastRes20) * 100
+	distStr := fmt.Sprintf("(%.1f%% To Res)", diffRes)
+	if diffRes >= 0 {
+		distStr = fmt.Sprintf("(+%.1f%% Above Res)", diffRes)
+	}
 
-		// 4. Combine Display Status
-		rows[i].DisplayStatus = fmt.Sprintf("%s | %s | %s | %s", trendLabel, actionLabel, distStr, changeLabel)
+	// 3. Tentukan Status & Action
+	trendLabel := "BULLISH"
+	if isSuperBullish {
+		trendLabel = "SUPER BULLISH"
+	}
 
-		// 5. Override Golden Signal
-		if isSuperBullish && isBreakout {
-			rows[i].DisplayStatus = fmt.Sprintf("🔥 GOLDEN SIGNAL | STRONG BUY | %s | %s", distStr, changeLabel)
-		}
+	actionLabel := "HOLD / WATCH"
+	if isBreakout {
+		actionLabel = "🚀 BREAKOUT! (BUY)"
+	} else if isNearRes {
+		actionLabel = "⚔️ TESTING RES (SIAP HAKA)"
+	}
 
-		// Warning jika kenaikan harian terlalu ekstrim
-		if rows[i].LastChange > 18 {
-			rows[i].DisplayStatus += " ⚠️ HIGH VOLATILITY"
-		}
+	changeLabel := fmt.Sprintf("[+%.2f%% Today]", r.LastChange)
+	if r.LastChange < 0 {
+		changeLabel = fmt.Sprintf("[%.2f%% Today]", r.LastChange)
 	}
 
-	return rows, nil
+	// 4. Combine Display Status
+	r.DisplayStatus = fmt.Sprintf("%s | %s | %s | %s", trendLabel, actionLabel, distStr, changeLabel)
+
+	// 5. Override Golden Signal
+	if isSuperBullish && isBreakout {
+		r.DisplayStatus = fmt.Sprintf("🔥 GOLDEN SIGNAL | STRONG BUY | %s | %s", distStr, changeLabel)
+	}
+
+	// Warning jika kenaikan harian terlalu ekstrim
+	if r.LastChange > 18 {
+		r.DisplayStatus += " ⚠️ HIGH VOLATILITY"
+	}
 }
 
-func GetTopAccumulationEOD(days int) ([]models.TopAccumulationEod, error) {
+func GetTopAccumulationEOD(days int, asOf string, params models.ScreenerParams) ([]models.TopAccumulationEod, error) {
+	p := withDefaults(TopAccumulationEODParams, params)
+
 	// Query ini menggunakan teknik "Late Filtering"
 	// Supaya Resistance & MA akurat, kita hitung dulu dari histori panjang,
 	// baru kita ambil (JOIN) baris terakhirnya saja.
 	query := `
 WITH BaseData AS (
-    -- Ambil histori 100 hari supaya MA50 dan RSI tidak NULL
+    -- Ambil histori 100 hari (sampai as_of) supaya MA50 dan RSI tidak NULL
     SELECT * FROM t_trading_summary
-    WHERE trade_date >= (
+    WHERE trade_date <= ?
+      AND trade_date >= (
         SELECT MIN(trade_date) FROM (
             SELECT DISTINCT trade_date FROM t_trading_summary 
+            WHERE trade_date <= ?
             ORDER BY trade_date DESC LIMIT 100
         ) AS t
     )
@@ -295,6 +314,7 @@ FilteredStats AS (
     WHERE trade_date >= (
         SELECT MIN(trade_date) FROM (
             SELECT DISTINCT trade_date FROM t_trading_summary 
+            WHERE trade_date <= ?
             ORDER BY trade_date DESC LIMIT ?
         ) AS t
     )
@@ -321,97 +341,108 @@ SELECT *, (last_price / NULLIF(last_res_20, 0)) as breakout_score
 FROM FinalData
 WHERE net_foreign > 0 
   AND last_price > last_ma20 
-  AND avg_value >= 1000000000 
-  AND last_rsi BETWEEN 30 AND 70
+  AND avg_value >= ?
+  AND last_rsi BETWEEN ? AND ?
 ORDER BY net_foreign DESC
 LIMIT 50`
 
 	rows := []models.TopAccumulationEod{}
-	err := database.DB.Select(&rows, query, days)
+	err := database.DB.Select(&rows, query, asOf, asOf, asOf, days,
+		p["min_avg_value"], p["rsi_min"], p["rsi_max"])
 	if err != nil {
 		return nil, err
 	}
 
 	for i := range rows {
-		// 0. Formatting Numbers
-		rows[i].FormattedNetForeign = helpers.FormatBigNumber(rows[i].NetForeign)
-		rows[i].FormattedAvgValue = helpers.FormatBigNumber(rows[i].AvgValue)
+		LabelTopAccumulationEOD(&rows[i])
+	}
 
-		// 1. Sentimen Lokal
-		retailLabel := "💎 INST"
-		if rows[i].LocalParticipation > 80 {
-			retailLabel = "🤡 FOMO"
-		} else if rows[i].LocalParticipation > 60 {
-			retailLabel = "👥 MIX"
-		}
+	return rows, nil
+}
 
-		// 2. Volume & Trend
-		volRatio := 0.0
-		if rows[i].LastAvgVol20 > 0 {
-			volRatio = rows[i].LastVolume / rows[i].LastAvgVol20
-		}
+// LabelTopAccumulationEOD isi format angka & status HAKA/BOW/BREAKOUT dari LastRes20/LastSup20.
+// Dipanggil ulang kalau level diganti (level_source=zones).
+func LabelTopAccumulationEOD(r *models.TopAccumulationEod) {
+	if r.LastRes20 > 0 {
+		r.BreakoutScore = r.LastPrice / r.LastRes20
+	}
 
-		volEmoji := "⚪ Normal"
-		if volRatio >= 2.0 {
-			volEmoji = "💎 GIANT"
-		} else if volRatio >= 1.2 {
-			volEmoji = "🔊 HIGH"
-		}
+	// 0. Formatting Numbers
+	r.FormattedNetForeign = helpers.FormatBigNumber(r.NetForeign)
+	r.FormattedAvgValue = helpers.FormatBigNumber(r.AvgValue)
 
-		trendEmoji := "📈 BULL"
-		if rows[i].LastMa50 > 0 && rows[i].LastPrice > rows[i].LastMa50 {
-			trendEmoji = "🔥 SUPER"
-		}
+	// 1. Sentimen Lokal
+	retailLabel := "💎 INST"
+	if r.LocalParticipation > 80 {
+		retailLabel = "🤡 FOMO"
+	} else if r.LocalParticipation > 60 {
+		retailLabel = "👥 MIX"
+	}
 
-		// 3. Smart Money Signal
-		isSmartMoney := rows[i].NetForeign > (rows[i].AvgValue*0.15) && volRatio >= 1.5 && rows[i].AvgCloseStrength > 0.7
-		smLabel := ""
-		if isSmartMoney {
-			smLabel = "🐋 SMART MONEY | "
-		}
+	// 2. Volume & Trend
+	volRatio := 0.0
+	if r.LastAvgVol20 > 0 {
+		volRatio = r.LastVolume / r.LastAvgVol20
+	}
 
-		// 4. Action & Strategy
-		// breakout_score 1.0 = tepat di resistance
-		diffRes := ((rows[i].LastPrice - rows[i].LastRes20) / NULLIF_FLOAT(rows[i].LastRes20)) * 100
-		distToSup := ((rows[i].LastPrice - rows[i].LastSup20) / NULLIF_FLOAT(rows[i].LastSup20)) * 100
-
-		action := "👀 WATCH"
-		entryPrice := rows[i].LastPrice
-
-		if rows[i].LastChange < 0 && distToSup <= 3 {
-			action = "🛡️ BOW"
-		} else if diffRes >= 0 && rows[i].LastChange < 10 {
-			action = "🎯 HAKA!"
-		} else if diffRes >= 0 && rows[i].LastChange >= 10 {
-			action = "⌛ RETRACE"
-			entryPrice = rows[i].LastRes20
-		} else if diffRes < 0 && diffRes >= -2 {
-			action = "🚀 BREAKOUT"
-			entryPrice = rows[i].LastRes20 + 2
-		}
+	volEmoji := "⚪ Normal"
+	if volRatio >= 2.0 {
+		volEmoji = "💎 GIANT"
+	} else if volRatio >= 1.2 {
+		volEmoji = "🔊 HIGH"
+	}
 
-		// 5. Risk Calculation
-		stopLoss := rows[i].LastMa20
-		if rows[i].LastSup20 > 0 && rows[i].LastSup20 < stopLoss {
-			stopLoss = rows[i].LastSup20 * 0.99
-		}
+	trendEmoji := "📈 BULL"
+	if r.LastMa50 > 0 && r.LastPrice > r.LastMa50 {
+		trendEmoji = "🔥 SUPER"
+	}
 
-		riskPct := 0.0
-		if entryPrice > 0 {
-			riskPct = ((entryPrice - stopLoss) / entryPrice) * 100
-		}
+	// 3. Smart Money Signal
+	isSmartMoney := r.NetForeign > (r.AvgValue*0.15) && volRatio >= 1.5 && r.AvgCloseStrength > 0.7
+	smLabel := ""
+	if isSmartMoney {
+		smLabel = "🐋 SMART MONEY | "
+	}
 
-		riskEmoji := "🟢"
-		if riskPct > 7 {
-			riskEmoji = "🔴"
-		}
+	// 4. Action & Strategy
+	// breakout_score 1.0 = tepat di resistance
+	diffRes := ((r.LastPrice - r.LastRes20) / NULLIF_FLOAT(r.LastRes20)) * 100
+	distToSup := ((r.LastPrice - r.LastSup20) / NULLIF_FLOAT(r.LastSup20)) * 100
+
+	action := "👀 WATCH"
+	entryPrice := r.LastPrice
+
+	if r.LastChange < 0 && distToSup <= 3 {
+		action = "🛡️ BOW"
+	} else if diffRes >= 0 && r.LastChange < 10 {
+		action = "🎯 HAKA!"
+	} else if diffRes >= 0 && r.LastChange >= 10 {
+		action = "⌛ RETRACE"
+		entryPrice = r.LastRes20
+	} else if diffRes < 0 && diffRes >= -2 {
+		action = "🚀 BREAKOUT"
+		entryPrice = r.LastRes20 + 2
+	}
 
-		// 6. FINAL OUTPUT
-		rows[i].DisplayStatus = fmt.Sprintf("%s%s | %s (Lokal: %.0f%%) | %s | %s | Entry: %.0f | SL: %.0f (Risk: %.1f%%) %s",
-			smLabel, trendEmoji, retailLabel, rows[i].LocalParticipation, volEmoji, action, entryPrice, stopLoss, riskPct, riskEmoji)
+	// 5. Risk Calculation
+	stopLoss := r.LastMa20
+	if r.LastSup20 > 0 && r.LastSup20 < stopLoss {
+		stopLoss = r.LastSup20 * 0.99
 	}
 
-	return rows, nil
+	riskPct := 0.0
+	if entryPrice > 0 {
+		riskPct = ((entryPrice - stopLoss) / entryPrice) * 100
+	}
+
+	riskEmoji := "🟢"
+	if riskPct > 7 {
+		riskEmoji = "🔴"
+	}
+
+	// 6. FINAL OUTPUT
+	r.DisplayStatus = fmt.Sprintf("%s%s | %s (Lokal: %.0f%%) | %s | %s | Entry: %.0f | SL: %.0f (Risk: %.1f%%) %s",
+		smLabel, trendEmoji, retailLabel, r.LocalParticipation, volEmoji, action, entryPrice, stopLoss, riskPct, riskEmoji)
 }
 
 // Helper sederhana untuk menghindari divide by zero di Go
@@ -485,7 +516,9 @@ func RunBacktestEOD(targetDate string) ([]models.BacktestResult, error) {
 
 	return rows, nil
 }
-func GetTopSwinger(tradeDate string) ([]models.TopSwinger, error) {
+func GetTopSwinger(tradeDate string, params models.ScreenerParams) ([]models.TopSwinger, error) {
+	p := withDefaults(TopSwingerParams, params)
+
 	query := `
 WITH BaseData AS (
     SELECT * FROM t_trading_summary
@@ -506,7 +539,12 @@ History AS (
 Calculated AS (
     SELECT *,
         COALESCE(((volume - prev_vol) / NULLIF(prev_vol, 0)) * 100, 0) as vol_change_pct,
-        COALESCE(volume / NULLIF(prev_vol, 0), 1) as vol_multiplier
+        COALESCE(volume / NULLIF(prev_vol, 0), 1) as vol_multiplier,
+        AVG(GREATEST(
+            high_price - low_price,
+            ABS(high_price - COALESCE(prev_close, close_price)),
+            ABS(low_price - COALESCE(prev_close, close_price))
+        )) OVER (PARTITION BY stock_code ORDER BY trade_date ROWS BETWEEN 13 PRECEDING AND CURRENT ROW) as atr14
     FROM History
 ),
 FinalData AS (
@@ -519,7 +557,7 @@ FinalData AS (
             (avg_strength_5d * 0.3) + 
             (IF(close_price >= prev_close, 10, 0)) + 
             (CASE 
-                WHEN vol_multiplier >= 3 THEN 60 
+                WHEN vol_multiplier >= ? THEN 60
                 WHEN vol_multiplier >= 2 THEN 40
                 WHEN vol_multiplier >= 1.5 THEN 20
                 ELSE 0 
@@ -527,34 +565,44 @@ FinalData AS (
             2
         ) AS swing_score,
         close_price as entry_price,
-        ROUND(low_price * 0.96, 0) AS stop_loss,
-        ROUND(close_price * 1.10, 0) AS take_profit,
+        atr14,
+        COALESCE(ROUND(atr14 / NULLIF(close_price, 0) * 100, 2), 0) AS atr_pct,
+        -- Stop/target pakai kelipatan ATR kalau multiplier di-set, selain itu persen tetap.
+        -- Level ATR mentah, dibulatkan ke fraksi harga di services.GetTopSwinger
+        CASE WHEN ? > 0 AND atr14 > 0
+             THEN GREATEST(close_price - ? * atr14, 1)
+             ELSE ROUND(low_price * 0.96, 0) END AS stop_loss,
+        CASE WHEN ? > 0 AND atr14 > 0
+             THEN close_price + ? * atr14
+             ELSE ROUND(close_price * 1.10, 0) END AS take_profit,
         COALESCE(prev_close, close_price) as prev_close_val
     FROM Calculated
 )
 SELECT * FROM FinalData
 WHERE trade_date = ?
-  AND close_price < 500
-  AND value >= 2000000000
-  AND avg_strength_5d >= 40
+  AND close_price < ?
+  AND value >= ?
+  AND avg_strength_5d >= ?
   AND net_foreign >= 0 
   AND (
       close_price >= prev_close_val 
       OR 
-      (vol_multiplier >= 2 AND close_strength > 50)
+      (vol_multiplier >= ? AND close_strength > 50)
   )
 ORDER BY swing_score DESC, value DESC
 LIMIT 50;`
 
 	rows := []models.TopSwinger{}
-	err := database.DB.Select(&rows, query, tradeDate, tradeDate)
+	err := database.DB.Select(&rows, query, tradeDate, p["boom_vol_multiplier"],
+		p["atr_stop_mult"], p["atr_stop_mult"], p["atr_target_mult"], p["atr_target_mult"], tradeDate,
+		p["max_price"], p["min_value"], p["avg_strength_min"], p["vol_multiplier_min"])
 	if err != nil {
 		return nil, err
 	}
 
 	for i := range rows {
 		status := "🧘 SIDEWAYS"
-		if rows[i].VolMultiplier >= 3 {
+		if rows[i].VolMultiplier >= p["boom_vol_multiplier"] {
 			status = "🚀 BOOM VOLUME"
 		} else if rows[i].ClosePrice > rows[i].PrevCloseVal {
 			status = "📈 UPTREND"
@@ -567,7 +615,9 @@ LIMIT 50;`
 	return rows, nil
 }
 
-func GetSilentAccumulation(days int) ([]models.SilentAccumulation, error) {
+func GetSilentAccumulation(days int, asOf string, params models.ScreenerParams) ([]models.SilentAccumulation, error) {
+	p := withDefaults(SilentAccumulationParams, params)
+
 	query := `
 		WITH DailyMetrics AS (
 			SELECT 
@@ -582,6 +632,7 @@ func GetSilentAccumulation(days int) ([]models.SilentAccumulation, error) {
                 ((close_price - LAG(close_price) OVER (PARTITION BY stock_code ORDER BY trade_date)) / 
 				 NULLIF(LAG(close_price) OVER (PARTITION BY stock_code ORDER BY trade_date), 0)) * 100 as change_pct
 			FROM t_trading_summary
+			WHERE trade_date <= ?                -- Point-in-time: nggak boleh intip data setelah as_of
 		),
 		SilentScreener AS (
 			SELECT 
@@ -596,7 +647,7 @@ func GetSilentAccumulation(days int) ([]models.SilentAccumulation, error) {
 				SUBSTRING_INDEX(GROUP_CONCAT(avg_vol100 ORDER BY trade_date DESC), ',', 1) + 0 as last_avg_vol100,
                 SUBSTRING_INDEX(GROUP_CONCAT(change_pct ORDER BY trade_date DESC), ',', 1) + 0 as last_change
 			FROM DailyMetrics
-			WHERE trade_date >= CURDATE() - INTERVAL ? DAY
+			WHERE trade_date >= DATE(?) - INTERVAL ? DAY
 			GROUP BY stock_code, stock_name
 		),
 		FinalFilter AS (
@@ -606,11 +657,11 @@ func GetSilentAccumulation(days int) ([]models.SilentAccumulation, error) {
 		)
 		SELECT * FROM FinalFilter
 		WHERE net_foreign > 0 
-		  AND avg_value >= 500000000 
-		  AND last_volume > (last_avg_vol20 * 2)  -- Ledakan Volume harian
+		  AND avg_value >= ?
+		  AND last_volume > (last_avg_vol20 * ?)  -- Ledakan Volume harian
 		  -- AND last_avg_vol20 < last_avg_vol100   -- VALIDASI TIDUR: Sebulan terakhir lebih sepi dr rata-rata 5 bulan
 		  -- AND breakout_score <= 1.03             -- Belum lari jauh dr atap (Maks +3%)
-		  AND local_participation < 50           -- INSTITUSI DOMINAN
+		  AND local_participation < ?            -- INSTITUSI DOMINAN
 		ORDER BY 
 			local_participation ASC,              -- 1. Cari yang retailnya paling dikit (Utama)
 			(last_volume / last_avg_vol20) DESC   -- 2. Cari yang lonjakannya paling anomali
@@ -618,7 +669,8 @@ func GetSilentAccumulation(days int) ([]models.SilentAccumulation, error) {
 
 	rows := []models.SilentAccumulation{}
 
-	err := database.DB.Select(&rows, query, days)
+	err := database.DB.Select(&rows, query, asOf, asOf, days,
+		p["min_avg_value"], p["vol_spike_ratio"], p["local_participation_max"])
 	if err != nil {
 		return nil, err
 	}
@@ -743,3 +795,16 @@ ORDER BY td.trade_date DESC`
 	return []models.StatisticSingleStockMapped{result}, nil
 
 }
+
+// GetStockListingDate = tanggal IPO dari m_list_stocks, nil kalau saham nggak ada / tanggal kosong
+func GetStockListingDate(stockCode string) (*time.Time, error) {
+	dates := []*time.Time{}
+	err := database.DB.Select(&dates, `SELECT listing_date FROM m_list_stocks WHERE stock_code = ?`, stockCode)
+	if err != nil {
+		return nil, err
+	}
+	if len(dates) == 0 || dates[0] == nil || dates[0].IsZero() {
+		return nil, nil
+	}
+	return dates[0], nil
+}
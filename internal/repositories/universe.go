@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"indonesia-stocks-api/internal/database"
+	"indonesia-stocks-api/internal/models"
+)
+
+// UpsertUniverse simpan isi universe, replace = hapus isi lama universe itu dulu
+func UpsertUniverse(universe string, codes []string, replace bool) error {
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if replace {
+		if _, err := tx.Exec(`DELETE FROM m_stock_universe WHERE universe_code = ?`, universe); err != nil {
+			return err
+		}
+	}
+
+	rows := make([]models.StockUniverse, 0, len(codes))
+	for _, code := range codes {
+		rows = append(rows, models.StockUniverse{UniverseCode: universe, StockCode: code})
+	}
+
+	query := `
+	INSERT INTO m_stock_universe (universe_code, stock_code, updated_at)
+	VALUES (:universe_code, :stock_code, NOW())
+	ON DUPLICATE KEY UPDATE updated_at = NOW()
+	`
+
+	for start := 0; start < len(rows); start += backtestInsertBatch {
+		end := min(start+backtestInsertBatch, len(rows))
+		if _, err := tx.NamedExec(query, rows[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func GetUniverses() ([]models.UniverseSummary, error) {
+	rows := []models.UniverseSummary{}
+	err := database.DB.Select(&rows, `
+		SELECT universe_code, COUNT(*) AS total_stocks
+		FROM m_stock_universe
+		GROUP BY universe_code
+		ORDER BY universe_code`)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func GetUniverseCodes(universe string) ([]string, error) {
+	codes := []string{}
+	err := database.DB.Select(&codes, `
+		SELECT stock_code
+		FROM m_stock_universe
+		WHERE universe_code = ?
+		ORDER BY stock_code`, universe)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func GetSectorCodes(sector string) ([]string, error) {
+	codes := []string{}
+	err := database.DB.Select(&codes, `
+		SELECT stock_code
+		FROM m_stock_sector
+		WHERE sector = ?
+		ORDER BY stock_code`, sector)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
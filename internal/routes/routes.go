@@ -11,12 +11,55 @@ func RegisterRoutes(r *gin.Engine) {
 	r.GET("/idx/brokersummary", handlers.FetchBrokerSummary)
 	r.GET("/idx/brokersummary/analyze", handlers.AnalyzeBrokerSummary)
 	r.POST("/tradingsummary/insert", handlers.InsertTradingSummary)
+	r.POST("/indexsummary/insert", handlers.InsertIndexSummary)
 	r.POST("/idx/syncbroker", handlers.SyncBrokerFromIDX)
 	r.POST("/idx/syncstocks", handlers.SyncStocksFromIDX)
 	r.GET("/analyze/single-stocks", handlers.StatisticSingleStock)
+	r.GET("/analyze/patterns", handlers.GetStockPatterns)
+	r.GET("/analyze/levels", handlers.GetStockLevels)
+	r.GET("/analyze/foreign-flow", handlers.GetStockForeignFlow)
+	r.GET("/analyze/foreign-flow/market", handlers.GetMarketForeignFlow)
+	r.GET("/analyze/non-regular", handlers.ScanNonRegular)
+	r.GET("/analyze/non-regular/stock", handlers.GetStockNonRegular)
+	r.GET("/analyze/order-book", handlers.ScanOrderBook)
+	r.GET("/analyze/order-book/stock", handlers.GetStockOrderBook)
+	r.POST("/rs/compute", handlers.ComputeRelativeStrength)
+	r.GET("/analyze/rs", handlers.GetRSRanking)
+	r.GET("/analyze/rs/stock", handlers.GetStockRelativeStrength)
+	r.POST("/sectors/upload", handlers.UploadStockSectors)
+	r.GET("/sectors", handlers.ListStockSectors)
+	r.GET("/analyze/sector-rotation", handlers.GetSectorRotation)
+	r.POST("/breadth/compute", handlers.ComputeMarketBreadth)
+	r.GET("/analyze/breadth", handlers.GetMarketBreadth)
+	r.GET("/analyze/regime", handlers.GetMarketRegime)
+	r.POST("/universes/upload", handlers.UploadUniverse)
+	r.GET("/universes", handlers.ListUniverses)
+	r.GET("/analyze/correlation", handlers.GetCorrelation)
+	r.GET("/analyze/correlation/peers", handlers.GetCorrelatedPeers)
+	r.GET("/analyze/pairs", handlers.GetPair)
+	r.GET("/analyze/pairs/scan", handlers.ScanPairs)
+	r.GET("/analyze/volatility", handlers.GetStockVolatility)
+	r.GET("/analyze/volatility/squeeze", handlers.ScanSqueeze)
+	r.GET("/analyze/volume-profile", handlers.GetVolumeProfile)
+	r.GET("/analyze/avwap", handlers.GetAnchoredVWAP)
+	r.GET("/analyze/liquidity", handlers.GetStockLiquidity)
+	r.GET("/analyze/market-cap", handlers.GetMarketCapRanking)
+	r.GET("/analyze/market-cap/stock", handlers.GetStockMarketCap)
+	r.POST("/fundamentals/upload", handlers.UploadFinancials)
+	r.GET("/fundamentals", handlers.ListStockFinancials)
+	r.GET("/analyze/valuation", handlers.GetStockValuation)
 	r.GET("/analyze/top-accumulation", handlers.GetTopAccumulation)
 	r.GET("/analyze/top-accumulation-eod", handlers.GetTopAccumulationEod)
 	r.GET("/analyze/silent-accumulation", handlers.GetSilentAccumulation)
 	r.GET("/backtest/top-accumulation-eod", handlers.RunBacktestEOD)
+	r.GET("/backtest/screeners", handlers.ListScreeners)
+	r.POST("/backtest/run", handlers.RunBacktest)
+	r.POST("/backtest/sweep", handlers.RunParameterSweep)
+	r.POST("/backtest/montecarlo", handlers.RunMonteCarlo)
+	r.POST("/backtest/signal-study", handlers.RunSignalStudy)
+	r.GET("/backtest/runs", handlers.ListBacktestRuns)
+	r.GET("/backtest/runs/compare", handlers.CompareBacktestRuns)
+	r.GET("/backtest/runs/:id", handlers.GetBacktestRun)
+	r.POST("/backtest/runs/:id/rerun", handlers.RerunBacktest)
 	r.GET("/analyze/top-scalping-daily", handlers.GetTopScalping)
 }
@@ -0,0 +1,593 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/constants"
+	"indonesia-stocks-api/internal/helpers"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	EntryNextOpen = "next_open"
+	EntryClose    = "close"
+
+	ExitStopLoss   = "STOP_LOSS"
+	ExitTakeProfit = "TAKE_PROFIT"
+	ExitTrailing   = "TRAILING_STOP"
+	ExitTimeStop   = "TIME_STOP"
+	ExitMACross    = "MA_CROSS"
+	ExitEndOfTest  = "END_OF_TEST"
+
+	// Histori tambahan sebelum start_date buat MA exit (hari kalender)
+	backtestWarmupDays = 400
+	// Cukup buat dapat close indeks sehari sebelum start_date (lewat libur panjang)
+	benchmarkWarmupDays = 20
+)
+
+// priceSeries = bar satu saham + index tanggal biar lookup cepat
+type priceSeries struct {
+	bars  []models.DailyBar
+	index map[string]int
+}
+
+func (p *priceSeries) at(date time.Time) (int, bool) {
+	i, ok := p.index[date.Format("2006-01-02")]
+	return i, ok
+}
+
+// sma close N bar terakhir sampai index i (inklusif), 0 kalau histori kurang
+func (p *priceSeries) sma(i, period int) float64 {
+	if period <= 0 || i+1 < period {
+		return 0
+	}
+	sum := 0.0
+	for j := i - period + 1; j <= i; j++ {
+		sum += p.bars[j].Close
+	}
+	return sum / float64(period)
+}
+
+// lastOnOrBefore = index bar terakhir dengan tanggal <= date, -1 kalau nggak ada
+func (p *priceSeries) lastOnOrBefore(date time.Time) int {
+	return sort.Search(len(p.bars), func(i int) bool { return p.bars[i].TradeDate.After(date) }) - 1
+}
+
+func buildPriceSeries(bars []models.DailyBar) map[string]*priceSeries {
+	series := map[string]*priceSeries{}
+	for _, b := range bars {
+		s, ok := series[b.StockCode]
+		if !ok {
+			s = &priceSeries{index: map[string]int{}}
+			series[b.StockCode] = s
+		}
+		s.index[b.TradeDate.Format("2006-01-02")] = len(s.bars)
+		s.bars = append(s.bars, b)
+	}
+	return series
+}
+
+// tradable = ada transaksi hari itu (volume 0 berarti nggak ada yang match)
+func tradable(b models.DailyBar) bool {
+	return b.Volume > 0 && b.Close > 0
+}
+
+// openPrice IDX kadang 0 kalau nggak ada pre-opening, fallback ke previous
+func openPrice(b models.DailyBar) float64 {
+	if b.Open > 0 {
+		return b.Open
+	}
+	if b.Previous > 0 {
+		return b.Previous
+	}
+	return b.Close
+}
+
+type openPosition struct {
+	trade       models.BacktestTrade
+	entryIdx    int
+	peakPrice   float64
+	lastClose   float64 // buat mark-to-market equity
+	pendingExit string  // exit kemarin ketahan ARB, coba lagi di open hari ini
+}
+
+type pendingEntry struct {
+	signal models.ScreenerSignal
+}
+
+// backtestData = semua input yang dibutuhkan engine, di-load sekali di awal
+// (parameter sweep pakai ulang data yang sama untuk semua kombinasi)
+type backtestData struct {
+	days      []time.Time
+	series    map[string]*priceSeries
+	benchmark []models.IndexClose
+	hash      string
+	signals   *signalCache
+}
+
+// signalCache simpan hasil screener per (screener, hari, param). Sweep dan walk-forward
+// menjalankan kombinasi param yang sama di hari yang sama berkali-kali, query screener cukup sekali.
+type signalCache struct {
+	mu      sync.Mutex
+	entries map[string]*signalEntry
+}
+
+type signalEntry struct {
+	once    sync.Once
+	signals []models.ScreenerSignal
+	err     error
+}
+
+func newSignalCache() *signalCache {
+	return &signalCache{entries: map[string]*signalEntry{}}
+}
+
+func signalKey(screener string, day time.Time, params models.ScreenerParams) string {
+	// fmt map selalu urut key, jadi param yang sama menghasilkan key yang sama
+	return screener + "|" + day.Format("2006-01-02") + "|" + fmt.Sprint(params)
+}
+
+// get jalankan screener sekali per key, goroutine lain yang minta key sama menunggu hasilnya.
+// Hasil dipakai bareng, pemanggil nggak boleh mengubah isi slice.
+func (c *signalCache) get(screener string, day time.Time, params models.ScreenerParams) ([]models.ScreenerSignal, error) {
+	if c == nil {
+		return RunScreener(screener, day.Format("2006-01-02"), params)
+	}
+
+	key := signalKey(screener, day, params)
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &signalEntry{}
+		c.entries[key] = e
+	}
+	c.mu.Unlock()
+
+	e.once.Do(func() {
+		e.signals, e.err = RunScreener(screener, day.Format("2006-01-02"), params)
+	})
+	return e.signals, e.err
+}
+
+// window = potongan data untuk sub-periode (walk-forward), bar tetap dipakai bareng.
+// Benchmark ikut dipotong tapi close terakhir sebelum window tetap disimpan sebagai titik awal.
+func (d *backtestData) window(from, to int) *backtestData {
+	days := d.days[from:to]
+	first, last := days[0], days[len(days)-1]
+
+	benchmark := []models.IndexClose{}
+	for _, b := range d.benchmark {
+		if b.TradeDate.After(last) {
+			break
+		}
+		if b.TradeDate.Before(first) {
+			benchmark = append(benchmark[:0], b)
+			continue
+		}
+		benchmark = append(benchmark, b)
+	}
+
+	w := &backtestData{days: days, series: d.series, benchmark: benchmark, signals: d.signals}
+	w.hash = hashBacktestData(w)
+	return w
+}
+
+func loadBacktestData(cfg models.BacktestConfig) (*backtestData, error) {
+	start, _ := time.Parse("2006-01-02", cfg.StartDate)
+
+	days, err := repositories.GetTradingDates(cfg.StartDate, cfg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if len(days) == 0 {
+		return nil, fmt.Errorf("no trading data between %s and %s", cfg.StartDate, cfg.EndDate)
+	}
+
+	warmup := start.AddDate(0, 0, -backtestWarmupDays).Format("2006-01-02")
+	series, err := loadPriceSeries(warmup, cfg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	benchFrom := start.AddDate(0, 0, -benchmarkWarmupDays).Format("2006-01-02")
+	benchmark, err := repositories.GetIndexCloses(cfg.BenchmarkIndex, benchFrom, cfg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &backtestData{days: days, series: series, benchmark: benchmark, signals: newSignalCache()}
+	data.hash = hashBacktestData(data)
+	return data, nil
+}
+
+func loadPriceSeries(from, to string) (map[string]*priceSeries, error) {
+	bars, err := repositories.GetDailyBars(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return buildPriceSeries(bars), nil
+}
+
+// NormalizeBacktestConfig isi default dan validasi config sebelum engine jalan
+func NormalizeBacktestConfig(cfg *models.BacktestConfig) error {
+	start, err := time.Parse("2006-01-02", cfg.StartDate)
+	if err != nil {
+		return fmt.Errorf("invalid start_date, format: YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", cfg.EndDate)
+	if err != nil {
+		return fmt.Errorf("invalid end_date, format: YYYY-MM-DD")
+	}
+	if start.After(end) {
+		return fmt.Errorf("start_date > end_date")
+	}
+
+	if cfg.EntryMode == "" {
+		cfg.EntryMode = EntryNextOpen
+	}
+	if cfg.EntryMode != EntryNextOpen && cfg.EntryMode != EntryClose {
+		return fmt.Errorf("invalid entry_mode %q, use %s or %s", cfg.EntryMode, EntryNextOpen, EntryClose)
+	}
+	if _, ok := screeners[cfg.Screener]; !ok {
+		return fmt.Errorf("unknown screener %q, available: %v", cfg.Screener, ScreenerNames())
+	}
+	if err := validateScreenerParams(cfg.Screener, cfg.Params); err != nil {
+		return err
+	}
+
+	if cfg.BenchmarkIndex == "" {
+		cfg.BenchmarkIndex = constants.IndexComposite
+	}
+
+	if err := NormalizeExecutionConfig(&cfg.Execution); err != nil {
+		return err
+	}
+	return NormalizePortfolioConfig(&cfg.Portfolio)
+}
+
+// RunBacktest replay hari bursa satu per satu:
+// cek exit posisi yang lagi jalan, jalankan screener as_of hari itu, lalu entry.
+func RunBacktest(cfg models.BacktestConfig) (*models.BacktestReport, error) {
+	if err := NormalizeBacktestConfig(&cfg); err != nil {
+		return nil, err
+	}
+
+	data, err := loadBacktestData(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return simulate(cfg, data)
+}
+
+// simulate = inti engine, cfg diasumsikan sudah dinormalisasi
+func simulate(cfg models.BacktestConfig, data *backtestData) (*models.BacktestReport, error) {
+	trades := []models.BacktestTrade{}
+	positions := map[string]*openPosition{}
+	pending := []pendingEntry{}
+	totalSignals := 0
+	rejected := map[string]int{}
+	delayedExits := 0
+	book := newPortfolioBook(cfg.Portfolio, cfg.Execution)
+
+	enter := func(sig models.ScreenerSignal, s *priceSeries, i int, raw float64) {
+		pos, reason := tryEnter(sig, s, i, raw, cfg, book, positions)
+		if pos == nil {
+			rejected[reason]++
+			return
+		}
+		positions[sig.StockCode] = pos
+		if book.enabled() {
+			book.open(pos.trade)
+		}
+	}
+
+	exit := func(code string, pos *openPosition, bar models.DailyBar, i int, price float64, reason string) {
+		t := closePosition(pos, bar, i, price, reason, cfg.Execution)
+		trades = append(trades, t)
+		delete(positions, code)
+		if book.enabled() {
+			book.close(t)
+		}
+	}
+
+	for _, day := range data.days {
+		// 1. Eksekusi entry dari sinyal kemarin di harga open hari ini
+		for _, p := range pending {
+			if _, holding := positions[p.signal.StockCode]; holding {
+				continue
+			}
+			s, ok := data.series[p.signal.StockCode]
+			if !ok {
+				continue
+			}
+			i, ok := s.at(day)
+			if !ok || !tradable(s.bars[i]) {
+				continue
+			}
+			enter(p.signal, s, i, openPrice(s.bars[i]))
+		}
+		pending = pending[:0]
+
+		// 2. Cek exit semua posisi pakai bar hari ini
+		for code, pos := range positions {
+			s := data.series[code]
+			i, ok := s.at(day)
+			if !ok {
+				continue
+			}
+			raw, reason, done := checkExit(pos, s, i, cfg.Exit)
+			if !done {
+				continue
+			}
+			price, _, filled := fillSell(s.bars[i], raw)
+			if !filled {
+				if pos.pendingExit == "" {
+					delayedExits++
+				}
+				pos.pendingExit = reason
+				continue
+			}
+			exit(code, pos, s.bars[i], i, price, reason)
+		}
+
+		// 3. Screener as_of hari ini, cuma pakai data sampai hari ini
+		signals, err := data.signals.get(cfg.Screener, day, cfg.Params)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.MaxSignalsPerDay > 0 && len(signals) > cfg.MaxSignalsPerDay {
+			signals = signals[:cfg.MaxSignalsPerDay]
+		}
+		totalSignals += len(signals)
+
+		for _, sig := range signals {
+			if _, holding := positions[sig.StockCode]; holding {
+				continue
+			}
+			if cfg.EntryMode == EntryNextOpen {
+				pending = append(pending, pendingEntry{signal: sig})
+				continue
+			}
+
+			s, ok := data.series[sig.StockCode]
+			if !ok {
+				continue
+			}
+			i, ok := s.at(day)
+			if !ok || !tradable(s.bars[i]) {
+				continue
+			}
+			enter(sig, s, i, s.bars[i].Close)
+		}
+
+		// 4. Mark-to-market posisi di close hari ini
+		for code, pos := range positions {
+			s := data.series[code]
+			if i, ok := s.at(day); ok && s.bars[i].Close > 0 {
+				pos.lastClose = s.bars[i].Close
+			}
+		}
+		if book.enabled() {
+			book.mark(day, positions)
+		}
+	}
+
+	// 5. Posisi yang masih jalan ditutup di close terakhir periode ini. Series dipakai bareng
+	// antar window walk-forward, jadi bar terakhir series bisa jauh setelah periode selesai.
+	end := data.days[len(data.days)-1]
+	for code, pos := range positions {
+		s := data.series[code]
+		last := s.lastOnOrBefore(end)
+		exit(code, pos, s.bars[last], last, s.bars[last].Close, ExitEndOfTest)
+	}
+
+	sortTrades(trades)
+
+	summary := summarizeTrades(trades, totalSignals)
+	summary.RejectedEntries = rejected
+	summary.DelayedExits = delayedExits
+
+	report := &models.BacktestReport{
+		Config:      cfg,
+		DataStart:   data.days[0],
+		DataEnd:     data.days[len(data.days)-1],
+		DataHash:    data.hash,
+		CodeVersion: helpers.CodeVersion(),
+		TradingDays: len(data.days),
+		Summary:     summary,
+		Trades:      trades,
+	}
+
+	if book.enabled() {
+		book.settle()
+		report.EquityCurve = book.curve
+		report.Summary.InitialCapital = cfg.Portfolio.InitialCapital
+		report.Summary.FinalEquity = book.cash
+		report.Summary.TotalReturnPct = ((book.cash - cfg.Portfolio.InitialCapital) / cfg.Portfolio.InitialCapital) * 100
+	}
+
+	report.Metrics = ComputeBacktestMetrics(report, data.days, data.benchmark)
+
+	return report, nil
+}
+
+// tryEnter jalankan model eksekusi: fraksi harga, cek ARA, lalu hitung lot.
+// Mode per sinyal pakai nominal tetap, mode portfolio pakai sizing dari book.
+func tryEnter(sig models.ScreenerSignal, s *priceSeries, idx int, raw float64, cfg models.BacktestConfig, book *portfolioBook, positions map[string]*openPosition) (*openPosition, string) {
+	bar := s.bars[idx]
+	price, reason, ok := fillBuy(bar, raw)
+	if !ok {
+		return nil, reason
+	}
+
+	pos := newPosition(sig, bar, idx, price, cfg.Exit)
+
+	// Entry di open: high hari itu terjadi setelah masuk, jadi ikut jadi peak trailing.
+	// Entry di close: high sudah lewat sebelum masuk, nggak dihitung.
+	if cfg.EntryMode == EntryNextOpen && bar.High > pos.peakPrice {
+		pos.peakPrice = bar.High
+	}
+
+	if book.enabled() {
+		lots, reason := book.lots(pos, s, idx, positions)
+		if lots <= 0 {
+			return nil, reason
+		}
+		pos.trade.Lots = lots
+	} else {
+		lots := lotsFor(cfg.Execution.PositionValue, price, cfg.Execution)
+		if lots <= 0 {
+			return nil, RejectNoLot
+		}
+		pos.trade.Lots = lots
+	}
+
+	pos.lastClose = bar.Close
+	return pos, ""
+}
+
+func newPosition(sig models.ScreenerSignal, bar models.DailyBar, idx int, entryPrice float64, rules models.ExitRules) *openPosition {
+	stopLoss := 0.0
+	takeProfit := 0.0
+
+	if rules.StopLossPct > 0 {
+		stopLoss = entryPrice * (1 - rules.StopLossPct/100)
+	}
+	if rules.TakeProfitPct > 0 {
+		takeProfit = entryPrice * (1 + rules.TakeProfitPct/100)
+	}
+
+	// Level dari screener (TopSwinger) menang kalau diminta
+	if rules.UseSignalLevels {
+		if sig.StopLoss > 0 && sig.StopLoss < entryPrice {
+			stopLoss = sig.StopLoss
+		}
+		if sig.TakeProfit > entryPrice {
+			takeProfit = sig.TakeProfit
+		}
+	}
+
+	return &openPosition{
+		trade: models.BacktestTrade{
+			StockCode:       sig.StockCode,
+			StockName:       sig.StockName,
+			SignalDate:      sig.SignalDate,
+			SignalStatus:    sig.DisplayStatus,
+			EntryDate:       bar.TradeDate,
+			EntryPrice:      entryPrice,
+			StopLossLevel:   stopLoss,
+			TakeProfitLevel: takeProfit,
+		},
+		entryIdx:  idx,
+		peakPrice: entryPrice,
+	}
+}
+
+// checkExit urutannya konservatif: kalau SL dan TP kena di hari yang sama, anggap SL duluan
+func checkExit(pos *openPosition, s *priceSeries, i int, rules models.ExitRules) (float64, string, bool) {
+	bar := s.bars[i]
+	if i <= pos.entryIdx || !tradable(bar) {
+		return 0, "", false
+	}
+
+	open := openPrice(bar)
+
+	if pos.pendingExit != "" {
+		return open, pos.pendingExit, true
+	}
+
+	if sl := pos.trade.StopLossLevel; sl > 0 && bar.Low <= sl {
+		// Gap down di bawah SL: keluar di open
+		return min(open, sl), ExitStopLoss, true
+	}
+
+	if rules.TrailingStopPct > 0 {
+		trail := pos.peakPrice * (1 - rules.TrailingStopPct/100)
+		if bar.Low <= trail {
+			return min(open, trail), ExitTrailing, true
+		}
+	}
+
+	if tp := pos.trade.TakeProfitLevel; tp > 0 && bar.High >= tp {
+		return max(open, tp), ExitTakeProfit, true
+	}
+
+	// Peak di-update setelah cek hari ini, biar high hari ini nggak dipakai buat trail hari yang sama
+	if bar.High > pos.peakPrice {
+		pos.peakPrice = bar.High
+	}
+
+	if rules.MACrossPeriod > 0 {
+		if ma := s.sma(i, rules.MACrossPeriod); ma > 0 && bar.Close < ma {
+			return bar.Close, ExitMACross, true
+		}
+	}
+
+	if rules.MaxHoldDays > 0 && i-pos.entryIdx >= rules.MaxHoldDays {
+		return bar.Close, ExitTimeStop, true
+	}
+
+	return 0, "", false
+}
+
+func closePosition(pos *openPosition, bar models.DailyBar, idx int, exitPrice float64, reason string, exec models.ExecutionConfig) models.BacktestTrade {
+	t := pos.trade
+	t.ExitDate = bar.TradeDate
+	t.ExitPrice = exitPrice
+	t.ExitReason = reason
+	t.HoldingDays = idx - pos.entryIdx
+	if t.EntryPrice > 0 {
+		t.ReturnPct = ((exitPrice - t.EntryPrice) / t.EntryPrice) * 100
+	}
+	applyTradeCosts(&t, exec)
+	return t
+}
+
+func sortTrades(trades []models.BacktestTrade) {
+	sort.Slice(trades, func(i, j int) bool {
+		if !trades[i].EntryDate.Equal(trades[j].EntryDate) {
+			return trades[i].EntryDate.Before(trades[j].EntryDate)
+		}
+		return trades[i].StockCode < trades[j].StockCode
+	})
+}
+
+func summarizeTrades(trades []models.BacktestTrade, totalSignals int) models.BacktestSummary {
+	summary := models.BacktestSummary{
+		TotalSignals: totalSignals,
+		TotalTrades:  len(trades),
+	}
+	if len(trades) == 0 {
+		return summary
+	}
+
+	var sumReturn, sumNetReturn, sumHolding float64
+	for _, t := range trades {
+		// Menang/kalah dihitung setelah fee & pajak
+		switch {
+		case t.NetReturnPct > 0:
+			summary.WinCount++
+		case t.NetReturnPct < 0:
+			summary.LoseCount++
+		default:
+			summary.BreakEvenCount++
+		}
+		sumReturn += t.ReturnPct
+		sumNetReturn += t.NetReturnPct
+		sumHolding += float64(t.HoldingDays)
+		summary.TotalNetPnL += t.NetPnL
+		summary.TotalFees += t.BuyFee + t.SellFee
+	}
+
+	n := float64(len(trades))
+	summary.WinRate = (float64(summary.WinCount) / n) * 100
+	summary.AvgReturnPct = sumReturn / n
+	summary.AvgNetReturnPct = sumNetReturn / n
+	summary.AvgHoldingDays = sumHolding / n
+
+	return summary
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+var ErrBacktestRunNotFound = errors.New("backtest run not found")
+
+// hashBacktestData = sha256 dari semua input engine (hari bursa, bar OHLCV + input screener per saham, benchmark).
+// Cuma bar dalam rentang yang di-load untuk periode ini yang dihitung, jadi window walk-forward
+// punya hash yang sama dengan run ulang periode itu dari DB.
+// Dua run dengan hash sama berarti jalan di atas data yang identik.
+func hashBacktestData(data *backtestData) string {
+	h := sha256.New()
+	buf := make([]byte, 8)
+	writeFloat := func(f float64) {
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(f))
+		h.Write(buf)
+	}
+
+	for _, d := range data.days {
+		h.Write([]byte(d.Format("2006-01-02")))
+	}
+
+	first, last := data.days[0], data.days[len(data.days)-1]
+	warmup := first.AddDate(0, 0, -backtestWarmupDays)
+
+	codes := make([]string, 0, len(data.series))
+	for code := range data.series {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		// Kode ditulis saat bar pertama dalam rentang, saham tanpa bar di rentang ini nggak ikut
+		seen := false
+		for _, b := range data.series[code].bars {
+			if b.TradeDate.Before(warmup) || b.TradeDate.After(last) {
+				continue
+			}
+			if !seen {
+				h.Write([]byte(code))
+				seen = true
+			}
+			h.Write([]byte(b.TradeDate.Format("2006-01-02")))
+			h.Write([]byte(b.ListingBoard))
+			for _, f := range []float64{
+				b.Previous, b.Open, b.High, b.Low, b.Close, b.Volume, b.Value,
+				b.ForeignBuy, b.ForeignSell, b.CloseStrength, b.Frequency,
+			} {
+				writeFloat(f)
+			}
+		}
+	}
+
+	base, dates, values := alignBenchmark(data.benchmark, data.days)
+	h.Write([]byte(base.TradeDate.Format("2006-01-02")))
+	writeFloat(base.Close)
+	for i := range dates {
+		h.Write([]byte(dates[i].Format("2006-01-02")))
+		writeFloat(values[i])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// SaveBacktestRun simpan report ke DB dan isi report.RunID.
+// rerunOf diisi kalau run ini hasil re-run dari run lain.
+func SaveBacktestRun(report *models.BacktestReport, duration time.Duration, rerunOf *uint64) error {
+	configJSON, err := json.Marshal(report.Config)
+	if err != nil {
+		return err
+	}
+	summaryJSON, err := json.Marshal(report.Summary)
+	if err != nil {
+		return err
+	}
+	metricsJSON, err := json.Marshal(report.Metrics)
+	if err != nil {
+		return err
+	}
+
+	run := models.BacktestRun{
+		Screener:    report.Config.Screener,
+		CodeVersion: report.CodeVersion,
+		DataStart:   report.DataStart,
+		DataEnd:     report.DataEnd,
+		DataHash:    report.DataHash,
+		TotalTrades: len(report.Trades),
+		RerunOf:     rerunOf,
+		DurationMs:  duration.Milliseconds(),
+		ConfigJSON:  string(configJSON),
+		SummaryJSON: string(summaryJSON),
+		MetricsJSON: string(metricsJSON),
+	}
+
+	id, err := repositories.SaveBacktestRun(run, report.Trades, report.EquityCurve)
+	if err != nil {
+		return err
+	}
+
+	report.RunID = id
+	return nil
+}
+
+// saveRun simpan report dan balikin run_id, atau pesan error kalau gagal.
+// Gagal simpan nggak membatalkan hasil yang sudah dihitung.
+func saveRun(report *models.BacktestReport, duration time.Duration) (*uint64, string) {
+	if err := SaveBacktestRun(report, duration, nil); err != nil {
+		return nil, err.Error()
+	}
+	id := report.RunID
+	return &id, ""
+}
+
+func decodeBacktestRun(run *models.BacktestRun) error {
+	if err := json.Unmarshal([]byte(run.ConfigJSON), &run.Config); err != nil {
+		return fmt.Errorf("run %d: invalid config_json: %w", run.ID, err)
+	}
+	if err := json.Unmarshal([]byte(run.SummaryJSON), &run.Summary); err != nil {
+		return fmt.Errorf("run %d: invalid summary_json: %w", run.ID, err)
+	}
+	if err := json.Unmarshal([]byte(run.MetricsJSON), &run.Metrics); err != nil {
+		return fmt.Errorf("run %d: invalid metrics_json: %w", run.ID, err)
+	}
+	return nil
+}
+
+func ListBacktestRuns(screener string, limit, offset int) ([]models.BacktestRun, error) {
+	runs, err := repositories.ListBacktestRuns(screener, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for i := range runs {
+		if err := decodeBacktestRun(&runs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return runs, nil
+}
+
+func GetBacktestRun(id uint64) (*models.BacktestRun, error) {
+	run, err := repositories.GetBacktestRun(id)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, fmt.Errorf("%w: id %d", ErrBacktestRunNotFound, id)
+	}
+	if err := decodeBacktestRun(run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func GetBacktestRunDetail(id uint64) (*models.BacktestRunDetail, error) {
+	run, err := GetBacktestRun(id)
+	if err != nil {
+		return nil, err
+	}
+
+	trades, err := repositories.GetBacktestTrades(id)
+	if err != nil {
+		return nil, err
+	}
+	curve, err := repositories.GetBacktestEquity(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BacktestRunDetail{Run: *run, Trades: trades, EquityCurve: curve}, nil
+}
+
+// RerunBacktest jalankan ulang config yang tersimpan, simpan sebagai run baru (rerun_of = id lama)
+// dan balikin perbandingannya. same_data=false berarti data historis sudah berubah sejak run awal.
+func RerunBacktest(id uint64) (*models.BacktestReport, *models.BacktestRunComparison, error) {
+	original, err := GetBacktestRun(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start := time.Now()
+	report, err := RunBacktest(original.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := SaveBacktestRun(report, time.Since(start), &original.ID); err != nil {
+		return nil, nil, err
+	}
+
+	comparison, err := CompareBacktestRuns(original.ID, report.RunID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return report, comparison, nil
+}
+
+// CompareBacktestRuns = beda config (per field), data, versi kode, dan selisih metrik utama (b - a)
+func CompareBacktestRuns(idA, idB uint64) (*models.BacktestRunComparison, error) {
+	a, err := GetBacktestRun(idA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := GetBacktestRun(idB)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := configChanges(a.ConfigJSON, b.ConfigJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &models.BacktestRunComparison{
+		A:             *a,
+		B:             *b,
+		SameData:      a.DataHash == b.DataHash,
+		SameCode:      a.CodeVersion == b.CodeVersion,
+		ConfigChanges: changes,
+	}
+
+	reportA := &models.BacktestReport{Summary: a.Summary, Metrics: a.Metrics}
+	reportB := &models.BacktestReport{Summary: b.Summary, Metrics: b.Metrics}
+	for _, metric := range SweepMetrics() {
+		comparison.Deltas = append(comparison.Deltas, metricDelta(metric, metricScore(reportA, metric), metricScore(reportB, metric)))
+	}
+
+	tradesA, tradesB := float64(a.Summary.TotalTrades), float64(b.Summary.TotalTrades)
+	comparison.Deltas = append(comparison.Deltas, metricDelta("total_trades", &tradesA, &tradesB))
+
+	if a.Metrics.Equity != nil && b.Metrics.Equity != nil {
+		ddA, ddB := a.Metrics.Equity.MaxDrawdownPct, b.Metrics.Equity.MaxDrawdownPct
+		comparison.Deltas = append(comparison.Deltas, metricDelta("max_drawdown", &ddA, &ddB))
+	}
+
+	comparison.SameResult = true
+	for _, d := range comparison.Deltas {
+		if (d.A == nil) != (d.B == nil) || (d.Delta != nil && math.Abs(*d.Delta) > 1e-9) {
+			comparison.SameResult = false
+			break
+		}
+	}
+
+	return comparison, nil
+}
+
+func metricDelta(metric string, a, b *float64) models.RunMetricDelta {
+	d := models.RunMetricDelta{Metric: metric, A: a, B: b}
+	if a != nil && b != nil {
+		delta := *b - *a
+		d.Delta = &delta
+	}
+	return d
+}
+
+// configChanges ratakan kedua config JSON jadi path -> value lalu list path yang beda
+func configChanges(a, b string) ([]string, error) {
+	var ma, mb map[string]any
+	if err := json.Unmarshal([]byte(a), &ma); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(b), &mb); err != nil {
+		return nil, err
+	}
+
+	fa, fb := map[string]any{}, map[string]any{}
+	flattenJSON("", ma, fa)
+	flattenJSON("", mb, fb)
+
+	keys := map[string]bool{}
+	for k := range fa {
+		keys[k] = true
+	}
+	for k := range fb {
+		keys[k] = true
+	}
+
+	changes := []string{}
+	for k := range keys {
+		va, okA := fa[k]
+		vb, okB := fb[k]
+		if okA && okB && reflect.DeepEqual(va, vb) {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %v -> %v", k, va, vb))
+	}
+	sort.Strings(changes)
+	return changes, nil
+}
+
+func flattenJSON(prefix string, v any, out map[string]any) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		out[prefix] = v
+		return
+	}
+	for k, child := range obj {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		flattenJSON(key, child, out)
+	}
+}
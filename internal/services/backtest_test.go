@@ -0,0 +1,109 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"math"
+	"testing"
+	"time"
+)
+
+func testDay(i int) time.Time {
+	return time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+// almostEqualPtr: dua-duanya nil atau nilainya hampir sama
+func almostEqualPtr(got *float64, want *float64) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return almostEqual(*got, *want)
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func fmtPtr(v *float64) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprint(*v)
+}
+
+// seedSignals isi cache screener supaya simulate jalan tanpa DB
+func seedSignals(c *signalCache, screener string, day time.Time, params models.ScreenerParams, signals []models.ScreenerSignal) {
+	e := &signalEntry{}
+	e.once.Do(func() { e.signals = signals })
+	c.entries[signalKey(screener, day, params)] = e
+}
+
+func TestSimulateClosesOpenPositionsAtWindowEnd(t *testing.T) {
+	const screener = "test"
+	closes := []float64{1000, 1010, 1020, 1030, 1500, 2000}
+
+	bars := []models.DailyBar{}
+	days := []time.Time{}
+	for i, c := range closes {
+		bars = append(bars, models.DailyBar{
+			StockCode: "BBCA",
+			TradeDate: testDay(i),
+			Open:      c,
+			High:      c,
+			Low:       c,
+			Close:     c,
+			Volume:    1000,
+		})
+		days = append(days, testDay(i))
+	}
+
+	full := &backtestData{days: days, series: buildPriceSeries(bars), signals: newSignalCache()}
+	for _, d := range days {
+		seedSignals(full.signals, screener, d, nil, nil)
+	}
+	seedSignals(full.signals, screener, days[0], nil, []models.ScreenerSignal{
+		{StockCode: "BBCA", SignalDate: days[0], DisplayStatus: "HAKA"},
+	})
+
+	cfg := models.BacktestConfig{Screener: screener, EntryMode: EntryClose}
+	if err := NormalizeExecutionConfig(&cfg.Execution); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		data      *backtestData
+		exitDate  time.Time
+		exitPrice float64
+	}{
+		{"full range", full, days[5], 2000},
+		{"in-sample window", full.window(0, 3), days[2], 1020},
+		{"window ending mid series", full.window(0, 4), days[3], 1030},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := simulate(cfg, tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(report.Trades) != 1 {
+				t.Fatalf("got %d trades, want 1", len(report.Trades))
+			}
+			tr := report.Trades[0]
+			if tr.ExitReason != ExitEndOfTest {
+				t.Errorf("exit reason = %s, want %s", tr.ExitReason, ExitEndOfTest)
+			}
+			if !tr.ExitDate.Equal(tt.exitDate) {
+				t.Errorf("exit date = %s, want %s", tr.ExitDate.Format("2006-01-02"), tt.exitDate.Format("2006-01-02"))
+			}
+			if tr.ExitPrice != tt.exitPrice {
+				t.Errorf("exit price = %v, want %v", tr.ExitPrice, tt.exitPrice)
+			}
+		})
+	}
+}
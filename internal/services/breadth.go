@@ -0,0 +1,226 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"time"
+)
+
+const (
+	RegimeRiskOn  = "risk_on"
+	RegimeNeutral = "neutral"
+	RegimeRiskOff = "risk_off"
+
+	breadthHighLowBars = 252 // 52 minggu
+	breadthWarmupDays  = 380 // hari kalender sebelum start buat MA200 & high/low 52 minggu
+
+	// Smoothing McClellan: EMA 19 & 39 hari (alpha 0.10 & 0.05)
+	mcclellanFastAlpha = 0.10
+	mcclellanSlowAlpha = 0.05
+)
+
+// ErrBreadthNotComputed = breadth hari itu belum pernah di-POST /breadth/compute. Endpoint baca
+// nggak menghitung sendiri, A/D line & McClellan butuh hari-hari sebelumnya dihitung urut.
+var ErrBreadthNotComputed = errors.New("market breadth not computed")
+
+// BreadthRegimes = nilai yang bisa dipakai di gate ?breadth_regime=
+func BreadthRegimes() []string {
+	return []string{RegimeRiskOn, RegimeNeutral, RegimeRiskOff}
+}
+
+// BreadthRegime: risk_on kalau mayoritas saham di atas MA50 dan McClellan positif,
+// risk_off kalau kebalikannya, sisanya neutral
+func BreadthRegime(b models.MarketBreadth) string {
+	switch {
+	case b.PctAboveMA50 >= 55 && b.McClellanOsc > 0:
+		return RegimeRiskOn
+	case b.PctAboveMA50 <= 45 && b.McClellanOsc < 0:
+		return RegimeRiskOff
+	}
+	return RegimeNeutral
+}
+
+// breadthOn hitung breadth satu hari dari semua series (tanpa A/D line & McClellan, itu kumulatif)
+func breadthOn(day time.Time, series map[string]*priceSeries) models.MarketBreadth {
+	b := models.MarketBreadth{TradeDate: day}
+	var above20, above50, above200, n20, n50, n200 int
+
+	for _, s := range series {
+		i, ok := s.at(day)
+		if !ok {
+			continue
+		}
+		bar := s.bars[i]
+		if !tradable(bar) || bar.Previous <= 0 {
+			continue
+		}
+		b.TotalStocks++
+
+		switch {
+		case bar.Close > bar.Previous:
+			b.Advancers++
+		case bar.Close < bar.Previous:
+			b.Decliners++
+		default:
+			b.Unchanged++
+		}
+
+		lower, upper := AutoRejectionLimits(bar.Previous, bar.ListingBoard)
+		if bar.Close >= upper {
+			b.ARACount++
+		}
+		if bar.Close <= lower {
+			b.ARBCount++
+		}
+
+		for _, ma := range []struct {
+			period       int
+			above, count *int
+		}{{20, &above20, &n20}, {50, &above50, &n50}, {200, &above200, &n200}} {
+			if avg := s.sma(i, ma.period); avg > 0 {
+				*ma.count++
+				if bar.Close > avg {
+					*ma.above++
+				}
+			}
+		}
+
+		if i >= breadthHighLowBars {
+			hi, lo := highLow(s.bars, i-breadthHighLowBars, i-1)
+			if bar.High > hi {
+				b.NewHighs52W++
+			}
+			if bar.Low > 0 && bar.Low < lo {
+				b.NewLows52W++
+			}
+		}
+	}
+
+	pct := func(x, n int) float64 {
+		if n == 0 {
+			return 0
+		}
+		return float64(x) / float64(n) * 100
+	}
+	b.PctAboveMA20 = pct(above20, n20)
+	b.PctAboveMA50 = pct(above50, n50)
+	b.PctAboveMA200 = pct(above200, n200)
+	return b
+}
+
+// accumulateBreadth isi A/D line & McClellan lanjut dari baris sebelumnya (nil = mulai dari nol)
+func accumulateBreadth(rows []models.MarketBreadth, prev *models.MarketBreadth) {
+	for i := range rows {
+		r := &rows[i]
+		net := float64(r.Advancers - r.Decliners)
+
+		if prev == nil {
+			r.ADLine = int64(r.Advancers - r.Decliners)
+			r.EMA19, r.EMA39 = net, net
+		} else {
+			r.ADLine = prev.ADLine + int64(r.Advancers-r.Decliners)
+			r.EMA19 = prev.EMA19 + mcclellanFastAlpha*(net-prev.EMA19)
+			r.EMA39 = prev.EMA39 + mcclellanSlowAlpha*(net-prev.EMA39)
+		}
+		r.McClellanOsc = r.EMA19 - r.EMA39
+		r.McClellanSum = r.McClellanOsc
+		if prev != nil {
+			r.McClellanSum += prev.McClellanSum
+		}
+		r.Regime = BreadthRegime(*r)
+		prev = r
+	}
+}
+
+// NormalizeBreadthComputeRequest: end_date kosong = satu hari
+func NormalizeBreadthComputeRequest(req *models.BreadthComputeRequest) error {
+	rs := models.RSComputeRequest{StartDate: req.StartDate, EndDate: req.EndDate}
+	if err := NormalizeRSComputeRequest(&rs); err != nil {
+		return err
+	}
+	req.EndDate = rs.EndDate
+	return nil
+}
+
+// ComputeMarketBreadth hitung & simpan breadth tiap hari bursa di antara start dan end.
+// A/D line & McClellan lanjut dari baris terakhir sebelum start, jadi idealnya dihitung urut.
+func ComputeMarketBreadth(startDate, endDate string) ([]models.MarketBreadth, error) {
+	req := models.BreadthComputeRequest{StartDate: startDate, EndDate: endDate}
+	if err := NormalizeBreadthComputeRequest(&req); err != nil {
+		return nil, err
+	}
+	start, _ := time.Parse("2006-01-02", req.StartDate)
+
+	days, err := repositories.GetTradingDates(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if len(days) == 0 {
+		return nil, fmt.Errorf("no trading data between %s and %s", req.StartDate, req.EndDate)
+	}
+
+	series, err := loadPriceSeries(start.AddDate(0, 0, -breadthWarmupDays).Format("2006-01-02"), req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	prev, err := repositories.GetLastMarketBreadth(req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]models.MarketBreadth, 0, len(days))
+	for _, day := range days {
+		rows = append(rows, breadthOn(day, series))
+	}
+	accumulateBreadth(rows, prev)
+
+	if err := repositories.UpsertMarketBreadth(rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// GetMarketBreadth = time series breadth tersimpan, N hari bursa terakhir sampai as_of
+func GetMarketBreadth(asOf string, days int) ([]models.MarketBreadth, error) {
+	end, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return nil, err
+	}
+	start := end.AddDate(0, 0, -(days*7/5 + 30)).Format("2006-01-02")
+
+	rows, err := repositories.GetMarketBreadth(start, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > days {
+		rows = rows[len(rows)-days:]
+	}
+	for i := range rows {
+		rows[i].Regime = BreadthRegime(rows[i])
+	}
+	return rows, nil
+}
+
+// marketBreadthAsOf = breadth tersimpan di hari bursa terakhir <= as_of
+func marketBreadthAsOf(asOf string) (*models.MarketBreadth, error) {
+	date, err := repositories.GetLatestTradingDate(asOf)
+	if err != nil {
+		return nil, err
+	}
+	d := date.Format("2006-01-02")
+
+	rows, err := repositories.GetMarketBreadth(d, d)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w for %s, run POST /breadth/compute first", ErrBreadthNotComputed, d)
+	}
+
+	b := rows[0]
+	b.Regime = BreadthRegime(b)
+	return &b, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"indonesia-stocks-api/internal/models"
+	"testing"
+)
+
+func TestAccumulateBreadth(t *testing.T) {
+	tests := []struct {
+		name        string
+		prev        *models.MarketBreadth
+		rows        []models.MarketBreadth
+		wantADLine  []int64
+		wantOsc     []float64
+		wantSum     []float64
+		wantRegimes []string
+	}{
+		{
+			"first rows seed both EMAs with net advances",
+			nil,
+			[]models.MarketBreadth{
+				{Advancers: 10, Decliners: 4, PctAboveMA50: 50},
+				{Advancers: 2, Decliners: 8, PctAboveMA50: 40},
+			},
+			[]int64{6, 0},
+			[]float64{0, 4.8 - 5.4},
+			[]float64{0, 4.8 - 5.4},
+			[]string{RegimeNeutral, RegimeRiskOff},
+		},
+		{
+			"continues from the previous stored row",
+			&models.MarketBreadth{ADLine: 100, EMA19: 10, EMA39: 5, McClellanOsc: 5, McClellanSum: 50},
+			[]models.MarketBreadth{
+				{Advancers: 5, Decliners: 5, PctAboveMA50: 60},
+				{Advancers: 7, Decliners: 3, PctAboveMA50: 60},
+			},
+			[]int64{100, 104},
+			[]float64{9 - 4.75, (9 + 0.1*(4-9)) - (4.75 + 0.05*(4-4.75))},
+			[]float64{54.25, 54.25 + (9 + 0.1*(4-9)) - (4.75 + 0.05*(4-4.75))},
+			[]string{RegimeRiskOn, RegimeRiskOn},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accumulateBreadth(tt.rows, tt.prev)
+			for i, r := range tt.rows {
+				if r.ADLine != tt.wantADLine[i] {
+					t.Errorf("row %d A/D line = %d, want %d", i, r.ADLine, tt.wantADLine[i])
+				}
+				if !almostEqual(r.McClellanOsc, tt.wantOsc[i]) || !almostEqual(r.McClellanSum, tt.wantSum[i]) {
+					t.Errorf("row %d McClellan osc/sum = %v/%v, want %v/%v", i, r.McClellanOsc, r.McClellanSum, tt.wantOsc[i], tt.wantSum[i])
+				}
+				if r.Regime != tt.wantRegimes[i] {
+					t.Errorf("row %d regime = %s, want %s", i, r.Regime, tt.wantRegimes[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,516 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultCorrelationWindow = 60
+	DefaultClusterCount      = 5
+	DefaultPeerLimit         = 10
+
+	minCorrelationWindow   = 20
+	maxCorrelationWindow   = 500
+	maxCorrelationUniverse = 200
+	maxClusterCount        = 50
+
+	// pasangan/saham butuh minimal 80% hari window ada transaksi barengan
+	correlationMinCoverage = 0.8
+)
+
+var ErrUniverseNotFound = errors.New("universe not found")
+
+// NormalizeCorrelationRequest validasi sumber universe dan isi default window & cluster.
+// requireSource = false buat peers (kosong = semua saham).
+func NormalizeCorrelationRequest(req *models.CorrelationRequest, requireSource bool) error {
+	codes := []string{}
+	for _, raw := range req.Codes {
+		for _, code := range strings.Split(raw, ",") {
+			code = strings.ToUpper(strings.TrimSpace(code))
+			if code != "" && !slices.Contains(codes, code) {
+				codes = append(codes, code)
+			}
+		}
+	}
+	req.Codes = codes
+	req.Universe = strings.ToUpper(strings.TrimSpace(req.Universe))
+	req.Sector = strings.TrimSpace(req.Sector)
+
+	sources := 0
+	for _, set := range []bool{req.Universe != "", req.Sector != "", len(req.Codes) > 0} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return fmt.Errorf("use only one of universe, sector or codes")
+	}
+	if sources == 0 && requireSource {
+		return fmt.Errorf("universe, sector or codes is required")
+	}
+	if len(req.Codes) == 1 && requireSource {
+		return fmt.Errorf("codes needs at least 2 stocks")
+	}
+
+	if req.Window == 0 {
+		req.Window = DefaultCorrelationWindow
+	}
+	if req.Window < minCorrelationWindow || req.Window > maxCorrelationWindow {
+		return fmt.Errorf("window must be %d-%d", minCorrelationWindow, maxCorrelationWindow)
+	}
+	if req.Clusters == 0 {
+		req.Clusters = DefaultClusterCount
+	}
+	if req.Clusters < 1 || req.Clusters > maxClusterCount {
+		return fmt.Errorf("clusters must be 1-%d", maxClusterCount)
+	}
+	return nil
+}
+
+// NormalizeUniverseUpload rapikan nama universe & kode saham
+func NormalizeUniverseUpload(req *models.UniverseUploadRequest) error {
+	req.Universe = strings.ToUpper(strings.TrimSpace(req.Universe))
+	if req.Universe == "" {
+		return fmt.Errorf("universe is required")
+	}
+
+	codes := []string{}
+	for _, code := range req.Codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" {
+			continue
+		}
+		if slices.Contains(codes, code) {
+			return fmt.Errorf("duplicate stock_code %s", code)
+		}
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 {
+		return fmt.Errorf("codes is empty")
+	}
+	req.Codes = codes
+	return nil
+}
+
+// resolveUniverse balikin label dan daftar saham. codes nil = semua saham.
+func resolveUniverse(req models.CorrelationRequest) (string, []string, error) {
+	switch {
+	case req.Universe != "":
+		codes, err := repositories.GetUniverseCodes(req.Universe)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(codes) == 0 {
+			return "", nil, fmt.Errorf("%w: %s", ErrUniverseNotFound, req.Universe)
+		}
+		return req.Universe, codes, nil
+	case req.Sector != "":
+		codes, err := repositories.GetSectorCodes(req.Sector)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(codes) == 0 {
+			return "", nil, fmt.Errorf("%w: no stocks mapped to sector %s", ErrUniverseNotFound, req.Sector)
+		}
+		return "SECTOR:" + req.Sector, codes, nil
+	case len(req.Codes) > 0:
+		return "CUSTOM", req.Codes, nil
+	}
+	return "ALL", nil, nil
+}
+
+// returnWindow = return harian close vs previous tiap saham di `window` hari bursa terakhir <= as_of.
+// NaN = hari itu nggak ada transaksi.
+type returnWindow struct {
+	days    []time.Time
+	returns map[string][]float64
+}
+
+func loadReturnWindow(codes []string, asOf string, window int) (*returnWindow, error) {
+	end, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return nil, err
+	}
+	start := end.AddDate(0, 0, -(window*7/5 + 30)).Format("2006-01-02")
+
+	days, err := repositories.GetTradingDates(start, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if len(days) > window {
+		days = days[len(days)-window:]
+	}
+	if len(days) == 0 {
+		return nil, fmt.Errorf("%w up to %s", ErrNoTradingData, asOf)
+	}
+
+	from, to := days[0].Format("2006-01-02"), days[len(days)-1].Format("2006-01-02")
+	var bars []models.DailyBar
+	if codes == nil {
+		bars, err = repositories.GetDailyBars(from, to)
+	} else {
+		bars, err = repositories.GetStockBars(codes, from, to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dayIdx := make(map[string]int, len(days))
+	for i, d := range days {
+		dayIdx[d.Format("2006-01-02")] = i
+	}
+
+	rw := &returnWindow{days: days, returns: map[string][]float64{}}
+	for _, b := range bars {
+		r, ok := rw.returns[b.StockCode]
+		if !ok {
+			r = make([]float64, len(days))
+			for i := range r {
+				r[i] = math.NaN()
+			}
+			rw.returns[b.StockCode] = r
+		}
+		if i, ok := dayIdx[b.TradeDate.Format("2006-01-02")]; ok && tradable(b) && b.Previous > 0 {
+			r[i] = b.Close/b.Previous - 1
+		}
+	}
+	return rw, nil
+}
+
+func (rw *returnWindow) minObservations() int {
+	return int(math.Ceil(float64(len(rw.days)) * correlationMinCoverage))
+}
+
+// covered = saham dengan data cukup, sisanya masuk skipped
+func (rw *returnWindow) covered(codes []string) ([]string, []string) {
+	ok, skipped := []string{}, []string{}
+	for _, code := range codes {
+		n := 0
+		for _, r := range rw.returns[code] {
+			if !math.IsNaN(r) {
+				n++
+			}
+		}
+		if n >= rw.minObservations() {
+			ok = append(ok, code)
+		} else {
+			skipped = append(skipped, code)
+		}
+	}
+	return ok, skipped
+}
+
+// pearson pakai hari yang dua-duanya ada return (pairwise complete)
+func pearson(a, b []float64) (float64, int) {
+	var xs, ys []float64
+	for i := range a {
+		if !math.IsNaN(a[i]) && !math.IsNaN(b[i]) {
+			xs = append(xs, a[i])
+			ys = append(ys, b[i])
+		}
+	}
+	if len(xs) < 3 {
+		return 0, len(xs)
+	}
+
+	mx, sx := meanStd(xs)
+	my, sy := meanStd(ys)
+	if sx == 0 || sy == 0 {
+		return 0, len(xs)
+	}
+	cov := 0.0
+	for i := range xs {
+		cov += (xs[i] - mx) * (ys[i] - my)
+	}
+	cov /= float64(len(xs) - 1)
+	return cov / (sx * sy), len(xs)
+}
+
+// correlationMatrix: nil di pasangan yang observasinya kurang
+func (rw *returnWindow) correlationMatrix(codes []string) [][]*float64 {
+	n := len(codes)
+	matrix := make([][]*float64, n)
+	for i := range matrix {
+		matrix[i] = make([]*float64, n)
+		one := 1.0
+		matrix[i][i] = &one
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			corr, obs := pearson(rw.returns[codes[i]], rw.returns[codes[j]])
+			if obs < rw.minObservations() {
+				continue
+			}
+			c := corr
+			matrix[i][j], matrix[j][i] = &c, &c
+		}
+	}
+	return matrix
+}
+
+// correlationDistance: korelasi 1 = jarak 0, korelasi -1 = jarak 2, pasangan tanpa data dianggap korelasi 0
+func correlationDistance(corr *float64) float64 {
+	c := 0.0
+	if corr != nil {
+		c = *corr
+	}
+	return math.Sqrt(math.Max(0, 2*(1-c)))
+}
+
+// clusterLinkage = hierarchical clustering average linkage (UPGMA), O(n^3) cukup buat <= 200 saham
+func clusterLinkage(matrix [][]*float64) []models.ClusterMerge {
+	n := len(matrix)
+	if n < 2 {
+		return []models.ClusterMerge{}
+	}
+
+	dist := make([][]float64, 2*n-1)
+	for i := range dist {
+		dist[i] = make([]float64, 2*n-1)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j {
+				dist[i][j] = correlationDistance(matrix[i][j])
+			}
+		}
+	}
+
+	size := make([]int, 2*n-1)
+	active := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		size[i] = 1
+		active = append(active, i)
+	}
+
+	merges := make([]models.ClusterMerge, 0, n-1)
+	for next := n; len(active) > 1; next++ {
+		bi, bj := 0, 1
+		for a := 0; a < len(active); a++ {
+			for b := a + 1; b < len(active); b++ {
+				if dist[active[a]][active[b]] < dist[active[bi]][active[bj]] {
+					bi, bj = a, b
+				}
+			}
+		}
+		left, right := active[bi], active[bj]
+		size[next] = size[left] + size[right]
+		merges = append(merges, models.ClusterMerge{Left: left, Right: right, Distance: dist[left][right], Size: size[next]})
+
+		remaining := make([]int, 0, len(active)-1)
+		for _, k := range active {
+			if k == left || k == right {
+				continue
+			}
+			d := (float64(size[left])*dist[k][left] + float64(size[right])*dist[k][right]) / float64(size[next])
+			dist[k][next], dist[next][k] = d, d
+			remaining = append(remaining, k)
+		}
+		active = append(remaining, next)
+	}
+	return merges
+}
+
+// leafOrder = urutan saham dari kiri ke kanan dendrogram
+func leafOrder(merges []models.ClusterMerge, n int) []int {
+	if n == 0 {
+		return []int{}
+	}
+	if len(merges) == 0 {
+		return []int{0}
+	}
+
+	var walk func(id int, out []int) []int
+	walk = func(id int, out []int) []int {
+		if id < n {
+			return append(out, id)
+		}
+		m := merges[id-n]
+		out = walk(m.Left, out)
+		return walk(m.Right, out)
+	}
+	return walk(n+len(merges)-1, make([]int, 0, n))
+}
+
+// cutClusters potong dendrogram jadi k cluster: merge terakhir (k-1) dibatalkan
+func cutClusters(merges []models.ClusterMerge, n, k int) [][]int {
+	parent := make([]int, 2*n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(x int) int {
+		for parent[x] != x {
+			parent[x] = parent[parent[x]]
+			x = parent[x]
+		}
+		return x
+	}
+
+	keep := len(merges) - (k - 1)
+	for step := 0; step < keep && step < len(merges); step++ {
+		id := n + step
+		parent[find(merges[step].Left)] = id
+		parent[find(merges[step].Right)] = id
+	}
+
+	groups := map[int][]int{}
+	roots := []int{}
+	for _, leaf := range leafOrder(merges, n) {
+		root := find(leaf)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], leaf)
+	}
+
+	out := make([][]int, 0, len(roots))
+	for _, root := range roots {
+		out = append(out, groups[root])
+	}
+	return out
+}
+
+func avgPairCorrelation(matrix [][]*float64, members []int) *float64 {
+	sum, n := 0.0, 0
+	for a := 0; a < len(members); a++ {
+		for b := a + 1; b < len(members); b++ {
+			if c := matrix[members[a]][members[b]]; c != nil {
+				sum += *c
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	avg := sum / float64(n)
+	return &avg
+}
+
+// GetCorrelationReport = matriks korelasi return harian + clustering untuk satu universe
+func GetCorrelationReport(asOf string, req models.CorrelationRequest) (*models.CorrelationReport, error) {
+	label, codes, err := resolveUniverse(req)
+	if err != nil {
+		return nil, err
+	}
+	if codes == nil {
+		return nil, fmt.Errorf("universe, sector or codes is required")
+	}
+
+	rw, err := loadReturnWindow(codes, asOf, req.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, skipped := rw.covered(codes)
+	if len(codes) > maxCorrelationUniverse {
+		return nil, fmt.Errorf("universe has %d stocks, max %d", len(codes), maxCorrelationUniverse)
+	}
+
+	matrix := rw.correlationMatrix(codes)
+	merges := clusterLinkage(matrix)
+
+	report := &models.CorrelationReport{
+		Universe:  label,
+		AsOf:      asOf,
+		StartDate: rw.days[0].Format("2006-01-02"),
+		EndDate:   rw.days[len(rw.days)-1].Format("2006-01-02"),
+		Window:    len(rw.days),
+		Codes:     codes,
+		Matrix:    matrix,
+		Order:     []string{},
+		Linkage:   merges,
+		Clusters:  []models.StockCluster{},
+		Skipped:   skipped,
+	}
+
+	all := make([]int, len(codes))
+	for i := range all {
+		all[i] = i
+	}
+	report.AvgCorrelation = avgPairCorrelation(matrix, all)
+
+	for _, i := range leafOrder(merges, len(codes)) {
+		report.Order = append(report.Order, codes[i])
+	}
+
+	if len(codes) > 0 {
+		for i, members := range cutClusters(merges, len(codes), min(req.Clusters, len(codes))) {
+			cluster := models.StockCluster{Cluster: i + 1, Codes: make([]string, 0, len(members))}
+			for _, m := range members {
+				cluster.Codes = append(cluster.Codes, codes[m])
+			}
+			cluster.AvgCorrelation = avgPairCorrelation(matrix, members)
+			report.Clusters = append(report.Clusters, cluster)
+		}
+	}
+
+	return report, nil
+}
+
+// GetCorrelatedPeers = saham dengan korelasi return tertinggi ke satu saham.
+// Universe kosong = semua saham.
+func GetCorrelatedPeers(code, asOf string, req models.CorrelationRequest, limit int) ([]models.CorrelatedPeer, error) {
+	_, codes, err := resolveUniverse(req)
+	if err != nil {
+		return nil, err
+	}
+	if codes != nil && !slices.Contains(codes, code) {
+		codes = append(codes, code)
+	}
+
+	rw, err := loadReturnWindow(codes, asOf, req.Window)
+	if err != nil {
+		return nil, err
+	}
+	if ok, _ := rw.covered([]string{code}); len(ok) == 0 {
+		return []models.CorrelatedPeer{}, nil
+	}
+
+	sectors, err := repositories.GetStockSectors()
+	if err != nil {
+		return nil, err
+	}
+	sectorOf := make(map[string]string, len(sectors))
+	for _, s := range sectors {
+		sectorOf[s.StockCode] = s.Sector
+	}
+
+	target := rw.returns[code]
+	peers := []models.CorrelatedPeer{}
+	for other, r := range rw.returns {
+		if other == code {
+			continue
+		}
+		corr, obs := pearson(target, r)
+		if obs < rw.minObservations() {
+			continue
+		}
+		peers = append(peers, models.CorrelatedPeer{
+			StockCode:    other,
+			Sector:       sectorOf[other],
+			Correlation:  corr,
+			Observations: obs,
+		})
+	}
+
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].Correlation != peers[j].Correlation {
+			return peers[i].Correlation > peers[j].Correlation
+		}
+		return peers[i].StockCode < peers[j].StockCode
+	})
+	if len(peers) > limit {
+		peers = peers[:limit]
+	}
+	return peers, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"indonesia-stocks-api/internal/models"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// corrMatrix bikin matriks korelasi simetris dari segitiga atas, NaN = pasangan tanpa data
+func corrMatrix(n int, upper map[[2]int]float64) [][]*float64 {
+	m := make([][]*float64, n)
+	for i := range m {
+		m[i] = make([]*float64, n)
+		one := 1.0
+		m[i][i] = &one
+	}
+	for k, v := range upper {
+		if math.IsNaN(v) {
+			continue
+		}
+		c := v
+		m[k[0]][k[1]], m[k[1]][k[0]] = &c, &c
+	}
+	return m
+}
+
+func TestClusterLinkage(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]*float64
+		want   []models.ClusterMerge
+	}{
+		{"single stock", corrMatrix(1, nil), []models.ClusterMerge{}},
+		{
+			"two tight pairs",
+			corrMatrix(4, map[[2]int]float64{{0, 1}: 0.9, {2, 3}: 0.8, {0, 2}: 0, {0, 3}: 0, {1, 2}: 0, {1, 3}: 0}),
+			[]models.ClusterMerge{
+				{Left: 0, Right: 1, Distance: math.Sqrt(0.2), Size: 2},
+				{Left: 2, Right: 3, Distance: math.Sqrt(0.4), Size: 2},
+				{Left: 4, Right: 5, Distance: math.Sqrt2, Size: 4},
+			},
+		},
+		{
+			"average linkage weights by cluster size",
+			corrMatrix(3, map[[2]int]float64{{0, 1}: 0.9, {0, 2}: 0.5, {1, 2}: 0}),
+			[]models.ClusterMerge{
+				{Left: 0, Right: 1, Distance: math.Sqrt(0.2), Size: 2},
+				{Left: 2, Right: 3, Distance: (1 + math.Sqrt2) / 2, Size: 3},
+			},
+		},
+		{
+			"missing pair counts as zero correlation",
+			corrMatrix(3, map[[2]int]float64{{0, 1}: math.NaN(), {0, 2}: 0.5, {1, 2}: -1}),
+			[]models.ClusterMerge{
+				{Left: 0, Right: 2, Distance: 1, Size: 2},
+				{Left: 1, Right: 3, Distance: (math.Sqrt2 + 2) / 2, Size: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clusterLinkage(tt.matrix)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d merges, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, m := range got {
+				w := tt.want[i]
+				if m.Left != w.Left || m.Right != w.Right || m.Size != w.Size || !almostEqual(m.Distance, w.Distance) {
+					t.Errorf("merge %d = %+v, want %+v", i, m, w)
+				}
+			}
+		})
+	}
+}
+
+func TestCutClusters(t *testing.T) {
+	// {0,1} dan {2,3} dulu, baru digabung
+	merges := []models.ClusterMerge{
+		{Left: 0, Right: 1, Distance: 0.4, Size: 2},
+		{Left: 2, Right: 3, Distance: 0.6, Size: 2},
+		{Left: 4, Right: 5, Distance: 1.4, Size: 4},
+	}
+
+	tests := []struct {
+		name string
+		k    int
+		want [][]int
+	}{
+		{"one cluster", 1, [][]int{{0, 1, 2, 3}}},
+		{"two clusters", 2, [][]int{{0, 1}, {2, 3}}},
+		{"three clusters", 3, [][]int{{0, 1}, {2}, {3}}},
+		{"every stock alone", 4, [][]int{{0}, {1}, {2}, {3}}},
+		{"k above n", 10, [][]int{{0}, {1}, {2}, {3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cutClusters(merges, 4, tt.k); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cutClusters(k=%d) = %v, want %v", tt.k, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"math"
+	"strings"
+)
+
+const (
+	LotSize = 100 // 1 lot = 100 lembar
+
+	DefaultBuyFeePct     = 0.15
+	DefaultSellFeePct    = 0.15
+	DefaultSellTaxPct    = 0.1 // PPh final penjualan saham
+	DefaultPositionValue = 10_000_000
+
+	RejectLimitUp   = "NOT_FILLED_ARA"
+	RejectLimitDown = "NOT_FILLED_ARB"
+	RejectNoLot     = "NOT_FILLED_LOT"
+)
+
+// TickSize = fraksi harga IDX
+func TickSize(price float64) float64 {
+	switch {
+	case price < 200:
+		return 1
+	case price < 500:
+		return 2
+	case price < 2000:
+		return 5
+	case price < 5000:
+		return 10
+	default:
+		return 25
+	}
+}
+
+// RoundToTick bulatkan ke fraksi terdekat: buy dibulatkan ke atas, sell ke bawah
+func RoundToTick(price float64, up bool) float64 {
+	if price <= 0 {
+		return 0
+	}
+	tick := TickSize(price)
+	steps := price / tick
+	if up {
+		return math.Ceil(steps-1e-9) * tick
+	}
+	return math.Floor(steps+1e-9) * tick
+}
+
+// isSpecialBoard = papan akselerasi & pemantauan khusus, auto reject 10% dan harga min Rp1
+func isSpecialBoard(board string) bool {
+	b := strings.ToLower(board)
+	return strings.Contains(b, "akselerasi") || strings.Contains(b, "pemantauan")
+}
+
+// AutoRejectionLimits hitung batas ARB (lower) dan ARA (upper) dari harga previous
+func AutoRejectionLimits(prevClose float64, board string) (float64, float64) {
+	if prevClose <= 0 {
+		return 0, math.MaxFloat64
+	}
+
+	pct := 0.20
+	minPrice := 50.0
+	switch {
+	case isSpecialBoard(board):
+		pct = 0.10
+		minPrice = 1
+	case prevClose <= 200:
+		pct = 0.35
+	case prevClose <= 5000:
+		pct = 0.25
+	}
+
+	upper := RoundToTick(prevClose*(1+pct), false)
+	lower := RoundToTick(prevClose*(1-pct), true)
+	if lower < minPrice {
+		lower = minPrice
+	}
+
+	return lower, upper
+}
+
+// NormalizeExecutionConfig isi default per field: fee yang nggak dikirim pakai default,
+// yang dikirim (termasuk 0) dipakai apa adanya. Pajak jual selalu kena kecuali di-override.
+func NormalizeExecutionConfig(cfg *models.ExecutionConfig) error {
+	fees := []struct {
+		name  string
+		value **float64
+		def   float64
+	}{
+		{"buy_fee_pct", &cfg.BuyFeePct, DefaultBuyFeePct},
+		{"sell_fee_pct", &cfg.SellFeePct, DefaultSellFeePct},
+		{"sell_tax_pct", &cfg.SellTaxPct, DefaultSellTaxPct},
+	}
+	for _, f := range fees {
+		if *f.value == nil {
+			def := f.def
+			*f.value = &def
+			continue
+		}
+		if **f.value < 0 {
+			return fmt.Errorf("%s must be >= 0", f.name)
+		}
+	}
+
+	if cfg.PositionValue <= 0 {
+		cfg.PositionValue = DefaultPositionValue
+	}
+	return nil
+}
+
+// feePct baca fee yang sudah dinormalisasi, nil dianggap 0
+func feePct(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// fillBuy simulasi order beli di harga raw. Kalau harga sudah di ARA (antrian offer habis)
+// order dianggap nggak kebagian.
+func fillBuy(bar models.DailyBar, raw float64) (float64, string, bool) {
+	_, upper := AutoRejectionLimits(bar.Previous, bar.ListingBoard)
+	price := RoundToTick(raw, true)
+	if price >= upper {
+		return 0, RejectLimitUp, false
+	}
+	return price, "", true
+}
+
+// fillSell simulasi order jual. Kalau sepanjang hari harga nempel di ARB (high <= ARB)
+// nggak ada bid yang bisa diambil, posisi nyangkut.
+func fillSell(bar models.DailyBar, raw float64) (float64, string, bool) {
+	lower, upper := AutoRejectionLimits(bar.Previous, bar.ListingBoard)
+	if bar.High > 0 && bar.High <= lower {
+		return 0, RejectLimitDown, false
+	}
+	price := RoundToTick(raw, false)
+	price = math.Max(price, lower)
+	price = math.Min(price, upper)
+	return price, "", true
+}
+
+// lotsFor = jumlah lot maksimal yang bisa dibeli dengan nominal tertentu (termasuk fee beli)
+func lotsFor(value, price float64, exec models.ExecutionConfig) int {
+	if price <= 0 {
+		return 0
+	}
+	perLot := price * LotSize * (1 + feePct(exec.BuyFeePct)/100)
+	return int(math.Floor(value / perLot))
+}
+
+// applyTradeCosts isi lot, fee, pajak dan P&L bersih ke trade
+func applyTradeCosts(t *models.BacktestTrade, exec models.ExecutionConfig) {
+	shares := float64(t.Lots * LotSize)
+	buyValue := shares * t.EntryPrice
+	sellValue := shares * t.ExitPrice
+
+	t.BuyFee = buyValue * feePct(exec.BuyFeePct) / 100
+	t.SellFee = sellValue * (feePct(exec.SellFeePct) + feePct(exec.SellTaxPct)) / 100
+	t.NetPnL = sellValue - t.SellFee - buyValue - t.BuyFee
+
+	cost := buyValue + t.BuyFee
+	if cost > 0 {
+		t.NetReturnPct = (t.NetPnL / cost) * 100
+	}
+}
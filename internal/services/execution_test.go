@@ -0,0 +1,86 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTickSize(t *testing.T) {
+	tests := []struct {
+		price float64
+		want  float64
+	}{
+		{50, 1},
+		{199, 1},
+		{200, 2},
+		{499, 2},
+		{500, 5},
+		{1999, 5},
+		{2000, 10},
+		{4999, 10},
+		{5000, 25},
+		{25000, 25},
+	}
+
+	for _, tt := range tests {
+		if got := TickSize(tt.price); got != tt.want {
+			t.Errorf("TickSize(%v) = %v, want %v", tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestRoundToTick(t *testing.T) {
+	tests := []struct {
+		name  string
+		price float64
+		up    bool
+		want  float64
+	}{
+		{"zero price", 0, true, 0},
+		{"on grid stays", 1000, true, 1000},
+		{"buy rounds up", 1003, true, 1005},
+		{"sell rounds down", 1003, false, 1000},
+		{"up across tick boundary", 199.5, true, 200},
+		{"down in wider tick", 2001, false, 2000},
+		{"up in wider tick", 2001, true, 2010},
+		{"float noise above grid", 1250.0000000001, true, 1250},
+		{"float noise below grid", 1249.9999999999, false, 1250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RoundToTick(tt.price, tt.up); got != tt.want {
+				t.Errorf("RoundToTick(%v, %v) = %v, want %v", tt.price, tt.up, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoRejectionLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		prevClose float64
+		board     string
+		wantLower float64
+		wantUpper float64
+	}{
+		{"no previous", 0, "Utama", 0, math.MaxFloat64},
+		{"<= 200 is 35%", 100, "Utama", 65, 135},
+		{"floor at Rp50", 60, "Pengembangan", 50, 81},
+		{"200-5000 is 25%", 1000, "Utama", 750, 1250},
+		{"limits snap inside the tick grid", 1005, "Utama", 755, 1255},
+		{"> 5000 is 20%", 6000, "Utama", 4800, 7200},
+		{"special board is 10%", 10, "Papan Pemantauan Khusus", 9, 11},
+		{"special board floor at Rp1", 1, "Papan Pemantauan Khusus", 1, 1},
+		{"acceleration board", 100, "Akselerasi", 90, 110},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lower, upper := AutoRejectionLimits(tt.prevClose, tt.board)
+			if lower != tt.wantLower || upper != tt.wantUpper {
+				t.Errorf("AutoRejectionLimits(%v, %q) = (%v, %v), want (%v, %v)", tt.prevClose, tt.board, lower, upper, tt.wantLower, tt.wantUpper)
+			}
+		})
+	}
+}
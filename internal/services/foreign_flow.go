@@ -0,0 +1,234 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/helpers"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"sort"
+	"time"
+)
+
+const (
+	StreakNetBuy  = "net_buy"
+	StreakNetSell = "net_sell"
+	StreakFlat    = "flat"
+
+	// Histori minimal (hari bursa) buat hitung streak di ranking market
+	foreignStreakLookback = 30
+)
+
+// foreignSharePct = porsi asing dari turnover. Tiap transaksi punya pembeli & penjual,
+// jadi dibagi 2x volume biar maksimal 100%.
+func foreignSharePct(buy, sell, volume float64) float64 {
+	if volume <= 0 {
+		return 0
+	}
+	return (buy + sell) / (2 * volume) * 100
+}
+
+func foreignDaily(r models.ForeignFlowRow) models.ForeignFlowDaily {
+	return models.ForeignFlowDaily{
+		TradeDate:       r.TradeDate,
+		Close:           r.Close,
+		ForeignBuyVal:   r.ForeignBuy * r.Close,
+		ForeignSellVal:  r.ForeignSell * r.Close,
+		NetForeignVal:   (r.ForeignBuy - r.ForeignSell) * r.Close,
+		ForeignSharePct: foreignSharePct(r.ForeignBuy, r.ForeignSell, r.Volume),
+	}
+}
+
+func streakDirection(net float64) string {
+	switch {
+	case net > 0:
+		return StreakNetBuy
+	case net < 0:
+		return StreakNetSell
+	}
+	return StreakFlat
+}
+
+// foreignStreaks dari data harian urut tanggal naik: streak yang sedang jalan + streak buy/sell terpanjang
+func foreignStreaks(daily []models.ForeignFlowDaily) (current, longestBuy, longestSell models.ForeignStreak) {
+	longestBuy = models.ForeignStreak{Direction: StreakNetBuy}
+	longestSell = models.ForeignStreak{Direction: StreakNetSell}
+	run := models.ForeignStreak{Direction: StreakFlat}
+
+	for _, d := range daily {
+		dir := streakDirection(d.NetForeignVal)
+		if dir != run.Direction {
+			run = models.ForeignStreak{Direction: dir}
+		}
+		run.Days++
+		run.NetValue += d.NetForeignVal
+
+		if run.Direction == StreakNetBuy && run.Days > longestBuy.Days {
+			longestBuy = run
+		}
+		if run.Direction == StreakNetSell && run.Days > longestSell.Days {
+			longestSell = run
+		}
+	}
+	return run, longestBuy, longestSell
+}
+
+func GetStockForeignFlow(stockCode, asOf string, days int) (*models.StockForeignFlow, error) {
+	rows, err := repositories.GetStockForeignFlow(stockCode, asOf, days)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w for %s up to %s", ErrNoTradingData, stockCode, asOf)
+	}
+
+	result := &models.StockForeignFlow{
+		StockCode: stockCode,
+		StockName: rows[len(rows)-1].StockName,
+		AsOf:      asOf,
+		Days:      len(rows),
+	}
+
+	daily := make([]models.ForeignFlowDaily, 0, len(rows))
+	sumShare := 0.0
+	for _, r := range rows {
+		d := foreignDaily(r)
+		result.CumNetForeign += d.NetForeignVal
+		d.CumNetForeign = result.CumNetForeign
+		sumShare += d.ForeignSharePct
+		daily = append(daily, d)
+	}
+	result.AvgForeignSharePct = sumShare / float64(len(daily))
+	result.FormattedCumNet = helpers.FormatBigNumber(result.CumNetForeign)
+	result.CurrentStreak, result.LongestBuyStreak, result.LongestSellStreak = foreignStreaks(daily)
+
+	// response terbaru dulu
+	for l, r := 0, len(daily)-1; l < r; l, r = l+1, r-1 {
+		daily[l], daily[r] = daily[r], daily[l]
+	}
+	result.Daily = daily
+
+	return result, nil
+}
+
+// GetMarketForeignFlow = total flow asing harian se-market + ranking net buy/sell per saham
+// dijumlah selama `days` hari bursa terakhir
+func GetMarketForeignFlow(asOf string, days, limit int) (*models.MarketForeignFlow, error) {
+	rows, err := repositories.GetMarketForeignFlow(asOf, max(days, foreignStreakLookback))
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w up to %s", ErrNoTradingData, asOf)
+	}
+
+	// tanggal-tanggal yang masuk window ranking
+	dateSet := map[time.Time]bool{}
+	for _, r := range rows {
+		dateSet[r.TradeDate] = true
+	}
+	dates := make([]time.Time, 0, len(dateSet))
+	for d := range dateSet {
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(a, b int) bool { return dates[a].After(dates[b]) })
+	if len(dates) > days {
+		dates = dates[:days]
+	}
+	from := dates[len(dates)-1]
+
+	result := &models.MarketForeignFlow{
+		AsOf: asOf, Days: len(dates), DateFrom: from, DateTo: dates[0],
+		Daily:      []models.MarketForeignFlowDay{},
+		TopNetBuy:  []models.ForeignFlowRank{},
+		TopNetSell: []models.ForeignFlowRank{},
+	}
+
+	market := map[time.Time]*models.MarketForeignFlowDay{}
+	shares := map[time.Time]*[3]float64{} // buy, sell, volume (lembar) per hari
+	var marketBuy, marketSell, marketVol float64
+	ranks := []models.ForeignFlowRank{}
+
+	for start := 0; start < len(rows); {
+		end := start
+		for end < len(rows) && rows[end].StockCode == rows[start].StockCode {
+			end++
+		}
+		stockRows := rows[start:end]
+		start = end
+
+		daily := make([]models.ForeignFlowDaily, 0, len(stockRows))
+		rank := models.ForeignFlowRank{StockCode: stockRows[0].StockCode}
+		var buy, sell, vol float64
+		for _, r := range stockRows {
+			d := foreignDaily(r)
+			daily = append(daily, d)
+			if r.TradeDate.Before(from) {
+				continue
+			}
+
+			rank.StockName = r.StockName
+			rank.Close = r.Close
+			rank.ForeignBuyVal += d.ForeignBuyVal
+			rank.ForeignSellVal += d.ForeignSellVal
+			rank.NetForeignVal += d.NetForeignVal
+			rank.Value += r.Value
+			buy, sell, vol = buy+r.ForeignBuy, sell+r.ForeignSell, vol+r.Volume
+
+			m, ok := market[r.TradeDate]
+			if !ok {
+				m = &models.MarketForeignFlowDay{TradeDate: r.TradeDate}
+				market[r.TradeDate] = m
+				shares[r.TradeDate] = &[3]float64{}
+			}
+			sh := shares[r.TradeDate]
+			sh[0], sh[1], sh[2] = sh[0]+r.ForeignBuy, sh[1]+r.ForeignSell, sh[2]+r.Volume
+			m.ForeignBuyVal += d.ForeignBuyVal
+			m.ForeignSellVal += d.ForeignSellVal
+			m.NetForeignVal += d.NetForeignVal
+			m.Value += r.Value
+		}
+		if rank.StockName == "" {
+			continue // nggak ada transaksi di window
+		}
+
+		marketBuy, marketSell, marketVol = marketBuy+buy, marketSell+sell, marketVol+vol
+		rank.ForeignSharePct = foreignSharePct(buy, sell, vol)
+		rank.FormattedNet = helpers.FormatBigNumber(rank.NetForeignVal)
+		rank.Streak, _, _ = foreignStreaks(daily)
+		ranks = append(ranks, rank)
+	}
+
+	for _, d := range dates {
+		m, ok := market[d]
+		if !ok {
+			continue
+		}
+		sh := shares[d]
+		m.ForeignSharePct = foreignSharePct(sh[0], sh[1], sh[2])
+		result.Daily = append(result.Daily, *m)
+
+		result.Total.ForeignBuyVal += m.ForeignBuyVal
+		result.Total.ForeignSellVal += m.ForeignSellVal
+		result.Total.NetForeignVal += m.NetForeignVal
+		result.Total.Value += m.Value
+	}
+	result.Total.TradeDate = dates[0]
+	result.Total.ForeignSharePct = foreignSharePct(marketBuy, marketSell, marketVol)
+
+	sort.Slice(ranks, func(a, b int) bool { return ranks[a].NetForeignVal > ranks[b].NetForeignVal })
+	for _, r := range ranks {
+		if len(result.TopNetBuy) >= limit || r.NetForeignVal <= 0 {
+			break
+		}
+		result.TopNetBuy = append(result.TopNetBuy, r)
+	}
+	for k := len(ranks) - 1; k >= 0; k-- {
+		r := ranks[k]
+		if len(result.TopNetSell) >= limit || r.NetForeignVal >= 0 {
+			break
+		}
+		result.TopNetSell = append(result.TopNetSell, r)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,363 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FinancialSourceUpload = "upload"
+	FinancialSourceCSV    = "csv"
+
+	// Laporan interim IDX kumulatif dari awal tahun buku (Q3 = 9 bulan)
+	FiguresYTD     = "ytd"
+	FiguresQuarter = "quarter"
+
+	// Batas terbit laporan tahunan di IDX, dipakai kalau published_date nggak diisi
+	financialPublishLagDays = 90
+	ttmQuarters             = 4
+)
+
+// ErrNoFinancialData = saham belum punya harga atau laporan yang sudah terbit sampai as_of
+var ErrNoFinancialData = errors.New("no price or published financial data")
+
+// ErrMissingQuarter = upload YTD tapi kuartal sebelumnya di tahun buku yang sama belum ada
+var ErrMissingQuarter = errors.New("previous quarter is missing")
+
+// Kolom CSV sama dengan field JSON upload
+var financialCSVColumns = []string{
+	"stock_code", "fiscal_year", "fiscal_quarter", "period_end", "published_date",
+	"revenue", "net_income", "total_equity", "eps", "der",
+}
+
+func quarterEnd(year, quarter int) time.Time {
+	return time.Date(year, time.Month(quarter*3)+1, 0, 0, 0, 0, 0, time.UTC)
+}
+
+// NormalizeFinancialUpload validasi baris upload dan isi default tanggal
+func NormalizeFinancialUpload(rows []models.FinancialUploadRow, source string) ([]models.FinancialSummary, error) {
+	seen := map[string]bool{}
+	out := make([]models.FinancialSummary, 0, len(rows))
+	for i, r := range rows {
+		code := strings.ToUpper(strings.TrimSpace(r.StockCode))
+		if code == "" {
+			return nil, fmt.Errorf("row %d: stock_code is required", i+1)
+		}
+		if r.FiscalQuarter < 1 || r.FiscalQuarter > 4 || r.FiscalYear < 1990 || r.FiscalYear > 2100 {
+			return nil, fmt.Errorf("row %d: invalid fiscal_year/fiscal_quarter %d/%d", i+1, r.FiscalYear, r.FiscalQuarter)
+		}
+		key := financialPeriodKey(code, r.FiscalYear, r.FiscalQuarter)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate period %s", key)
+		}
+		seen[key] = true
+
+		row := models.FinancialSummary{
+			StockCode:     code,
+			FiscalYear:    r.FiscalYear,
+			FiscalQuarter: r.FiscalQuarter,
+			PeriodEnd:     quarterEnd(r.FiscalYear, r.FiscalQuarter),
+			Revenue:       r.Revenue,
+			NetIncome:     r.NetIncome,
+			TotalEquity:   r.TotalEquity,
+			EPS:           r.EPS,
+			DER:           r.DER,
+			Source:        source,
+		}
+		if r.PeriodEnd != "" {
+			d, err := time.Parse("2006-01-02", r.PeriodEnd)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid period_end, format: YYYY-MM-DD", i+1)
+			}
+			row.PeriodEnd = d
+		}
+		row.PublishedDate = row.PeriodEnd.AddDate(0, 0, financialPublishLagDays)
+		if r.PublishedDate != "" {
+			d, err := time.Parse("2006-01-02", r.PublishedDate)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid published_date, format: YYYY-MM-DD", i+1)
+			}
+			if d.Before(row.PeriodEnd) {
+				return nil, fmt.Errorf("row %d: published_date before period_end", i+1)
+			}
+			row.PublishedDate = d
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// NormalizeFinancialFigures: jenis angka upload wajib diisi, salah pilih bikin TTM melenceng jauh
+func NormalizeFinancialFigures(figures string) (string, error) {
+	figures = strings.ToLower(strings.TrimSpace(figures))
+	switch figures {
+	case FiguresYTD, FiguresQuarter:
+		return figures, nil
+	}
+	return "", fmt.Errorf("figures is required, use %s (year-to-date as published) or %s (standalone quarter)", FiguresYTD, FiguresQuarter)
+}
+
+func financialPeriodKey(code string, year, quarter int) string {
+	return fmt.Sprintf("%s-%dQ%d", code, year, quarter)
+}
+
+// decumulateFinancials ubah revenue, laba dan EPS YTD jadi angka kuartal: Qn - (Q1..Qn-1) di tahun buku
+// yang sama. Kuartal sebelumnya dari batch ini atau yang sudah tersimpan (stored, sudah angka kuartal).
+// Ekuitas & DER itu posisi neraca, nggak diubah.
+func decumulateFinancials(rows []models.FinancialSummary, stored map[string][]models.FinancialSummary) error {
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.StockCode != b.StockCode {
+			return a.StockCode < b.StockCode
+		}
+		if a.FiscalYear != b.FiscalYear {
+			return a.FiscalYear < b.FiscalYear
+		}
+		return a.FiscalQuarter < b.FiscalQuarter
+	})
+
+	quarters := map[string]models.FinancialSummary{}
+	for _, list := range stored {
+		for _, q := range list {
+			quarters[financialPeriodKey(q.StockCode, q.FiscalYear, q.FiscalQuarter)] = q
+		}
+	}
+
+	for i := range rows {
+		r := &rows[i]
+		var revenue, netIncome, eps float64
+		epsComplete := r.EPS != nil
+		for q := 1; q < r.FiscalQuarter; q++ {
+			prev, ok := quarters[financialPeriodKey(r.StockCode, r.FiscalYear, q)]
+			if !ok {
+				return fmt.Errorf("%w: %s %dQ%d is year-to-date, upload %dQ%d first or in the same batch",
+					ErrMissingQuarter, r.StockCode, r.FiscalYear, r.FiscalQuarter, r.FiscalYear, q)
+			}
+			revenue += prev.Revenue
+			netIncome += prev.NetIncome
+			if prev.EPS == nil {
+				epsComplete = false
+			} else {
+				eps += *prev.EPS
+			}
+		}
+
+		r.Revenue -= revenue
+		r.NetIncome -= netIncome
+		if epsComplete {
+			v := *r.EPS - eps
+			r.EPS = &v
+		} else {
+			r.EPS = nil
+		}
+		quarters[financialPeriodKey(r.StockCode, r.FiscalYear, r.FiscalQuarter)] = *r
+	}
+	return nil
+}
+
+// DecumulateFinancials = decumulateFinancials dengan kuartal tersimpan saham-saham di batch
+func DecumulateFinancials(rows []models.FinancialSummary) error {
+	stored := map[string][]models.FinancialSummary{}
+	for _, r := range rows {
+		if _, ok := stored[r.StockCode]; ok || r.FiscalQuarter == 1 {
+			continue
+		}
+		list, err := repositories.GetStockFinancials(r.StockCode)
+		if err != nil {
+			return err
+		}
+		stored[r.StockCode] = list
+	}
+	return decumulateFinancials(rows, stored)
+}
+
+// ParseFinancialCSV baca CSV dengan header (urutan kolom bebas, eps & der boleh kosong)
+func ParseFinancialCSV(r io.Reader) ([]models.FinancialUploadRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, name := range []string{"stock_code", "fiscal_year", "fiscal_quarter", "revenue", "net_income", "total_equity"} {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("csv column %q is required, columns: %v", name, financialCSVColumns)
+		}
+	}
+
+	rows := []models.FinancialUploadRow{}
+	for line := 2; ; line++ {
+		rec, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		field := func(name string) string {
+			if i, ok := col[name]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		var parseErr error
+		num := func(name string) float64 {
+			v, err := strconv.ParseFloat(field(name), 64)
+			if err != nil && parseErr == nil {
+				parseErr = fmt.Errorf("line %d: invalid %s %q", line, name, field(name))
+			}
+			return v
+		}
+		optional := func(name string) *float64 {
+			if field(name) == "" {
+				return nil
+			}
+			v := num(name)
+			return &v
+		}
+
+		row := models.FinancialUploadRow{
+			StockCode:     field("stock_code"),
+			FiscalYear:    int(num("fiscal_year")),
+			FiscalQuarter: int(num("fiscal_quarter")),
+			PeriodEnd:     field("period_end"),
+			PublishedDate: field("published_date"),
+			Revenue:       num("revenue"),
+			NetIncome:     num("net_income"),
+			TotalEquity:   num("total_equity"),
+			EPS:           optional("eps"),
+			DER:           optional("der"),
+		}
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("csv has no data rows")
+	}
+	return rows, nil
+}
+
+// valuationOf hitung rasio dari laporan yang sudah terbit (urut period_end naik). Angka tersimpan
+// sudah per kuartal (upload YTD di-decumulate), jadi TTM = jumlah 4 kuartal terakhir.
+// TTM kurang dari 4 kuartal disetahunkan, lihat QuartersUsed.
+func valuationOf(m models.StockMarketCap, quarters []models.FinancialSummary) models.StockValuation {
+	v := models.StockValuation{
+		StockCode: m.StockCode,
+		TradeDate: m.TradeDate.Format("2006-01-02"),
+		Close:     m.Close,
+	}
+	if len(quarters) == 0 {
+		return v
+	}
+
+	latest := quarters[len(quarters)-1]
+	v.LatestPeriod = fmt.Sprintf("%dQ%d", latest.FiscalYear, latest.FiscalQuarter)
+	v.PublishedDate = latest.PublishedDate.Format("2006-01-02")
+	v.TotalEquity = latest.TotalEquity
+	v.DER = latest.DER
+
+	oldest := latest.PeriodEnd.AddDate(-1, 0, 1)
+	var epsSum float64
+	epsComplete := true
+	for i := len(quarters) - 1; i >= 0 && v.QuartersUsed < ttmQuarters; i-- {
+		q := quarters[i]
+		if q.PeriodEnd.Before(oldest) {
+			break
+		}
+		v.QuartersUsed++
+		v.RevenueTTM += q.Revenue
+		v.NetIncomeTTM += q.NetIncome
+		if q.EPS != nil {
+			epsSum += *q.EPS
+		} else {
+			epsComplete = false
+		}
+	}
+	annualize := float64(ttmQuarters) / float64(v.QuartersUsed)
+	v.RevenueTTM *= annualize
+	v.NetIncomeTTM *= annualize
+
+	switch {
+	case m.ListedShares > 0:
+		eps := v.NetIncomeTTM / m.ListedShares
+		v.EPSTTM = &eps
+		bvps := v.TotalEquity / m.ListedShares
+		v.BVPS = &bvps
+	case epsComplete:
+		eps := epsSum * annualize
+		v.EPSTTM = &eps
+	}
+
+	if v.EPSTTM != nil && *v.EPSTTM > 0 && m.Close > 0 {
+		per := m.Close / *v.EPSTTM
+		v.PER = &per
+	}
+	if v.BVPS != nil && *v.BVPS > 0 && m.Close > 0 {
+		pbv := m.Close / *v.BVPS
+		v.PBV = &pbv
+	}
+	if v.TotalEquity > 0 {
+		roe := v.NetIncomeTTM / v.TotalEquity * 100
+		v.ROE = &roe
+	}
+	return v
+}
+
+// valuationsAsOf = valuasi saham-saham (nil = semua) di hari bursa terakhir <= as_of.
+// Saham tanpa laporan keuangan nggak ikut.
+func valuationsAsOf(asOf string, codes []string) (map[string]models.StockValuation, error) {
+	// listed_shares boleh kosong, EPS fallback ke EPS laporan
+	date, err := repositories.GetLatestTradingDate(asOf)
+	if err != nil {
+		return nil, err
+	}
+	caps, err := repositories.GetMarketCapsByDate(date.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	financials, err := repositories.GetPublishedFinancials(codes, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	byCode := map[string][]models.FinancialSummary{}
+	for _, f := range financials {
+		byCode[f.StockCode] = append(byCode[f.StockCode], f)
+	}
+
+	out := make(map[string]models.StockValuation, len(byCode))
+	for _, m := range caps {
+		if quarters, ok := byCode[m.StockCode]; ok && m.Close > 0 {
+			out[m.StockCode] = valuationOf(m, quarters)
+		}
+	}
+	return out, nil
+}
+
+func GetStockValuation(stockCode, asOf string) (*models.StockValuation, error) {
+	rows, err := valuationsAsOf(asOf, []string{stockCode})
+	if err != nil {
+		return nil, err
+	}
+	v, ok := rows[stockCode]
+	if !ok {
+		return nil, fmt.Errorf("%w for %s up to %s", ErrNoFinancialData, stockCode, asOf)
+	}
+	return &v, nil
+}
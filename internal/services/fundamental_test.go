@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"indonesia-stocks-api/internal/models"
+	"testing"
+)
+
+// quarter = laporan satu kuartal, period_end di akhir kuartal
+func quarter(year, q int, netIncome, equity float64, eps *float64) models.FinancialSummary {
+	end := quarterEnd(year, q)
+	return models.FinancialSummary{
+		StockCode:     "BBCA",
+		FiscalYear:    year,
+		FiscalQuarter: q,
+		PeriodEnd:     end,
+		PublishedDate: end.AddDate(0, 0, 60),
+		Revenue:       netIncome * 10,
+		NetIncome:     netIncome,
+		TotalEquity:   equity,
+		EPS:           eps,
+	}
+}
+
+func TestValuationOf(t *testing.T) {
+	tests := []struct {
+		name         string
+		cap          models.StockMarketCap
+		quarters     []models.FinancialSummary
+		wantQuarters int
+		wantNetTTM   float64
+		wantEPS      *float64
+		wantPER      *float64
+		wantPBV      *float64
+		wantROE      *float64
+	}{
+		{"no reports", models.StockMarketCap{Close: 500, ListedShares: 1000}, nil, 0, 0, nil, nil, nil, nil},
+		{
+			"full year from listed shares",
+			models.StockMarketCap{Close: 500, ListedShares: 1000},
+			[]models.FinancialSummary{
+				quarter(2024, 1, 100, 1800, nil),
+				quarter(2024, 2, 100, 1900, nil),
+				quarter(2024, 3, 100, 1950, nil),
+				quarter(2024, 4, 100, 2000, nil),
+			},
+			4, 400, float64Ptr(0.4), float64Ptr(1250), float64Ptr(250), float64Ptr(20),
+		},
+		{
+			"half year annualized from reported EPS",
+			models.StockMarketCap{Close: 400},
+			[]models.FinancialSummary{
+				quarter(2024, 3, 50, 1000, float64Ptr(10)),
+				quarter(2024, 4, 50, 1000, float64Ptr(10)),
+			},
+			2, 200, float64Ptr(40), float64Ptr(10), nil, float64Ptr(20),
+		},
+		{
+			"missing EPS without listed shares",
+			models.StockMarketCap{Close: 400},
+			[]models.FinancialSummary{
+				quarter(2024, 3, 50, 1000, nil),
+				quarter(2024, 4, 50, 1000, float64Ptr(10)),
+			},
+			2, 200, nil, nil, nil, float64Ptr(20),
+		},
+		{
+			"quarters older than a year are skipped, losses have no PER",
+			models.StockMarketCap{Close: 100, ListedShares: 100},
+			[]models.FinancialSummary{
+				quarter(2023, 4, 1000, 500, nil),
+				quarter(2024, 3, -10, 500, nil),
+				quarter(2024, 4, -10, 500, nil),
+			},
+			2, -40, float64Ptr(-0.4), nil, float64Ptr(20), float64Ptr(-8),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := valuationOf(tt.cap, tt.quarters)
+			if v.QuartersUsed != tt.wantQuarters || !almostEqual(v.NetIncomeTTM, tt.wantNetTTM) {
+				t.Errorf("quarters used = %d, net income TTM = %v, want %d and %v", v.QuartersUsed, v.NetIncomeTTM, tt.wantQuarters, tt.wantNetTTM)
+			}
+			for _, f := range []struct {
+				name      string
+				got, want *float64
+			}{
+				{"EPS TTM", v.EPSTTM, tt.wantEPS},
+				{"PER", v.PER, tt.wantPER},
+				{"PBV", v.PBV, tt.wantPBV},
+				{"ROE", v.ROE, tt.wantROE},
+			} {
+				if !almostEqualPtr(f.got, f.want) {
+					t.Errorf("%s = %s, want %s", f.name, fmtPtr(f.got), fmtPtr(f.want))
+				}
+			}
+		})
+	}
+}
+
+func TestDecumulateFinancials(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows       []models.FinancialSummary
+		stored     []models.FinancialSummary
+		wantNet    []float64 // urut tahun & kuartal
+		wantEPS    []*float64
+		wantMissed bool
+	}{
+		{
+			"full year in one batch, any order",
+			[]models.FinancialSummary{
+				quarter(2024, 3, 270, 1000, float64Ptr(27)),
+				quarter(2024, 1, 100, 900, float64Ptr(10)),
+				quarter(2024, 4, 400, 1100, float64Ptr(40)),
+				quarter(2024, 2, 180, 950, float64Ptr(18)),
+			},
+			nil,
+			[]float64{100, 80, 90, 130},
+			[]*float64{float64Ptr(10), float64Ptr(8), float64Ptr(9), float64Ptr(13)},
+			false,
+		},
+		{
+			"earlier quarters already stored as standalone",
+			[]models.FinancialSummary{quarter(2024, 3, 270, 1000, float64Ptr(27))},
+			[]models.FinancialSummary{quarter(2024, 1, 100, 900, float64Ptr(10)), quarter(2024, 2, 80, 950, nil)},
+			[]float64{90},
+			[]*float64{nil},
+			false,
+		},
+		{
+			"new fiscal year starts over",
+			[]models.FinancialSummary{quarter(2024, 4, 400, 1100, nil), quarter(2025, 1, 120, 1200, nil)},
+			[]models.FinancialSummary{quarter(2024, 1, 100, 900, nil), quarter(2024, 2, 80, 950, nil), quarter(2024, 3, 90, 1000, nil)},
+			[]float64{130, 120},
+			[]*float64{nil, nil},
+			false,
+		},
+		{
+			"missing previous quarter",
+			[]models.FinancialSummary{quarter(2024, 3, 270, 1000, nil)},
+			[]models.FinancialSummary{quarter(2024, 1, 100, 900, nil)},
+			nil, nil, true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := decumulateFinancials(tt.rows, map[string][]models.FinancialSummary{"BBCA": tt.stored})
+			if tt.wantMissed {
+				if !errors.Is(err, ErrMissingQuarter) {
+					t.Fatalf("err = %v, want ErrMissingQuarter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, r := range tt.rows {
+				if !almostEqual(r.NetIncome, tt.wantNet[i]) || !almostEqual(r.Revenue, tt.wantNet[i]*10) {
+					t.Errorf("%dQ%d net income/revenue = %v/%v, want %v/%v", r.FiscalYear, r.FiscalQuarter, r.NetIncome, r.Revenue, tt.wantNet[i], tt.wantNet[i]*10)
+				}
+				if !almostEqualPtr(r.EPS, tt.wantEPS[i]) {
+					t.Errorf("%dQ%d EPS = %s, want %s", r.FiscalYear, r.FiscalQuarter, fmtPtr(r.EPS), fmtPtr(tt.wantEPS[i]))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"math"
+	"sort"
+)
+
+const (
+	ZoneSupport    = "support"
+	ZoneResistance = "resistance"
+
+	DefaultLevelLookback = 120 // hari bursa
+
+	LevelSourceRolling = "rolling" // resistance_20/support_20 dari SQL
+	LevelSourceZones   = "zones"   // zona swing pivot
+
+	levelSwingWindow = 3
+	levelMinTouches  = 2
+)
+
+// swingHighs = index local maximum (high tertinggi di +-w bar)
+func swingHighs(bars []models.DailyBar, from, to, w int) []int {
+	idx := []int{}
+	for j := from + w; j <= to-w; j++ {
+		high := bars[j].High
+		if high <= 0 {
+			continue
+		}
+		isMax := true
+		for k := j - w; k <= j+w; k++ {
+			if k != j && bars[k].High > high {
+				isMax = false
+				break
+			}
+		}
+		if isMax {
+			idx = append(idx, j)
+		}
+	}
+	return idx
+}
+
+type pivot struct {
+	price  float64
+	idx    int
+	volume float64
+}
+
+// DetectPriceZones cluster swing high & low di lookback bar terakhir sampai i.
+// Pivot masuk cluster yang sama kalau jaraknya <= toleransi dari rata-rata cluster
+// (toleransi = max(0.5 ATR14, 1% harga)).
+func DetectPriceZones(bars []models.DailyBar, i, lookback int) []models.PriceZone {
+	from := max(0, i-lookback+1)
+	if i-from < levelSwingWindow*2 {
+		return []models.PriceZone{}
+	}
+
+	pivots := []pivot{}
+	for _, j := range swingHighs(bars, from, i, levelSwingWindow) {
+		pivots = append(pivots, pivot{price: bars[j].High, idx: j, volume: bars[j].Volume})
+	}
+	for _, j := range swingLows(bars, from, i, levelSwingWindow) {
+		pivots = append(pivots, pivot{price: bars[j].Low, idx: j, volume: bars[j].Volume})
+	}
+	sort.Slice(pivots, func(a, b int) bool { return pivots[a].price < pivots[b].price })
+
+	lastClose := bars[i].Close
+	series := &priceSeries{bars: bars}
+	tol := math.Max(0.5*series.atr(i, DefaultATRPeriod), lastClose*0.01)
+	avgVol := avgVolume(bars, from, i)
+
+	var clusters [][]pivot
+	for _, p := range pivots {
+		n := len(clusters)
+		if n > 0 {
+			sum := 0.0
+			for _, q := range clusters[n-1] {
+				sum += q.price
+			}
+			if p.price-sum/float64(len(clusters[n-1])) <= tol {
+				clusters[n-1] = append(clusters[n-1], p)
+				continue
+			}
+		}
+		clusters = append(clusters, []pivot{p})
+	}
+
+	zones := []models.PriceZone{}
+	for _, cl := range clusters {
+		if len(cl) < levelMinTouches {
+			continue
+		}
+
+		zone := models.PriceZone{Low: math.MaxFloat64, Touches: len(cl)}
+		first, last := cl[0].idx, cl[0].idx
+		sum, vol := 0.0, 0.0
+		for _, p := range cl {
+			zone.Low = math.Min(zone.Low, p.price)
+			zone.High = math.Max(zone.High, p.price)
+			sum += p.price
+			vol += p.volume
+			first, last = min(first, p.idx), max(last, p.idx)
+		}
+		zone.Mid = sum / float64(len(cl))
+		zone.FirstSeen = bars[first].TradeDate
+		zone.LastSeen = bars[last].TradeDate
+
+		// 60 poin dari sentuhan (maks 5), 25 dari kebaruan, 15 dari volume relatif di pivot
+		touchScore := float64(min(zone.Touches, 5)) / 5 * 60
+		recency := (1 - float64(i-last)/float64(i-from+1)) * 25
+		volScore := 0.0
+		if avgVol > 0 {
+			volScore = math.Min(vol/float64(len(cl))/avgVol, 2) / 2 * 15
+		}
+		zone.Strength = math.Round((touchScore+recency+volScore)*100) / 100
+
+		zone.Kind = ZoneSupport
+		if zone.Mid > lastClose {
+			zone.Kind = ZoneResistance
+		}
+		zones = append(zones, zone)
+	}
+
+	return zones
+}
+
+// PivotPointsFor hitung pivot classic & fibonacci dari satu bar (H/L/C)
+func PivotPointsFor(b models.DailyBar) []models.PivotPoints {
+	h, l, c := b.High, b.Low, b.Close
+	p := (h + l + c) / 3
+	r := h - l
+
+	classic := models.PivotPoints{
+		Method: "classic", P: p,
+		R1: 2*p - l, R2: p + r, R3: h + 2*(p-l),
+		S1: 2*p - h, S2: p - r, S3: l - 2*(h-p),
+	}
+	fib := models.PivotPoints{
+		Method: "fibonacci", P: p,
+		R1: p + 0.382*r, R2: p + 0.618*r, R3: p + r,
+		S1: p - 0.382*r, S2: p - 0.618*r, S3: p - r,
+	}
+	return []models.PivotPoints{classic, fib}
+}
+
+// StockLevelsAt = zona S/R, zona terdekat + jaraknya, dan pivot di bar i
+func StockLevelsAt(bars []models.DailyBar, i, lookback int) models.StockLevels {
+	lastClose := bars[i].Close
+	levels := models.StockLevels{
+		StockCode: bars[i].StockCode,
+		StockName: bars[i].StockName,
+		AsOf:      bars[i].TradeDate.Format("2006-01-02"),
+		LastClose: lastClose,
+		Zones:     DetectPriceZones(bars, i, lookback),
+		Pivots:    PivotPointsFor(bars[i]),
+	}
+	if lastClose <= 0 {
+		return levels
+	}
+
+	for k := range levels.Zones {
+		z := levels.Zones[k]
+		switch z.Kind {
+		case ZoneResistance:
+			if levels.NearestResistance == nil || z.Mid < levels.NearestResistance.Mid {
+				levels.NearestResistance = &levels.Zones[k]
+			}
+		case ZoneSupport:
+			if levels.NearestSupport == nil || z.Mid > levels.NearestSupport.Mid {
+				levels.NearestSupport = &levels.Zones[k]
+			}
+		}
+	}
+
+	if z := levels.NearestResistance; z != nil {
+		d := math.Max(0, z.Low-lastClose) / lastClose * 100
+		levels.DistToResistancePct = &d
+	}
+	if z := levels.NearestSupport; z != nil {
+		d := math.Max(0, lastClose-z.High) / lastClose * 100
+		levels.DistToSupportPct = &d
+	}
+
+	return levels
+}
+
+// GetStockLevels = level S/R satu saham sampai as_of
+func GetStockLevels(stockCode, asOf string, lookback int) (*models.StockLevels, error) {
+	series, err := loadSeriesAsOf([]string{stockCode}, asOf, levelLookbackDays(lookback))
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := series[stockCode]
+	if !ok || len(s.bars) == 0 {
+		return nil, fmt.Errorf("%w for %s up to %s", ErrNoTradingData, stockCode, asOf)
+	}
+
+	levels := StockLevelsAt(s.bars, len(s.bars)-1, lookback)
+	return &levels, nil
+}
+
+// levelLookbackDays konversi hari bursa ke hari kalender (+ buffer libur)
+func levelLookbackDays(lookback int) int {
+	return lookback*7/5 + 30
+}
+
+// ScreenerLevelsAsOf = zona S/R terdekat per saham buat label screener. Sama seperti
+// resistance_20/support_20, level dihitung sampai H-1 (relatif ke close kemarin), jadi close
+// hari ini di atas resistance berarti breakout. Resistance = batas atas zona, support = batas bawah.
+func ScreenerLevelsAsOf(asOf string, codes []string) (map[string]models.ScreenerLevels, error) {
+	result := map[string]models.ScreenerLevels{}
+	if len(codes) == 0 {
+		return result, nil
+	}
+
+	series, err := loadSeriesAsOf(codes, asOf, levelLookbackDays(DefaultLevelLookback+1))
+	if err != nil {
+		return nil, err
+	}
+
+	for code, s := range series {
+		if len(s.bars) < 2 {
+			continue
+		}
+		levels := StockLevelsAt(s.bars, len(s.bars)-2, DefaultLevelLookback)
+		var l models.ScreenerLevels
+		if z := levels.NearestResistance; z != nil {
+			l.Resistance = z.High
+		}
+		if z := levels.NearestSupport; z != nil {
+			l.Support = z.Low
+		}
+		result[code] = l
+	}
+	return result, nil
+}
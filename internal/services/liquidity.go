@@ -0,0 +1,227 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultLiquidityWindow   = 20
+	DefaultLiquidityPosition = 1_000_000_000
+	DefaultParticipationPct  = 10.0
+	MaxLiquidityWindow       = 250
+	liquidityAmihudValueUnit = 1_000_000 // Amihud per Rp1M
+)
+
+// LiquidityTier dari skor: A gampang keluar masuk, D hati-hati
+func LiquidityTier(score float64) string {
+	switch {
+	case score >= 80:
+		return "A"
+	case score >= 60:
+		return "B"
+	case score >= 40:
+		return "C"
+	}
+	return "D"
+}
+
+func median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	s := append([]float64{}, xs...)
+	sort.Float64s(s)
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
+
+func liquidityOf(bars []models.LiquidityBar, tradingDays int) models.StockLiquidity {
+	l := models.StockLiquidity{Window: tradingDays}
+	if len(bars) > 0 {
+		l.StockCode = bars[0].StockCode
+		l.StockName = bars[len(bars)-1].StockName
+	}
+
+	values := []float64{}
+	var sumValue, sumVolume, sumFreq, sumTurnover, sumAmihud float64
+	var turnoverDays, amihudDays int
+	for _, b := range bars {
+		sumValue += b.Value
+		sumVolume += b.Volume
+		sumFreq += b.Frequency
+		values = append(values, b.Value)
+		if b.Volume <= 0 {
+			continue
+		}
+		l.DaysTraded++
+
+		if b.TradeableShares > 0 {
+			sumTurnover += b.Volume / b.TradeableShares * 100
+			turnoverDays++
+		}
+		if b.Value > 0 && b.Previous > 0 {
+			ret := math.Abs(b.Close/b.Previous-1) * 100
+			sumAmihud += ret / (b.Value / liquidityAmihudValueUnit)
+			amihudDays++
+		}
+	}
+	// hari tanpa bar (suspend) dihitung nol
+	for len(values) < tradingDays {
+		values = append(values, 0)
+	}
+
+	l.ZeroVolumeDays = tradingDays - l.DaysTraded
+	if tradingDays > 0 {
+		l.AvgValue = sumValue / float64(tradingDays)
+		l.AvgVolume = sumVolume / float64(tradingDays)
+		l.AvgFrequency = sumFreq / float64(tradingDays)
+	}
+	l.MedianValue = median(values)
+	if turnoverDays > 0 {
+		t := sumTurnover / float64(turnoverDays)
+		l.FreeFloatTurnoverPct = &t
+	}
+	if amihudDays > 0 {
+		a := sumAmihud / float64(amihudDays)
+		l.Amihud = &a
+	}
+	return l
+}
+
+// percentileRank isi skor 0-100 per saham, higherBetter = false buat metrik yang makin kecil makin bagus.
+// Nilai sama dapat rata-rata rank grupnya, kode saham cuma buat urutan yang stabil.
+func percentileRank(values map[string]float64, higherBetter bool) map[string]float64 {
+	codes := make([]string, 0, len(values))
+	for code := range values {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		a, b := values[codes[i]], values[codes[j]]
+		if a != b {
+			return (a < b) == higherBetter
+		}
+		return codes[i] < codes[j]
+	})
+
+	out := make(map[string]float64, len(codes))
+	for start := 0; start < len(codes); {
+		end := start
+		for end+1 < len(codes) && values[codes[end+1]] == values[codes[start]] {
+			end++
+		}
+		score := 100.0
+		if len(codes) > 1 {
+			score = float64(start+end) / 2 / float64(len(codes)-1) * 100
+		}
+		for _, code := range codes[start : end+1] {
+			out[code] = score
+		}
+		start = end + 1
+	}
+	return out
+}
+
+// scoreLiquidity = rata-rata percentile ADV, turnover free float, Amihud dan hari tanpa transaksi
+func scoreLiquidity(rows map[string]*models.StockLiquidity) {
+	adv, turnover, amihud, zero := map[string]float64{}, map[string]float64{}, map[string]float64{}, map[string]float64{}
+	for code, l := range rows {
+		adv[code] = l.AvgValue
+		zero[code] = float64(l.ZeroVolumeDays)
+		if l.FreeFloatTurnoverPct != nil {
+			turnover[code] = *l.FreeFloatTurnoverPct
+		}
+		if l.Amihud != nil {
+			amihud[code] = *l.Amihud
+		}
+	}
+
+	ranks := []map[string]float64{
+		percentileRank(adv, true),
+		percentileRank(turnover, true),
+		percentileRank(amihud, false),
+		percentileRank(zero, false),
+	}
+	for code, l := range rows {
+		sum, n := 0.0, 0
+		for _, r := range ranks {
+			if v, ok := r[code]; ok {
+				sum += v
+				n++
+			}
+		}
+		if n > 0 {
+			l.LiquidityScore = sum / float64(n)
+		}
+		l.Tier = LiquidityTier(l.LiquidityScore)
+	}
+}
+
+func applyPositionSize(l *models.StockLiquidity, positionValue, participationPct float64) {
+	l.PositionValue = positionValue
+	l.ParticipationPct = participationPct
+	if capacity := l.AvgValue * participationPct / 100; capacity > 0 {
+		days := positionValue / capacity
+		l.DaysToEnter = &days
+	}
+}
+
+// marketLiquidity hitung likuiditas semua saham di `window` hari bursa terakhir <= as_of
+func marketLiquidity(asOf string, window int) (map[string]*models.StockLiquidity, error) {
+	end, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return nil, err
+	}
+	start := end.AddDate(0, 0, -levelLookbackDays(window)).Format("2006-01-02")
+
+	days, err := repositories.GetTradingDates(start, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if len(days) > window {
+		days = days[len(days)-window:]
+	}
+	if len(days) == 0 {
+		return nil, fmt.Errorf("%w up to %s", ErrNoTradingData, asOf)
+	}
+
+	bars, err := repositories.GetLiquidityBars(days[0].Format("2006-01-02"), days[len(days)-1].Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	byCode := map[string][]models.LiquidityBar{}
+	for _, b := range bars {
+		byCode[b.StockCode] = append(byCode[b.StockCode], b)
+	}
+
+	rows := make(map[string]*models.StockLiquidity, len(byCode))
+	for code, b := range byCode {
+		l := liquidityOf(b, len(days))
+		rows[code] = &l
+	}
+	scoreLiquidity(rows)
+	return rows, nil
+}
+
+// GetStockLiquidity = laporan likuiditas satu saham, skor relatif ke semua saham
+func GetStockLiquidity(stockCode, asOf string, window int, positionValue, participationPct float64) (*models.StockLiquidity, error) {
+	rows, err := marketLiquidity(asOf, window)
+	if err != nil {
+		return nil, err
+	}
+
+	l, ok := rows[stockCode]
+	if !ok {
+		return nil, fmt.Errorf("%w for %s in the last %d trading days up to %s", ErrNoTradingData, stockCode, window, asOf)
+	}
+	applyPositionSize(l, positionValue, participationPct)
+	return l, nil
+}
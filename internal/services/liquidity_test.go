@@ -0,0 +1,29 @@
+package services
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestPercentileRank(t *testing.T) {
+	tests := []struct {
+		name         string
+		values       map[string]float64
+		higherBetter bool
+		want         map[string]float64
+	}{
+		{"single stock", map[string]float64{"BBCA": 1}, true, map[string]float64{"BBCA": 100}},
+		{"higher better", map[string]float64{"AAAA": 1, "BBBB": 2, "CCCC": 3}, true, map[string]float64{"AAAA": 0, "BBBB": 50, "CCCC": 100}},
+		{"lower better", map[string]float64{"AAAA": 1, "BBBB": 2, "CCCC": 3}, false, map[string]float64{"AAAA": 100, "BBBB": 50, "CCCC": 0}},
+		{"ties share the average rank", map[string]float64{"AAAA": 0, "BBBB": 0, "CCCC": 5, "DDDD": 0, "EEEE": 9}, true,
+			map[string]float64{"AAAA": 25, "BBBB": 25, "CCCC": 75, "DDDD": 25, "EEEE": 100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentileRank(tt.values, tt.higherBetter); !maps.Equal(got, tt.want) {
+				t.Errorf("percentileRank = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
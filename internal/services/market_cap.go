@@ -0,0 +1,149 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SizeBig   = "big"
+	SizeMid   = "mid"
+	SizeSmall = "small"
+	SizeMicro = "micro"
+
+	RankByMarketCap = "market_cap"
+	RankByFreeFloat = "free_float_cap"
+
+	bigCapMin   = 10_000_000_000_000 // Rp10T
+	midCapMin   = 1_000_000_000_000  // Rp1T
+	smallCapMin = 250_000_000_000    // Rp250M
+)
+
+func SizeBuckets() []string {
+	return []string{SizeBig, SizeMid, SizeSmall, SizeMicro}
+}
+
+func MarketCapRankOptions() []string {
+	return []string{RankByMarketCap, RankByFreeFloat}
+}
+
+func SizeBucket(marketCap float64) string {
+	switch {
+	case marketCap >= bigCapMin:
+		return SizeBig
+	case marketCap >= midCapMin:
+		return SizeMid
+	case marketCap >= smallCapMin:
+		return SizeSmall
+	}
+	return SizeMicro
+}
+
+// ParseSizeBuckets pecah comma-separated dan validasi nama bucket
+func ParseSizeBuckets(raw []string) ([]string, error) {
+	buckets := []string{}
+	for _, r := range raw {
+		for _, b := range strings.Split(r, ",") {
+			b = strings.ToLower(strings.TrimSpace(b))
+			if b == "" {
+				continue
+			}
+			if !slices.Contains(SizeBuckets(), b) {
+				return nil, fmt.Errorf("unknown size %q, available: %v", b, SizeBuckets())
+			}
+			buckets = append(buckets, b)
+		}
+	}
+	return buckets, nil
+}
+
+func fillMarketCap(m *models.StockMarketCap) {
+	m.MarketCap = m.Close * m.ListedShares
+	if m.ListedShares > 0 {
+		m.FreeFloatPct = math.Min(m.TradeableShares/m.ListedShares*100, 100)
+	}
+	m.FreeFloatMarketCap = m.MarketCap * m.FreeFloatPct / 100
+	m.SizeBucket = SizeBucket(m.MarketCap)
+}
+
+// marketCapsAsOf = market cap semua saham di hari bursa terakhir <= as_of, rank berdasarkan market cap
+func marketCapsAsOf(asOf string) ([]models.StockMarketCap, time.Time, error) {
+	date, err := repositories.GetLatestTradingDate(asOf)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	rows, err := repositories.GetMarketCapsByDate(date.Format("2006-01-02"))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	out := make([]models.StockMarketCap, 0, len(rows))
+	for _, r := range rows {
+		if r.Close <= 0 || r.ListedShares <= 0 {
+			continue
+		}
+		fillMarketCap(&r)
+		out = append(out, r)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].MarketCap > out[j].MarketCap })
+	for i := range out {
+		out[i].Rank = i + 1
+	}
+	return out, date, nil
+}
+
+// GetMarketCapRanking = ranking market cap / free float market cap, opsional per bucket ukuran
+func GetMarketCapRanking(asOf, rankBy string, buckets []string, limit int) ([]models.StockMarketCap, time.Time, error) {
+	rows, date, err := marketCapsAsOf(asOf)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if rankBy == RankByFreeFloat {
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].FreeFloatMarketCap > rows[j].FreeFloatMarketCap })
+		for i := range rows {
+			rows[i].Rank = i + 1
+		}
+	}
+
+	out := []models.StockMarketCap{}
+	for _, r := range rows {
+		if len(buckets) > 0 && !slices.Contains(buckets, r.SizeBucket) {
+			continue
+		}
+		out = append(out, r)
+		if len(out) >= limit {
+			break
+		}
+	}
+	return out, date, nil
+}
+
+// GetStockMarketCap = market cap harian satu saham N hari bursa terakhir sampai as_of
+func GetStockMarketCap(stockCode, asOf string, days int) ([]models.StockMarketCap, error) {
+	end, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return nil, err
+	}
+	start := end.AddDate(0, 0, -levelLookbackDays(days)).Format("2006-01-02")
+
+	rows, err := repositories.GetStockMarketCaps(stockCode, start, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > days {
+		rows = rows[len(rows)-days:]
+	}
+	for i := range rows {
+		fillMarketCap(&rows[i])
+	}
+	return rows, nil
+}
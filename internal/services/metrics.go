@@ -0,0 +1,326 @@
+package services
+
+import (
+	"indonesia-stocks-api/internal/models"
+	"math"
+	"time"
+)
+
+const (
+	tradingDaysPerYear = 252
+
+	// Mode per sinyal nggak punya modal, trade bisa tumpang tindih dengan nominal tetap,
+	// jadi nggak ada kurva equity yang jujur buat CAGR, drawdown dan Sharpe.
+	EquityNotePerSignal = "equity metrics (CAGR, max drawdown, Sharpe) need portfolio.initial_capital > 0"
+)
+
+// alignBenchmark pisahkan close indeks jadi titik awal (close terakhir sebelum hari pertama)
+// dan kurva di hari-hari backtest. Kalau histori sebelum hari pertama nggak ada,
+// titik awalnya close hari pertama.
+func alignBenchmark(benchmark []models.IndexClose, days []time.Time) (models.IndexClose, []time.Time, []float64) {
+	var base models.IndexClose
+	var dates []time.Time
+	var values []float64
+	if len(days) == 0 {
+		return base, nil, nil
+	}
+	first, last := days[0], days[len(days)-1]
+
+	for _, b := range benchmark {
+		switch {
+		case b.TradeDate.Before(first):
+			base = b
+		case b.TradeDate.After(last):
+		default:
+			dates = append(dates, b.TradeDate)
+			values = append(values, b.Close)
+		}
+	}
+	if base.Close <= 0 && len(values) > 0 {
+		base = models.IndexClose{TradeDate: dates[0], Close: values[0]}
+	}
+	return base, dates, values
+}
+
+// curveStats hitung CAGR, drawdown, Sharpe, Sortino, Calmar dari kurva nilai harian.
+// startValue = nilai sebelum titik pertama (modal awal / close indeks sebelum hari pertama).
+func curveStats(values []float64, startValue, riskFreePct float64) models.CurveStats {
+	stats := models.CurveStats{StartValue: startValue, TradingDaysObserved: len(values)}
+	if len(values) == 0 || startValue <= 0 {
+		return stats
+	}
+
+	endValue := values[len(values)-1]
+	stats.EndValue = endValue
+	stats.TotalReturnPct = ((endValue - startValue) / startValue) * 100
+
+	years := float64(len(values)) / tradingDaysPerYear
+	if years > 0 && endValue > 0 {
+		stats.CAGRPct = (math.Pow(endValue/startValue, 1/years) - 1) * 100
+	}
+
+	// Drawdown: jarak dari puncak tertinggi sebelumnya, durasi = lama di bawah puncak
+	peak := startValue
+	peakIdx := -1
+	for i, v := range values {
+		if v >= peak {
+			peak = v
+			peakIdx = i
+			continue
+		}
+		dd := ((v - peak) / peak) * 100
+		if dd < stats.MaxDrawdownPct {
+			stats.MaxDrawdownPct = dd
+		}
+		if days := i - peakIdx; days > stats.MaxDrawdownDays {
+			stats.MaxDrawdownDays = days
+		}
+	}
+
+	returns := dailyReturns(values, startValue)
+	rfDaily := riskFreePct / 100 / tradingDaysPerYear
+
+	mean, std := meanStd(returns)
+	stats.VolatilityPct = std * math.Sqrt(tradingDaysPerYear) * 100
+	if std > 0 {
+		stats.Sharpe = (mean - rfDaily) / std * math.Sqrt(tradingDaysPerYear)
+	}
+
+	downside := 0.0
+	for _, r := range returns {
+		if d := r - rfDaily; d < 0 {
+			downside += d * d
+		}
+	}
+	if len(returns) > 0 && downside > 0 {
+		dd := math.Sqrt(downside / float64(len(returns)))
+		sortino := (mean - rfDaily) / dd * math.Sqrt(tradingDaysPerYear)
+		stats.Sortino = &sortino
+	}
+
+	if stats.MaxDrawdownPct < 0 {
+		calmar := stats.CAGRPct / math.Abs(stats.MaxDrawdownPct)
+		stats.Calmar = &calmar
+	}
+
+	return stats
+}
+
+func dailyReturns(values []float64, startValue float64) []float64 {
+	returns := make([]float64, 0, len(values))
+	prev := startValue
+	for _, v := range values {
+		if prev > 0 {
+			returns = append(returns, (v-prev)/prev)
+		}
+		prev = v
+	}
+	return returns
+}
+
+func meanStd(xs []float64) (float64, float64) {
+	if len(xs) == 0 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	mean := sum / float64(len(xs))
+	if len(xs) < 2 {
+		return mean, 0
+	}
+	sq := 0.0
+	for _, x := range xs {
+		sq += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(xs)-1))
+}
+
+// monthlyReturns = return per bulan dari nilai akhir bulan vs akhir bulan sebelumnya
+func monthlyReturns(dates []time.Time, values []float64, startValue float64) (map[string]float64, []string) {
+	result := map[string]float64{}
+	order := []string{}
+	prevMonthEnd := startValue
+
+	for i := range values {
+		month := dates[i].Format("2006-01")
+		lastOfMonth := i == len(values)-1 || dates[i+1].Format("2006-01") != month
+		if !lastOfMonth {
+			continue
+		}
+		if prevMonthEnd > 0 {
+			result[month] = ((values[i] - prevMonthEnd) / prevMonthEnd) * 100
+			order = append(order, month)
+		}
+		prevMonthEnd = values[i]
+	}
+
+	return result, order
+}
+
+// ComputeBacktestMetrics gabungan metrik per trade, metrik equity curve, dan perbandingan benchmark
+func ComputeBacktestMetrics(report *models.BacktestReport, days []time.Time, benchmark []models.IndexClose) models.BacktestMetrics {
+	metrics := models.BacktestMetrics{MonthlyReturns: []models.MonthlyReturn{}}
+
+	// 1. Metrik per trade (berlaku di kedua mode)
+	var grossProfit, grossLoss, sumWin, sumLoss, sumPnL, sumReturn, sumHolding float64
+	var wins, losses int
+	for _, t := range report.Trades {
+		sumPnL += t.NetPnL
+		sumReturn += t.NetReturnPct
+		sumHolding += float64(t.HoldingDays)
+		// Break even (PnL 0) nggak dihitung menang maupun kalah, sama seperti summarizeTrades
+		switch {
+		case t.NetPnL > 0:
+			grossProfit += t.NetPnL
+			sumWin += t.NetReturnPct
+			wins++
+		case t.NetPnL < 0:
+			grossLoss += -t.NetPnL
+			sumLoss += t.NetReturnPct
+			losses++
+		}
+	}
+	if n := float64(len(report.Trades)); n > 0 {
+		metrics.ExpectancyPct = sumReturn / n
+		metrics.ExpectancyIDR = sumPnL / n
+		metrics.AvgHoldingDays = sumHolding / n
+	}
+	if wins > 0 {
+		metrics.AvgWinPct = sumWin / float64(wins)
+	}
+	if losses > 0 {
+		metrics.AvgLossPct = sumLoss / float64(losses)
+	}
+	if grossLoss > 0 {
+		pf := grossProfit / grossLoss
+		metrics.ProfitFactor = &pf
+	}
+
+	metrics.ExposurePct = exposurePct(report, days)
+
+	// 2. Metrik equity curve (mode portfolio)
+	curveDates := make([]time.Time, 0, len(report.EquityCurve))
+	curveValues := make([]float64, 0, len(report.EquityCurve))
+	for _, p := range report.EquityCurve {
+		curveDates = append(curveDates, p.Date)
+		curveValues = append(curveValues, p.Equity)
+	}
+
+	if len(curveValues) > 0 {
+		stats := curveStats(curveValues, report.Config.Portfolio.InitialCapital, report.Config.RiskFreeRatePct)
+		metrics.Equity = &stats
+	} else {
+		metrics.EquityNote = EquityNotePerSignal
+	}
+
+	// 3. Benchmark buy & hold di periode yang sama. Titik awalnya close sebelum hari pertama,
+	// sama seperti modal strategi yang sudah ada sebelum hari pertama.
+	base, benchDates, benchValues := alignBenchmark(benchmark, days)
+
+	benchMonthly := map[string]float64{}
+	if len(benchValues) > 0 && base.Close > 0 {
+		benchStats := curveStats(benchValues, base.Close, report.Config.RiskFreeRatePct)
+		comparison := &models.BenchmarkComparison{
+			IndexCode: report.Config.BenchmarkIndex,
+			Stats:     benchStats,
+		}
+
+		if metrics.Equity != nil {
+			excess := metrics.Equity.TotalReturnPct - benchStats.TotalReturnPct
+			comparison.ExcessReturnPct = &excess
+			betaDates, betaValues := benchDates, benchValues
+			if base.TradeDate.Before(benchDates[0]) {
+				betaDates = append([]time.Time{base.TradeDate}, benchDates...)
+				betaValues = append([]float64{base.Close}, benchValues...)
+			}
+			comparison.Beta, comparison.Correlation = betaCorrelation(curveDates, curveValues, report.Config.Portfolio.InitialCapital, betaDates, betaValues)
+		}
+
+		benchMonthly, _ = monthlyReturns(benchDates, benchValues, base.Close)
+		metrics.Benchmark = comparison
+	}
+
+	// 4. Tabel return bulanan (mode portfolio)
+	if len(curveValues) > 0 {
+		monthly, order := monthlyReturns(curveDates, curveValues, report.Config.Portfolio.InitialCapital)
+		for _, m := range order {
+			row := models.MonthlyReturn{Month: m, ReturnPct: monthly[m]}
+			if b, ok := benchMonthly[m]; ok {
+				row.BenchmarkReturnPct = &b
+			}
+			metrics.MonthlyReturns = append(metrics.MonthlyReturns, row)
+		}
+	}
+
+	return metrics
+}
+
+// exposurePct: mode portfolio dari equity curve, mode per sinyal dari rentang tanggal trade
+func exposurePct(report *models.BacktestReport, days []time.Time) float64 {
+	if len(report.EquityCurve) > 0 {
+		invested := 0
+		for _, p := range report.EquityCurve {
+			if p.OpenPositions > 0 {
+				invested++
+			}
+		}
+		return (float64(invested) / float64(len(report.EquityCurve))) * 100
+	}
+
+	if len(days) == 0 {
+		return 0
+	}
+	invested := 0
+	for _, d := range days {
+		for _, t := range report.Trades {
+			if !d.Before(t.EntryDate) && !d.After(t.ExitDate) {
+				invested++
+				break
+			}
+		}
+	}
+	return (float64(invested) / float64(len(days))) * 100
+}
+
+// betaCorrelation dari return harian strategi vs indeks di tanggal yang sama
+func betaCorrelation(dates []time.Time, values []float64, start float64, benchDates []time.Time, benchValues []float64) (*float64, *float64) {
+	benchReturn := map[string]float64{}
+	for i := 1; i < len(benchValues); i++ {
+		if benchValues[i-1] > 0 {
+			benchReturn[benchDates[i].Format("2006-01-02")] = (benchValues[i] - benchValues[i-1]) / benchValues[i-1]
+		}
+	}
+
+	var xs, ys []float64
+	prev := start
+	for i, v := range values {
+		if b, ok := benchReturn[dates[i].Format("2006-01-02")]; ok && prev > 0 {
+			xs = append(xs, b)
+			ys = append(ys, (v-prev)/prev)
+		}
+		prev = v
+	}
+	if len(xs) < 2 {
+		return nil, nil
+	}
+
+	mx, sx := meanStd(xs)
+	my, sy := meanStd(ys)
+	cov := 0.0
+	for i := range xs {
+		cov += (xs[i] - mx) * (ys[i] - my)
+	}
+	cov /= float64(len(xs) - 1)
+
+	if sx == 0 {
+		return nil, nil
+	}
+	beta := cov / (sx * sx)
+	if sy == 0 {
+		return &beta, nil
+	}
+	corr := cov / (sx * sy)
+	return &beta, &corr
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"indonesia-stocks-api/internal/models"
+	"testing"
+)
+
+func TestComputeBacktestMetricsTradeBuckets(t *testing.T) {
+	report := &models.BacktestReport{Trades: []models.BacktestTrade{
+		{NetPnL: 200, NetReturnPct: 4},
+		{NetPnL: -100, NetReturnPct: -2},
+		{NetPnL: 0, NetReturnPct: 0},
+	}}
+
+	m := ComputeBacktestMetrics(report, nil, nil)
+	if !almostEqual(m.AvgWinPct, 4) || !almostEqual(m.AvgLossPct, -2) {
+		t.Errorf("avg win/loss = %v/%v, want 4/-2 (break even in neither bucket)", m.AvgWinPct, m.AvgLossPct)
+	}
+	if !almostEqualPtr(m.ProfitFactor, float64Ptr(2)) {
+		t.Errorf("profit factor = %s, want 2", fmtPtr(m.ProfitFactor))
+	}
+	if !almostEqual(m.ExpectancyPct, 2.0/3) {
+		t.Errorf("expectancy = %v, want %v", m.ExpectancyPct, 2.0/3)
+	}
+}
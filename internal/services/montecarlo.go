@@ -0,0 +1,270 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+const (
+	MonteCarloBootstrap = "bootstrap" // ambil trade acak dengan pengembalian
+	MonteCarloReshuffle = "reshuffle" // acak urutan trade yang sama
+
+	defaultMonteCarloSims    = 1000
+	maxMonteCarloSims        = 20000
+	defaultMonteCarloSeed    = 42
+	defaultMonteCarloCapital = 100_000_000
+	defaultRuinDrawdownPct   = 50
+	defaultStreakAlert       = 5
+)
+
+var ErrNoTrades = errors.New("no trades to simulate")
+
+// NormalizeMonteCarloConfig validasi sumber trade dan isi default yang nggak tergantung backtest.
+// Seed yang nggak dikirim pakai seed default biar tetap reproducible, seed 0 tetap dipakai apa adanya.
+func NormalizeMonteCarloConfig(cfg *models.MonteCarloConfig) error {
+	switch {
+	case cfg.Backtest == nil && cfg.RunID == nil:
+		return fmt.Errorf("backtest or run_id is required")
+	case cfg.Backtest != nil && cfg.RunID != nil:
+		return fmt.Errorf("use either backtest or run_id, not both")
+	case cfg.Backtest != nil:
+		if err := NormalizeBacktestConfig(cfg.Backtest); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Simulations <= 0 {
+		cfg.Simulations = defaultMonteCarloSims
+	}
+	if cfg.Simulations > maxMonteCarloSims {
+		return fmt.Errorf("simulations max %d", maxMonteCarloSims)
+	}
+	if cfg.Seed == nil {
+		seed := int64(defaultMonteCarloSeed)
+		cfg.Seed = &seed
+	}
+
+	switch cfg.Method {
+	case "":
+		cfg.Method = "both"
+	case MonteCarloBootstrap, MonteCarloReshuffle, "both":
+	default:
+		return fmt.Errorf("invalid method %q, use %s, %s or both", cfg.Method, MonteCarloBootstrap, MonteCarloReshuffle)
+	}
+
+	if cfg.RuinDrawdownPct <= 0 {
+		cfg.RuinDrawdownPct = defaultRuinDrawdownPct
+	}
+	if cfg.LosingStreakAlertMin <= 0 {
+		cfg.LosingStreakAlertMin = defaultStreakAlert
+	}
+	return nil
+}
+
+// applyPortfolioDefaults isi modal & ukuran posisi dari config portfolio backtest sumbernya
+func applyPortfolioDefaults(cfg *models.MonteCarloConfig, portfolio models.PortfolioConfig) {
+	if cfg.InitialCapital <= 0 {
+		cfg.InitialCapital = defaultMonteCarloCapital
+		if portfolio.InitialCapital > 0 {
+			cfg.InitialCapital = portfolio.InitialCapital
+		}
+	}
+	if cfg.PositionFractionPct <= 0 {
+		cfg.PositionFractionPct = 10
+		if portfolio.InitialCapital > 0 && portfolio.MaxPositions > 0 {
+			cfg.PositionFractionPct = 100 / float64(portfolio.MaxPositions)
+		}
+	}
+}
+
+// RunMonteCarlo ambil daftar trade (dari run tersimpan atau backtest baru), lalu simulasi ulang
+func RunMonteCarlo(cfg models.MonteCarloConfig) (*models.MonteCarloReport, error) {
+	if err := NormalizeMonteCarloConfig(&cfg); err != nil {
+		return nil, err
+	}
+
+	var trades []models.BacktestTrade
+	runID, saveError := cfg.RunID, ""
+	if cfg.RunID != nil {
+		detail, err := GetBacktestRunDetail(*cfg.RunID)
+		if err != nil {
+			return nil, err
+		}
+		trades = detail.Trades
+		cfg.Backtest = &detail.Run.Config
+	} else {
+		start := time.Now()
+		backtest, err := RunBacktest(*cfg.Backtest)
+		if err != nil {
+			return nil, err
+		}
+		trades = backtest.Trades
+		cfg.Backtest = &backtest.Config
+		runID, saveError = saveRun(backtest, time.Since(start))
+	}
+
+	applyPortfolioDefaults(&cfg, cfg.Backtest.Portfolio)
+	report, err := MonteCarloFromTrades(trades, cfg)
+	if err != nil {
+		return nil, err
+	}
+	report.RunID = runID
+	report.SaveError = saveError
+	return report, nil
+}
+
+// MonteCarloFromTrades simulasi dari return bersih tiap trade.
+// Tiap trade memakai PositionFractionPct dari equity berjalan (compounding).
+func MonteCarloFromTrades(trades []models.BacktestTrade, cfg models.MonteCarloConfig) (*models.MonteCarloReport, error) {
+	if len(trades) == 0 {
+		return nil, ErrNoTrades
+	}
+
+	returns := make([]float64, len(trades))
+	for i, t := range trades {
+		returns[i] = t.NetReturnPct / 100
+	}
+
+	report := &models.MonteCarloReport{
+		Config:      cfg,
+		TotalTrades: len(trades),
+		Original:    simulatePath(returns, cfg),
+	}
+
+	methods := []string{cfg.Method}
+	if cfg.Method == "both" {
+		methods = []string{MonteCarloBootstrap, MonteCarloReshuffle}
+	}
+
+	for i, method := range methods {
+		// Tiap metode punya generator sendiri dari seed yang sama + offset, jadi urutan metode nggak ngaruh
+		rng := rand.New(rand.NewSource(*cfg.Seed + int64(i)))
+		report.Distributions = append(report.Distributions, runSimulations(returns, method, rng, cfg))
+	}
+
+	return report, nil
+}
+
+func runSimulations(returns []float64, method string, rng *rand.Rand, cfg models.MonteCarloConfig) models.MonteCarloDistribution {
+	n := cfg.Simulations
+	finals := make([]float64, n)
+	totalReturns := make([]float64, n)
+	drawdowns := make([]float64, n)
+	streaks := make([]float64, n)
+
+	var losses, ruins, longStreaks int
+	sample := make([]float64, len(returns))
+
+	for s := 0; s < n; s++ {
+		switch method {
+		case MonteCarloBootstrap:
+			for i := range sample {
+				sample[i] = returns[rng.Intn(len(returns))]
+			}
+		case MonteCarloReshuffle:
+			copy(sample, returns)
+			rng.Shuffle(len(sample), func(i, j int) { sample[i], sample[j] = sample[j], sample[i] })
+		}
+
+		path := simulatePath(sample, cfg)
+		finals[s] = path.FinalEquity
+		totalReturns[s] = path.TotalReturnPct
+		drawdowns[s] = path.MaxDrawdownPct
+		streaks[s] = float64(path.MaxLosingStreak)
+
+		if path.FinalEquity < cfg.InitialCapital {
+			losses++
+		}
+		if -path.MaxDrawdownPct >= cfg.RuinDrawdownPct {
+			ruins++
+		}
+		if path.MaxLosingStreak >= cfg.LosingStreakAlertMin {
+			longStreaks++
+		}
+	}
+
+	return models.MonteCarloDistribution{
+		Method:            method,
+		Simulations:       n,
+		FinalEquity:       percentiles(finals),
+		TotalReturnPct:    percentiles(totalReturns),
+		MaxDrawdownPct:    percentiles(drawdowns),
+		MaxLosingStreak:   percentiles(streaks),
+		ProbLossPct:       float64(losses) / float64(n) * 100,
+		ProbRuinPct:       float64(ruins) / float64(n) * 100,
+		ProbLongStreakPct: float64(longStreaks) / float64(n) * 100,
+	}
+}
+
+func simulatePath(returns []float64, cfg models.MonteCarloConfig) models.PathStats {
+	fraction := cfg.PositionFractionPct / 100
+	equity := cfg.InitialCapital
+	peak := equity
+	stats := models.PathStats{}
+	streak := 0
+
+	for _, r := range returns {
+		equity *= 1 + fraction*r
+		if equity > peak {
+			peak = equity
+		}
+		if dd := ((equity - peak) / peak) * 100; dd < stats.MaxDrawdownPct {
+			stats.MaxDrawdownPct = dd
+		}
+
+		// Break even (return 0) nggak dihitung kalah, memutus streak
+		if r < 0 {
+			streak++
+			if streak > stats.MaxLosingStreak {
+				stats.MaxLosingStreak = streak
+			}
+		} else {
+			streak = 0
+		}
+	}
+
+	stats.FinalEquity = equity
+	stats.TotalReturnPct = ((equity - cfg.InitialCapital) / cfg.InitialCapital) * 100
+	return stats
+}
+
+// percentiles pakai interpolasi linear antar rank
+func percentiles(values []float64) models.Percentiles {
+	if len(values) == 0 {
+		return models.Percentiles{}
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	at := func(p float64) float64 {
+		pos := p / 100 * float64(len(sorted)-1)
+		lo := int(math.Floor(pos))
+		hi := int(math.Ceil(pos))
+		if lo == hi {
+			return sorted[lo]
+		}
+		return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+	}
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+
+	return models.Percentiles{
+		P5:   at(5),
+		P25:  at(25),
+		P50:  at(50),
+		P75:  at(75),
+		P95:  at(95),
+		Mean: sum / float64(len(sorted)),
+		Min:  sorted[0],
+		Max:  sorted[len(sorted)-1],
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"indonesia-stocks-api/internal/models"
+	"reflect"
+	"testing"
+)
+
+func monteCarloTrades(returnsPct ...float64) []models.BacktestTrade {
+	trades := make([]models.BacktestTrade, 0, len(returnsPct))
+	for _, r := range returnsPct {
+		trades = append(trades, models.BacktestTrade{NetReturnPct: r})
+	}
+	return trades
+}
+
+func monteCarloConfig(seed int64, method string) models.MonteCarloConfig {
+	return models.MonteCarloConfig{
+		Simulations:          200,
+		Seed:                 &seed,
+		Method:               method,
+		InitialCapital:       1000,
+		PositionFractionPct:  100,
+		RuinDrawdownPct:      50,
+		LosingStreakAlertMin: 2,
+	}
+}
+
+func TestMonteCarloFromTradesIsReproducible(t *testing.T) {
+	trades := monteCarloTrades(10, -5, 0, 8, -12, 3, -2)
+
+	first, err := MonteCarloFromTrades(trades, monteCarloConfig(7, "both"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := MonteCarloFromTrades(trades, monteCarloConfig(7, "both"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first.Distributions, second.Distributions) {
+		t.Errorf("same seed gave different distributions:\n%+v\n%+v", first.Distributions, second.Distributions)
+	}
+
+	other, err := MonteCarloFromTrades(trades, monteCarloConfig(8, "both"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(first.Distributions, other.Distributions) {
+		t.Error("different seeds gave identical distributions")
+	}
+}
+
+func TestMonteCarloFromTradesPath(t *testing.T) {
+	// 1000 -> 1100 -> 990 -> 990 -> 792 -> 831.6, puncak 1100
+	trades := monteCarloTrades(10, -10, 0, -20, 5)
+
+	tests := []struct {
+		name string
+		cfg  models.MonteCarloConfig
+	}{
+		{"reshuffle", monteCarloConfig(1, MonteCarloReshuffle)},
+		{"bootstrap", monteCarloConfig(1, MonteCarloBootstrap)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := MonteCarloFromTrades(trades, tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			o := report.Original
+			if !almostEqual(o.FinalEquity, 831.6) || !almostEqual(o.TotalReturnPct, -16.84) {
+				t.Errorf("original final/return = %v/%v, want 831.6/-16.84", o.FinalEquity, o.TotalReturnPct)
+			}
+			if !almostEqual(o.MaxDrawdownPct, -28) {
+				t.Errorf("original max drawdown = %v, want -28", o.MaxDrawdownPct)
+			}
+			// trade break even memutus streak: -10, 0, -20 = dua streak 1
+			if o.MaxLosingStreak != 1 {
+				t.Errorf("original max losing streak = %d, want 1", o.MaxLosingStreak)
+			}
+
+			if len(report.Distributions) != 1 || report.Distributions[0].Method != tt.cfg.Method {
+				t.Fatalf("distributions = %+v, want one %s", report.Distributions, tt.cfg.Method)
+			}
+			d := report.Distributions[0]
+			if tt.cfg.Method == MonteCarloReshuffle {
+				// urutan beda, hasil kali return sama
+				if !almostEqual(d.FinalEquity.Min, 831.6) || !almostEqual(d.FinalEquity.Max, 831.6) {
+					t.Errorf("reshuffle final equity range = %v-%v, want 831.6", d.FinalEquity.Min, d.FinalEquity.Max)
+				}
+				if d.ProbLossPct != 100 {
+					t.Errorf("reshuffle prob loss = %v, want 100", d.ProbLossPct)
+				}
+			}
+			if d.MaxLosingStreak.Max > float64(len(trades)) || d.MaxDrawdownPct.Max > 0 {
+				t.Errorf("streak max %v, drawdown max %v out of range", d.MaxLosingStreak.Max, d.MaxDrawdownPct.Max)
+			}
+		})
+	}
+}
+
+func TestMonteCarloFromTradesWithoutTrades(t *testing.T) {
+	if _, err := MonteCarloFromTrades(nil, monteCarloConfig(1, "both")); !errors.Is(err, ErrNoTrades) {
+		t.Errorf("err = %v, want ErrNoTrades", err)
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/helpers"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultNonRegularHistory  = 60  // hari bursa pembanding
+	DefaultNonRegularMinRatio = 3.0 // value nego >= 3x rata-rata histori
+	DefaultNonRegularMinValue = 1e9 // crossing < 1M dianggap noise
+	nonRegularMinZScore       = 2.0
+)
+
+// nonRegularAt bandingkan transaksi nego di rows[i] dengan `history` hari sebelumnya (rows satu saham, urut tanggal naik)
+func nonRegularAt(rows []models.NonRegularRow, i, history int, minRatio, minValue float64) models.NonRegularActivity {
+	r := rows[i]
+	a := models.NonRegularActivity{
+		StockCode:           r.StockCode,
+		StockName:           r.StockName,
+		TradeDate:           r.TradeDate,
+		Close:               r.Close,
+		NonRegularVolume:    r.NonRegularVolume,
+		NonRegularValue:     r.NonRegularValue,
+		FormattedValue:      helpers.FormatBigNumber(r.NonRegularValue),
+		NonRegularFrequency: r.NonRegularFrequency,
+	}
+	if r.Value > 0 {
+		a.NonRegularSharePct = r.NonRegularValue / r.Value * 100
+	}
+	if r.NonRegularVolume > 0 {
+		price := r.NonRegularValue / r.NonRegularVolume
+		a.CrossingPrice = &price
+		if r.Close > 0 {
+			premium := (price - r.Close) / r.Close * 100
+			a.CrossingPremiumPct = &premium
+		}
+	}
+
+	hist := make([]float64, 0, history)
+	for _, h := range rows[max(0, i-history):i] {
+		hist = append(hist, h.NonRegularValue)
+		if h.NonRegularValue > 0 {
+			a.ActiveDays++
+		}
+	}
+	a.HistoryDays = len(hist)
+
+	mean, std := meanStd(hist)
+	a.AvgHistoryValue = mean
+	if mean > 0 {
+		ratio := r.NonRegularValue / mean
+		a.Ratio = &ratio
+	}
+	if std > 0 {
+		z := (r.NonRegularValue - mean) / std
+		a.ZScore = &z
+	}
+
+	// Unusual: nominal cukup besar, dan jauh di atas kebiasaan (atau belum pernah ada nego sama sekali)
+	if r.NonRegularValue >= minValue {
+		switch {
+		case a.Ratio == nil:
+			a.Unusual = true
+		case *a.Ratio >= minRatio:
+			a.Unusual = a.ZScore == nil || *a.ZScore >= nonRegularMinZScore
+		}
+	}
+
+	return a
+}
+
+// groupNonRegular pecah rows (urut stock_code, trade_date) per saham
+func groupNonRegular(rows []models.NonRegularRow) map[string][]models.NonRegularRow {
+	grouped := map[string][]models.NonRegularRow{}
+	for start := 0; start < len(rows); {
+		end := start
+		for end < len(rows) && rows[end].StockCode == rows[start].StockCode {
+			end++
+		}
+		grouped[rows[start].StockCode] = rows[start:end]
+		start = end
+	}
+	return grouped
+}
+
+// nonRegularAsOf = aktivitas nego per saham di hari bursa terakhir <= as_of
+func nonRegularAsOf(asOf string, history int, minRatio, minValue float64, codes []string) (map[string]models.NonRegularActivity, time.Time, error) {
+	rows, err := repositories.GetNonRegularHistory(asOf, history+1, codes)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var latest time.Time
+	for _, r := range rows {
+		if r.TradeDate.After(latest) {
+			latest = r.TradeDate
+		}
+	}
+
+	result := map[string]models.NonRegularActivity{}
+	for code, stockRows := range groupNonRegular(rows) {
+		last := len(stockRows) - 1
+		if !stockRows[last].TradeDate.Equal(latest) {
+			continue // nggak ada data di hari terakhir
+		}
+		result[code] = nonRegularAt(stockRows, last, history, minRatio, minValue)
+	}
+	return result, latest, nil
+}
+
+// ScanNonRegular = saham dengan transaksi nego nggak biasa di hari bursa terakhir <= as_of
+func ScanNonRegular(asOf string, history int, minRatio, minValue float64, limit int) (*models.NonRegularScan, error) {
+	activity, latest, err := nonRegularAsOf(asOf, history, minRatio, minValue, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(activity) == 0 {
+		return nil, fmt.Errorf("%w up to %s", ErrNoTradingData, asOf)
+	}
+
+	data := []models.NonRegularActivity{}
+	for _, a := range activity {
+		if a.Unusual {
+			data = append(data, a)
+		}
+	}
+
+	// belum pernah nego (ratio null) ditaruh paling atas, lalu ratio terbesar
+	ratioOf := func(a models.NonRegularActivity) float64 {
+		if a.Ratio == nil {
+			return math.Inf(1)
+		}
+		return *a.Ratio
+	}
+	sort.Slice(data, func(a, b int) bool {
+		ra, rb := ratioOf(data[a]), ratioOf(data[b])
+		if ra != rb {
+			return ra > rb
+		}
+		return data[a].NonRegularValue > data[b].NonRegularValue
+	})
+	if len(data) > limit {
+		data = data[:limit]
+	}
+
+	return &models.NonRegularScan{
+		AsOf:        asOf,
+		TradeDate:   latest,
+		HistoryDays: history,
+		MinRatio:    minRatio,
+		MinValue:    minValue,
+		Total:       len(data),
+		Data:        data,
+	}, nil
+}
+
+// GetStockNonRegular = histori nego harian satu saham (terbaru dulu) + close hari berikutnya
+// (selama masih <= as_of) buat lihat apakah crossing besar diikuti pergerakan harga
+func GetStockNonRegular(stockCode, asOf string, days, history int) ([]models.NonRegularActivity, error) {
+	end, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return nil, err
+	}
+	start := end.AddDate(0, 0, -(days+history)*7/5-30).Format("2006-01-02")
+
+	rows, err := repositories.GetStockNonRegular(stockCode, start, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	last := len(rows) - 1
+	if last < 0 {
+		return nil, fmt.Errorf("%w for %s up to %s", ErrNoTradingData, stockCode, asOf)
+	}
+
+	result := []models.NonRegularActivity{}
+	for i := last; i > last-days && i >= 0; i-- {
+		a := nonRegularAt(rows, i, history, DefaultNonRegularMinRatio, DefaultNonRegularMinValue)
+		if i < last {
+			next := rows[i+1].Close
+			a.NextClose = &next
+			if a.CrossingPrice != nil && *a.CrossingPrice > 0 {
+				pct := (next - *a.CrossingPrice) / *a.CrossingPrice * 100
+				a.NextVsCrossingPct = &pct
+			}
+		}
+		result = append(result, a)
+	}
+
+	return result, nil
+}
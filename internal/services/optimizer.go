@@ -0,0 +1,363 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	MetricSharpe       = "sharpe"
+	MetricSortino      = "sortino"
+	MetricCalmar       = "calmar"
+	MetricCAGR         = "cagr"
+	MetricTotalReturn  = "total_return"
+	MetricProfitFactor = "profit_factor"
+	MetricExpectancy   = "expectancy"
+	MetricWinRate      = "win_rate"
+	MetricNetPnL       = "net_pnl"
+
+	maxSweepCombinations = 500
+	defaultSweepTopN     = 20
+)
+
+// SweepMetrics = metrik yang bisa dipakai buat ranking kombinasi param
+func SweepMetrics() []string {
+	return []string{
+		MetricSharpe, MetricSortino, MetricCalmar, MetricCAGR, MetricTotalReturn,
+		MetricProfitFactor, MetricExpectancy, MetricWinRate, MetricNetPnL,
+	}
+}
+
+// metricScore ambil nilai metrik dari hasil backtest, nil kalau nggak terdefinisi
+func metricScore(report *models.BacktestReport, metric string) *float64 {
+	if report.Summary.TotalTrades == 0 {
+		return nil
+	}
+
+	m := report.Metrics
+	var v float64
+	switch metric {
+	case MetricSharpe, MetricSortino, MetricCalmar, MetricCAGR, MetricTotalReturn:
+		if m.Equity == nil {
+			return nil
+		}
+		switch metric {
+		case MetricSharpe:
+			v = m.Equity.Sharpe
+		case MetricSortino:
+			return m.Equity.Sortino
+		case MetricCalmar:
+			return m.Equity.Calmar
+		case MetricCAGR:
+			v = m.Equity.CAGRPct
+		case MetricTotalReturn:
+			v = m.Equity.TotalReturnPct
+		}
+	case MetricProfitFactor:
+		return m.ProfitFactor
+	case MetricExpectancy:
+		v = m.ExpectancyPct
+	case MetricWinRate:
+		v = report.Summary.WinRate
+	case MetricNetPnL:
+		v = report.Summary.TotalNetPnL
+	}
+	return &v
+}
+
+// expandGrid = cartesian product semua kandidat nilai param
+func expandGrid(grid map[string][]float64) []models.ScreenerParams {
+	keys := make([]string, 0, len(grid))
+	for k := range grid {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	combos := []models.ScreenerParams{{}}
+	for _, k := range keys {
+		next := make([]models.ScreenerParams, 0, len(combos)*len(grid[k]))
+		for _, base := range combos {
+			for _, v := range grid[k] {
+				p := models.ScreenerParams{}
+				for bk, bv := range base {
+					p[bk] = bv
+				}
+				p[k] = v
+				next = append(next, p)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// NormalizeSweepConfig validasi grid & isi default metric/workers
+func NormalizeSweepConfig(cfg *models.SweepConfig) error {
+	if err := NormalizeBacktestConfig(&cfg.Base); err != nil {
+		return err
+	}
+	if len(cfg.Grid) == 0 {
+		return fmt.Errorf("grid is required")
+	}
+
+	total := 1
+	for key, values := range cfg.Grid {
+		if len(values) == 0 {
+			return fmt.Errorf("grid %q has no values", key)
+		}
+		total *= len(values)
+	}
+	if err := validateScreenerParams(cfg.Base.Screener, gridKeys(cfg.Grid)); err != nil {
+		return err
+	}
+	if total > maxSweepCombinations {
+		return fmt.Errorf("grid has %d combinations, max %d", total, maxSweepCombinations)
+	}
+
+	if cfg.Metric == "" {
+		cfg.Metric = MetricExpectancy
+		if cfg.Base.Portfolio.InitialCapital > 0 {
+			cfg.Metric = MetricSharpe
+		}
+	}
+	valid := false
+	for _, m := range SweepMetrics() {
+		if cfg.Metric == m {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid metric %q, use one of: %s", cfg.Metric, strings.Join(SweepMetrics(), ", "))
+	}
+
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+	if cfg.TopN <= 0 {
+		cfg.TopN = defaultSweepTopN
+	}
+
+	if wf := cfg.WalkForward; wf != nil {
+		if wf.InSampleDays <= 0 || wf.OutSampleDays <= 0 {
+			return fmt.Errorf("walk_forward in_sample_days and out_sample_days must be > 0")
+		}
+	}
+	return nil
+}
+
+func gridKeys(grid map[string][]float64) models.ScreenerParams {
+	keys := models.ScreenerParams{}
+	for k := range grid {
+		keys[k] = 0
+	}
+	return keys
+}
+
+// sweep jalankan semua kombinasi paralel di atas data yang sama, hasil diurut score tertinggi
+func sweep(cfg models.SweepConfig, data *backtestData, combos []models.ScreenerParams) []models.SweepResult {
+	results := make([]models.SweepResult, len(combos))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				run := cfg.Base
+				run.Params = withBaseParams(cfg.Base.Params, combos[i])
+
+				result := models.SweepResult{Params: run.Params}
+				report, err := simulate(run, data)
+				if err != nil {
+					result.Error = err.Error()
+				} else {
+					result.Score = metricScore(report, cfg.Metric)
+					result.Summary = report.Summary
+					result.Metrics = &report.Metrics
+				}
+				results[i] = result
+			}
+		}()
+	}
+
+	for i := range combos {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	rankResults(results)
+	return results
+}
+
+func withBaseParams(base, override models.ScreenerParams) models.ScreenerParams {
+	merged := models.ScreenerParams{}
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+func appendSaveError(report *models.SweepReport, msg string) {
+	if msg != "" {
+		report.SaveErrors = append(report.SaveErrors, msg)
+	}
+}
+
+// rankResults: score tertinggi di atas, yang null/error di paling bawah
+func rankResults(results []models.SweepResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Score, results[j].Score
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return *a > *b
+	})
+}
+
+// RunParameterSweep jalankan grid search, plus walk-forward kalau diminta
+func RunParameterSweep(cfg models.SweepConfig) (*models.SweepReport, error) {
+	if err := NormalizeSweepConfig(&cfg); err != nil {
+		return nil, err
+	}
+
+	data, err := loadBacktestData(cfg.Base)
+	if err != nil {
+		return nil, err
+	}
+
+	combos := expandGrid(cfg.Grid)
+	report := &models.SweepReport{
+		Screener:     cfg.Base.Screener,
+		Metric:       cfg.Metric,
+		Combinations: len(combos),
+		Workers:      cfg.Workers,
+	}
+
+	results := sweep(cfg, data, combos)
+	if len(results) > cfg.TopN {
+		results = results[:cfg.TopN]
+	}
+	report.Results = results
+
+	// Kombinasi terbaik disimpan sebagai run lengkap (trade + equity), sinyalnya sudah di cache
+	if len(results) > 0 && results[0].Score != nil {
+		start := time.Now()
+		best := cfg.Base
+		best.Params = results[0].Params
+		if bestReport, err := simulate(best, data); err == nil {
+			var saveError string
+			results[0].RunID, saveError = saveRun(bestReport, time.Since(start))
+			appendSaveError(report, saveError)
+		}
+	}
+
+	if cfg.WalkForward != nil {
+		windows, summary := walkForward(cfg, data, combos, report)
+		report.WalkForward = windows
+		report.WFSummary = summary
+	}
+
+	return report, nil
+}
+
+// walkForward: optimasi di in-sample, lalu param terbaik diuji di out-of-sample berikutnya.
+// Window digeser sebesar out-of-sample, jadi semua periode OOS nggak overlap.
+// Run out-of-sample tiap window disimpan, error simpan dicatat di report.
+func walkForward(cfg models.SweepConfig, data *backtestData, combos []models.ScreenerParams, report *models.SweepReport) ([]models.WalkForwardWindow, *models.WalkForwardSummary) {
+	wf := cfg.WalkForward
+	windows := []models.WalkForwardWindow{}
+	summary := &models.WalkForwardSummary{}
+
+	var sumIS, sumOOS float64
+	var countIS, countOOS, oosWins int
+	distinct := map[string]bool{}
+
+	for start := 0; start+wf.InSampleDays < len(data.days); start += wf.OutSampleDays {
+		isEnd := start + wf.InSampleDays
+		oosEnd := min(isEnd+wf.OutSampleDays, len(data.days))
+
+		isData := data.window(start, isEnd)
+		oosData := data.window(isEnd, oosEnd)
+
+		isCfg := cfg
+		isCfg.Base.StartDate = isData.days[0].Format("2006-01-02")
+		isCfg.Base.EndDate = isData.days[len(isData.days)-1].Format("2006-01-02")
+		ranked := sweep(isCfg, isData, combos)
+
+		window := models.WalkForwardWindow{
+			InSampleStart:  isCfg.Base.StartDate,
+			InSampleEnd:    isCfg.Base.EndDate,
+			OutSampleStart: oosData.days[0].Format("2006-01-02"),
+			OutSampleEnd:   oosData.days[len(oosData.days)-1].Format("2006-01-02"),
+		}
+
+		if len(ranked) == 0 || ranked[0].Score == nil {
+			windows = append(windows, window)
+			continue
+		}
+
+		best := ranked[0]
+		window.BestParams = best.Params
+		window.InSampleScore = best.Score
+		distinct[fmt.Sprint(best.Params)] = true
+
+		oosCfg := cfg.Base
+		oosCfg.StartDate = window.OutSampleStart
+		oosCfg.EndDate = window.OutSampleEnd
+		oosCfg.Params = best.Params
+
+		oosStart := time.Now()
+		oosReport, err := simulate(oosCfg, oosData)
+		if err == nil {
+			var saveError string
+			window.OutSampleRunID, saveError = saveRun(oosReport, time.Since(oosStart))
+			appendSaveError(report, saveError)
+
+			window.OutSampleScore = metricScore(oosReport, cfg.Metric)
+			window.OutSampleSummary = oosReport.Summary
+
+			summary.OutSampleTrades += oosReport.Summary.TotalTrades
+			summary.OutSampleNetPnL += oosReport.Summary.TotalNetPnL
+			oosWins += oosReport.Summary.WinCount
+		}
+
+		sumIS += *best.Score
+		countIS++
+		if window.OutSampleScore != nil {
+			sumOOS += *window.OutSampleScore
+			countOOS++
+		}
+
+		windows = append(windows, window)
+	}
+
+	summary.Windows = len(windows)
+	summary.DistinctBestParams = len(distinct)
+	if countIS > 0 {
+		avg := sumIS / float64(countIS)
+		summary.AvgInSampleScore = &avg
+	}
+	if countOOS > 0 {
+		avg := sumOOS / float64(countOOS)
+		summary.AvgOutSampleScore = &avg
+	}
+	if summary.AvgInSampleScore != nil && summary.AvgOutSampleScore != nil && *summary.AvgInSampleScore != 0 {
+		eff := *summary.AvgOutSampleScore / *summary.AvgInSampleScore
+		summary.Efficiency = &eff
+	}
+	if summary.OutSampleTrades > 0 {
+		summary.OutSampleWinRate = (float64(oosWins) / float64(summary.OutSampleTrades)) * 100
+	}
+
+	return windows, summary
+}
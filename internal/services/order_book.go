@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"sort"
+)
+
+const (
+	OrderBookARAQueue   = "ARA QUEUE (Offer Habis)"
+	OrderBookARBQueue   = "ARB QUEUE (Bid Kosong)"
+	OrderBookOfferSwept = "OFFER SWEPT"
+	OrderBookBidEmpty   = "BID EMPTY"
+	OrderBookBidHeavy   = "BID HEAVY"
+	OrderBookOfferHeavy = "OFFER HEAVY"
+	OrderBookBalanced   = "BALANCED"
+
+	// |imbalance| di atas ini dianggap berat sebelah
+	orderBookHeavyPct = 50.0
+)
+
+// SpreadTicks = jumlah fraksi antara bid dan offer, ikut pindah fraksi kalau lewat batas harga
+func SpreadTicks(bid, offer float64) int {
+	ticks := 0
+	for p := bid; p < offer-1e-9 && ticks < 1000; p += TickSize(p) {
+		ticks++
+	}
+	return ticks
+}
+
+func OrderBookPressureOf(r models.OrderBookRow) models.OrderBookPressure {
+	p := models.OrderBookPressure{
+		StockCode:   r.StockCode,
+		StockName:   r.StockName,
+		TradeDate:   r.TradeDate,
+		Close:       r.Close,
+		Bid:         r.Bid,
+		BidVolume:   r.BidVolume,
+		Offer:       r.Offer,
+		OfferVolume: r.OfferVolume,
+	}
+
+	if total := r.BidVolume + r.OfferVolume; total > 0 {
+		p.ImbalancePct = (r.BidVolume - r.OfferVolume) / total * 100
+	}
+	if r.OfferVolume > 0 {
+		ratio := r.BidVolume / r.OfferVolume
+		p.BidOfferRatio = &ratio
+	}
+	if r.Bid > 0 && r.Offer > 0 && r.BidVolume > 0 && r.OfferVolume > 0 {
+		ticks := SpreadTicks(r.Bid, r.Offer)
+		pct := (r.Offer - r.Bid) / r.Bid * 100
+		p.SpreadTicks, p.SpreadPct = &ticks, &pct
+	}
+
+	p.OfferSwept = r.OfferVolume == 0 && r.BidVolume > 0
+	p.BidEmpty = r.BidVolume == 0 && r.OfferVolume > 0
+
+	lower, upper := AutoRejectionLimits(r.Previous, r.ListingBoard)
+	p.AtARA = r.Previous > 0 && r.Close >= upper
+	p.AtARB = r.Previous > 0 && r.Close <= lower
+
+	switch {
+	case p.AtARA && p.OfferSwept:
+		p.Status = OrderBookARAQueue
+	case p.AtARB && p.BidEmpty:
+		p.Status = OrderBookARBQueue
+	case p.OfferSwept:
+		p.Status = OrderBookOfferSwept
+	case p.BidEmpty:
+		p.Status = OrderBookBidEmpty
+	case p.ImbalancePct >= orderBookHeavyPct:
+		p.Status = OrderBookBidHeavy
+	case p.ImbalancePct <= -orderBookHeavyPct:
+		p.Status = OrderBookOfferHeavy
+	default:
+		p.Status = OrderBookBalanced
+	}
+
+	return p
+}
+
+// orderBookAsOf = tekanan order book per saham di hari bursa terakhir <= as_of
+func orderBookAsOf(asOf string, codes []string) (map[string]models.OrderBookPressure, error) {
+	rows, err := repositories.GetOrderBookAsOf(asOf, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]models.OrderBookPressure, len(rows))
+	for _, r := range rows {
+		result[r.StockCode] = OrderBookPressureOf(r)
+	}
+	return result, nil
+}
+
+// ValidateOrderBookFlag: flag kosong = tanpa filter
+func ValidateOrderBookFlag(flag string) error {
+	switch flag {
+	case "", "offer_swept", "bid_empty", "ara", "arb":
+		return nil
+	}
+	return fmt.Errorf("invalid flag %q, use offer_swept, bid_empty, ara or arb", flag)
+}
+
+// ScanOrderBook = ranking imbalance se-market; flag (offer_swept/bid_empty/ara/arb) buat nyaring
+func ScanOrderBook(asOf, flag string, limit int) ([]models.OrderBookPressure, error) {
+	if err := ValidateOrderBookFlag(flag); err != nil {
+		return nil, err
+	}
+
+	book, err := orderBookAsOf(asOf, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := []models.OrderBookPressure{}
+	for _, p := range book {
+		keep := true
+		switch flag {
+		case "offer_swept":
+			keep = p.OfferSwept
+		case "bid_empty":
+			keep = p.BidEmpty
+		case "ara":
+			keep = p.AtARA
+		case "arb":
+			keep = p.AtARB
+		}
+		if keep {
+			data = append(data, p)
+		}
+	}
+
+	sort.Slice(data, func(a, b int) bool {
+		if data[a].ImbalancePct != data[b].ImbalancePct {
+			return data[a].ImbalancePct > data[b].ImbalancePct
+		}
+		return data[a].BidVolume > data[b].BidVolume
+	})
+	if len(data) > limit {
+		data = data[:limit]
+	}
+	return data, nil
+}
+
+// GetStockOrderBook = histori tekanan order book satu saham, terbaru dulu
+func GetStockOrderBook(stockCode, asOf string, days int) ([]models.OrderBookPressure, error) {
+	rows, err := repositories.GetStockOrderBook(stockCode, asOf, days)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.OrderBookPressure, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, OrderBookPressureOf(r))
+	}
+	return result, nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"indonesia-stocks-api/internal/models"
+	"testing"
+)
+
+func TestSpreadTicks(t *testing.T) {
+	tests := []struct {
+		name       string
+		bid, offer float64
+		want       int
+	}{
+		{"locked book", 100, 100, 0},
+		{"one tick", 150, 151, 1},
+		{"inside the Rp1 band", 100, 103, 3},
+		{"crosses the Rp200 band", 198, 204, 4},
+		{"crosses the Rp500 band", 490, 505, 6},
+		{"crosses the Rp2000 band", 1990, 2010, 3},
+		{"Rp25 band", 5000, 5050, 2},
+		{"crossed book", 204, 198, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SpreadTicks(tt.bid, tt.offer); got != tt.want {
+				t.Errorf("SpreadTicks(%v, %v) = %d, want %d", tt.bid, tt.offer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderBookPressureOf(t *testing.T) {
+	tests := []struct {
+		name      string
+		row       models.OrderBookRow
+		want      string
+		wantAtARA bool
+		wantAtARB bool
+		wantTicks int // 0 = spread null (salah satu sisi kosong)
+	}{
+		{
+			"ARA queue: closed at the upper limit with the offer swept",
+			models.OrderBookRow{Previous: 100, Close: 135, Bid: 135, BidVolume: 5000},
+			OrderBookARAQueue, true, false, 0,
+		},
+		{
+			"ARA queue on the special board 10% limit",
+			models.OrderBookRow{Previous: 100, Close: 110, Bid: 110, BidVolume: 5000, ListingBoard: "Papan Pemantauan Khusus"},
+			OrderBookARAQueue, true, false, 0,
+		},
+		{
+			"ARB queue: closed at the lower limit with no bid",
+			models.OrderBookRow{Previous: 100, Close: 65, Offer: 65, OfferVolume: 8000},
+			OrderBookARBQueue, false, true, 0,
+		},
+		{
+			"at ARA with offers left is not a queue",
+			models.OrderBookRow{Previous: 100, Close: 135, Bid: 134, BidVolume: 1000, Offer: 135, OfferVolume: 1000},
+			OrderBookBalanced, true, false, 1,
+		},
+		{
+			"offer swept below ARA",
+			models.OrderBookRow{Previous: 100, Close: 120, Bid: 120, BidVolume: 5000},
+			OrderBookOfferSwept, false, false, 0,
+		},
+		{
+			"bid empty above ARB",
+			models.OrderBookRow{Previous: 100, Close: 80, Offer: 80, OfferVolume: 5000},
+			OrderBookBidEmpty, false, false, 0,
+		},
+		{
+			"bid heavy at the threshold",
+			models.OrderBookRow{Previous: 200, Close: 198, Bid: 198, BidVolume: 3000, Offer: 204, OfferVolume: 1000},
+			OrderBookBidHeavy, false, false, 4,
+		},
+		{
+			"offer heavy at the threshold",
+			models.OrderBookRow{Previous: 200, Close: 198, Bid: 198, BidVolume: 1000, Offer: 204, OfferVolume: 3000},
+			OrderBookOfferHeavy, false, false, 4,
+		},
+		{
+			"no previous price never flags ARA/ARB",
+			models.OrderBookRow{Close: 135, Bid: 135, BidVolume: 5000},
+			OrderBookOfferSwept, false, false, 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := OrderBookPressureOf(tt.row)
+			if p.Status != tt.want {
+				t.Errorf("status = %q, want %q", p.Status, tt.want)
+			}
+			if p.AtARA != tt.wantAtARA || p.AtARB != tt.wantAtARB {
+				t.Errorf("at ARA/ARB = %v/%v, want %v/%v", p.AtARA, p.AtARB, tt.wantAtARA, tt.wantAtARB)
+			}
+			ticks := 0
+			if p.SpreadTicks != nil {
+				ticks = *p.SpreadTicks
+			}
+			if ticks != tt.wantTicks {
+				t.Errorf("spread ticks = %d, want %d", ticks, tt.wantTicks)
+			}
+		})
+	}
+}
+
+func TestOrderBookPressureOfRatios(t *testing.T) {
+	p := OrderBookPressureOf(models.OrderBookRow{Bid: 198, BidVolume: 3000, Offer: 204, OfferVolume: 1000})
+	if !almostEqual(p.ImbalancePct, 50) {
+		t.Errorf("imbalance = %v, want 50", p.ImbalancePct)
+	}
+	if !almostEqualPtr(p.BidOfferRatio, float64Ptr(3)) {
+		t.Errorf("bid/offer ratio = %s, want 3", fmtPtr(p.BidOfferRatio))
+	}
+	if !almostEqualPtr(p.SpreadPct, float64Ptr(6.0/198*100)) {
+		t.Errorf("spread pct = %s, want %v", fmtPtr(p.SpreadPct), 6.0/198*100)
+	}
+
+	// offer habis: rasio & spread null
+	p = OrderBookPressureOf(models.OrderBookRow{Bid: 135, BidVolume: 5000})
+	if p.BidOfferRatio != nil || p.SpreadTicks != nil || p.SpreadPct != nil || !almostEqual(p.ImbalancePct, 100) {
+		t.Errorf("swept book ratio/ticks/pct/imbalance = %s/%v/%s/%v, want nil/nil/nil/100", fmtPtr(p.BidOfferRatio), p.SpreadTicks, fmtPtr(p.SpreadPct), p.ImbalancePct)
+	}
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultPairLookback    = 250
+	DefaultPairMinCorr     = 0.3
+	DefaultPairMaxHalfLife = 60.0
+	DefaultPairScanLimit   = 20
+
+	DefaultPairSignificance = 0.05
+
+	MinPairLookback = 60
+	MaxPairLookback = 1000
+
+	pairEntryZ = 2.0
+	pairExitZ  = 0.5
+
+	PairLongAShortB = "LONG_A_SHORT_B"
+	PairShortALongB = "SHORT_A_LONG_B"
+	PairExit        = "EXIT"
+	PairHold        = "HOLD"
+)
+
+// Critical value Engle-Granger 2 variabel (MacKinnon), tanpa tren, per tingkat signifikansi
+var engleGrangerCritical = map[float64]float64{
+	0.01: -3.90,
+	0.05: -3.34,
+	0.10: -3.04,
+}
+
+// ErrNotEnoughOverlap = dua saham ada harganya, tapi hari yang sama-sama ada terlalu sedikit
+var ErrNotEnoughOverlap = errors.New("not enough overlapping data")
+
+// PairCriticalValue = critical value Engle-Granger buat signifikansi 0.01, 0.05 atau 0.10
+func PairCriticalValue(significance float64) (float64, error) {
+	critical, ok := engleGrangerCritical[significance]
+	if !ok {
+		return 0, fmt.Errorf("significance must be 0.01, 0.05 or 0.10")
+	}
+	return critical, nil
+}
+
+// priceWindow = close tiap saham di `lookback` hari bursa terakhir <= as_of, NaN kalau belum ada bar
+type priceWindow struct {
+	days   []time.Time
+	closes map[string][]float64
+}
+
+func loadPriceWindow(codes []string, asOf string, lookback int) (*priceWindow, error) {
+	end, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return nil, err
+	}
+	start := end.AddDate(0, 0, -(lookback*7/5 + 30)).Format("2006-01-02")
+
+	days, err := repositories.GetTradingDates(start, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if len(days) > lookback {
+		days = days[len(days)-lookback:]
+	}
+	if len(days) == 0 {
+		return nil, fmt.Errorf("%w up to %s", ErrNoTradingData, asOf)
+	}
+
+	bars, err := repositories.GetStockBars(codes, days[0].Format("2006-01-02"), days[len(days)-1].Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	dayIdx := make(map[string]int, len(days))
+	for i, d := range days {
+		dayIdx[d.Format("2006-01-02")] = i
+	}
+
+	pw := &priceWindow{days: days, closes: map[string][]float64{}}
+	for _, b := range bars {
+		c, ok := pw.closes[b.StockCode]
+		if !ok {
+			c = make([]float64, len(days))
+			for i := range c {
+				c[i] = math.NaN()
+			}
+			pw.closes[b.StockCode] = c
+		}
+		if i, ok := dayIdx[b.TradeDate.Format("2006-01-02")]; ok && b.Close > 0 {
+			c[i] = b.Close
+		}
+	}
+	return pw, nil
+}
+
+// linearFit = OLS y = a + b*x
+func linearFit(x, y []float64) (float64, float64) {
+	mx, _ := meanStd(x)
+	my, _ := meanStd(y)
+	var sxy, sxx float64
+	for i := range x {
+		sxy += (x[i] - mx) * (y[i] - my)
+		sxx += (x[i] - mx) * (x[i] - mx)
+	}
+	if sxx == 0 {
+		return my, 0
+	}
+	b := sxy / sxx
+	return my - b*mx, b
+}
+
+// dickeyFuller = t-stat gamma dari Δe_t = gamma * e_(t-1), residual EG rata-ratanya 0 jadi tanpa konstanta
+func dickeyFuller(e []float64) float64 {
+	var sxy, sxx float64
+	for t := 1; t < len(e); t++ {
+		sxy += (e[t] - e[t-1]) * e[t-1]
+		sxx += e[t-1] * e[t-1]
+	}
+	if sxx == 0 || len(e) < 3 {
+		return 0
+	}
+	gamma := sxy / sxx
+
+	ss := 0.0
+	for t := 1; t < len(e); t++ {
+		r := (e[t] - e[t-1]) - gamma*e[t-1]
+		ss += r * r
+	}
+	se := math.Sqrt(ss / float64(len(e)-2) / sxx)
+	if se == 0 {
+		return 0
+	}
+	return gamma / se
+}
+
+// halfLife dari Δe_t = c + lambda * e_(t-1), half life = -ln2 / lambda
+func halfLife(e []float64) *float64 {
+	if len(e) < 3 {
+		return nil
+	}
+	lagged := e[:len(e)-1]
+	diff := make([]float64, len(e)-1)
+	for t := 1; t < len(e); t++ {
+		diff[t-1] = e[t] - e[t-1]
+	}
+	_, lambda := linearFit(lagged, diff)
+	if lambda >= 0 {
+		return nil
+	}
+	hl := -math.Ln2 / lambda
+	return &hl
+}
+
+func pairSignal(z float64) string {
+	switch {
+	case z >= pairEntryZ:
+		return PairShortALongB
+	case z <= -pairEntryZ:
+		return PairLongAShortB
+	case math.Abs(z) <= pairExitZ:
+		return PairExit
+	}
+	return PairHold
+}
+
+// analyzePair uji Engle-Granger (regresi ln A ke ln B) di hari yang dua saham ada harganya.
+// idx = index hari yang dipakai.
+func analyzePair(a, b string, ca, cb []float64, significance float64) (models.PairStats, []float64, []int) {
+	critical := engleGrangerCritical[significance]
+	stats := models.PairStats{StockA: a, StockB: b, Significance: significance, CriticalValue: critical}
+
+	var x, y []float64
+	var idx []int
+	for i := range ca {
+		if !math.IsNaN(ca[i]) && !math.IsNaN(cb[i]) {
+			y = append(y, math.Log(ca[i]))
+			x = append(x, math.Log(cb[i]))
+			idx = append(idx, i)
+		}
+	}
+	stats.Observations = len(x)
+	if len(x) < MinPairLookback/2 {
+		return stats, nil, nil
+	}
+
+	ra := make([]float64, 0, len(x)-1)
+	rb := make([]float64, 0, len(x)-1)
+	for i := 1; i < len(x); i++ {
+		ra = append(ra, y[i]-y[i-1])
+		rb = append(rb, x[i]-x[i-1])
+	}
+	stats.Correlation, _ = pearson(ra, rb)
+
+	stats.Intercept, stats.HedgeRatio = linearFit(x, y)
+	spread := make([]float64, len(x))
+	for i := range x {
+		spread[i] = y[i] - stats.Intercept - stats.HedgeRatio*x[i]
+	}
+
+	stats.ADFStat = dickeyFuller(spread)
+	stats.Cointegrated = stats.ADFStat < critical
+	stats.HalfLifeDays = halfLife(spread)
+	stats.SpreadMean, stats.SpreadStd = meanStd(spread)
+	if stats.SpreadStd > 0 {
+		stats.ZScore = (spread[len(spread)-1] - stats.SpreadMean) / stats.SpreadStd
+	}
+	stats.Signal = pairSignal(stats.ZScore)
+	return stats, spread, idx
+}
+
+// bestDirection: hasil Engle-Granger tergantung saham mana yang jadi variabel dependen,
+// jadi uji dua arah dan ambil ADF yang paling negatif
+func bestDirection(a, b string, ca, cb []float64, significance float64) (models.PairStats, []float64, []int) {
+	stats, spread, idx := analyzePair(a, b, ca, cb, significance)
+	if spread == nil {
+		return stats, nil, nil
+	}
+	rev, revSpread, revIdx := analyzePair(b, a, cb, ca, significance)
+	if revSpread != nil && rev.ADFStat < stats.ADFStat {
+		return rev, revSpread, revIdx
+	}
+	return stats, spread, idx
+}
+
+// GetPairReport = statistik pair + time series spread & z-score. Arah regresinya sesuai permintaan (A ke B).
+func GetPairReport(a, b, asOf string, lookback int, significance float64) (*models.PairReport, error) {
+	if a == b {
+		return nil, fmt.Errorf("stock a and b must be different")
+	}
+
+	pw, err := loadPriceWindow([]string{a, b}, asOf, lookback)
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range []string{a, b} {
+		if _, ok := pw.closes[code]; !ok {
+			return nil, fmt.Errorf("%w for %s in the last %d trading days up to %s", ErrNoTradingData, code, lookback, asOf)
+		}
+	}
+
+	stats, spread, idx := analyzePair(a, b, pw.closes[a], pw.closes[b], significance)
+	if spread == nil {
+		return nil, fmt.Errorf("%w, got %d days, need %d", ErrNotEnoughOverlap, stats.Observations, MinPairLookback/2)
+	}
+
+	report := &models.PairReport{
+		PairStats: stats,
+		StartDate: pw.days[idx[0]].Format("2006-01-02"),
+		EndDate:   pw.days[idx[len(idx)-1]].Format("2006-01-02"),
+		Spread:    make([]models.PairSpreadPoint, 0, len(spread)),
+	}
+	for i, s := range spread {
+		point := models.PairSpreadPoint{Date: pw.days[idx[i]].Format("2006-01-02"), Spread: s}
+		if stats.SpreadStd > 0 {
+			point.ZScore = (s - stats.SpreadMean) / stats.SpreadStd
+		}
+		report.Spread = append(report.Spread, point)
+	}
+	return report, nil
+}
+
+// ScanPairs cari pair cointegrated di satu sektor/universe, urut dari z-score paling ekstrem.
+// Balikin juga jumlah pair yang diuji: dengan banyak pair, kira-kira tested * significance
+// lolos cuma karena kebetulan.
+func ScanPairs(asOf string, req models.CorrelationRequest, lookback int, minCorr, maxHalfLife, significance float64, limit int) ([]models.PairStats, int, error) {
+	_, codes, err := resolveUniverse(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	if codes == nil {
+		return nil, 0, fmt.Errorf("universe, sector or codes is required")
+	}
+	if len(codes) > maxCorrelationUniverse {
+		return nil, 0, fmt.Errorf("universe has %d stocks, max %d", len(codes), maxCorrelationUniverse)
+	}
+
+	pw, err := loadPriceWindow(codes, asOf, lookback)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	available := make([]string, 0, len(pw.closes))
+	for code := range pw.closes {
+		available = append(available, code)
+	}
+	sort.Strings(available)
+
+	tested := 0
+	pairs := []models.PairStats{}
+	for i := 0; i < len(available); i++ {
+		for j := i + 1; j < len(available); j++ {
+			a, b := available[i], available[j]
+			stats, spread, _ := bestDirection(a, b, pw.closes[a], pw.closes[b], significance)
+			if spread == nil || stats.Correlation < minCorr {
+				continue
+			}
+			tested++
+			if !stats.Cointegrated || stats.HalfLifeDays == nil || *stats.HalfLifeDays > maxHalfLife {
+				continue
+			}
+			pairs = append(pairs, stats)
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return math.Abs(pairs[i].ZScore) > math.Abs(pairs[j].ZScore)
+	})
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+	return pairs, tested, nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDickeyFuller(t *testing.T) {
+	tests := []struct {
+		name   string
+		spread []float64
+		want   float64
+	}{
+		{"too short", []float64{1, 2}, 0},
+		{"flat spread", []float64{1, 1, 1, 1}, 0},
+		{"mean reverting", []float64{1, 0, 1, 0}, -2},
+		{"trending", []float64{1, 2, 3, 4}, 2 * math.Sqrt(3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dickeyFuller(tt.spread); !almostEqual(got, tt.want) {
+				t.Errorf("dickeyFuller(%v) = %v, want %v", tt.spread, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHalfLife(t *testing.T) {
+	tests := []struct {
+		name   string
+		spread []float64
+		want   *float64
+	}{
+		{"too short", []float64{1, 2}, nil},
+		{"halves every day", []float64{16, 8, 4, 2, 1}, float64Ptr(math.Ln2 / 0.5)},
+		{"constant drift", []float64{1, 2, 3, 4}, nil},
+		{"explosive", []float64{1, 2, 4, 8}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := halfLife(tt.spread); !almostEqualPtr(got, tt.want) {
+				t.Errorf("halfLife(%v) = %s, want %s", tt.spread, fmtPtr(got), fmtPtr(tt.want))
+			}
+		})
+	}
+}
+
+func TestPairCriticalValue(t *testing.T) {
+	tests := []struct {
+		significance float64
+		want         float64
+		wantErr      bool
+	}{
+		{0.01, -3.90, false},
+		{0.05, -3.34, false},
+		{0.10, -3.04, false},
+		{0.2, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := PairCriticalValue(tt.significance)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("PairCriticalValue(%v) = (%v, %v), want %v, error %v", tt.significance, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
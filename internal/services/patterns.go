@@ -0,0 +1,333 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"math"
+)
+
+const (
+	PatternHammer             = "hammer"
+	PatternShootingStar       = "shooting_star"
+	PatternBullishEngulfing   = "bullish_engulfing"
+	PatternBearishEngulfing   = "bearish_engulfing"
+	PatternDoji               = "doji"
+	PatternMorningStar        = "morning_star"
+	PatternEveningStar        = "evening_star"
+	PatternInsideBar          = "inside_bar"
+	PatternBullFlag           = "bull_flag"
+	PatternDoubleBottom       = "double_bottom"
+	PatternTightConsolidation = "tight_consolidation"
+
+	PatternKindCandle = "candle"
+	PatternKindChart  = "chart"
+
+	BiasBullish = "bullish"
+	BiasBearish = "bearish"
+	BiasNeutral = "neutral"
+
+	// Histori (hari kalender) yang di-load buat deteksi chart pattern
+	patternLookbackDays = 150
+)
+
+// PatternNames = semua pola yang bisa dipakai di filter ?pattern=
+func PatternNames() []string {
+	return []string{
+		PatternHammer, PatternShootingStar, PatternBullishEngulfing, PatternBearishEngulfing,
+		PatternDoji, PatternMorningStar, PatternEveningStar, PatternInsideBar,
+		PatternBullFlag, PatternDoubleBottom, PatternTightConsolidation,
+	}
+}
+
+type candle struct {
+	body, rng, upper, lower float64
+	bullish, bearish        bool
+}
+
+func candleOf(b models.DailyBar) candle {
+	open := openPrice(b)
+	return candle{
+		body:    math.Abs(b.Close - open),
+		rng:     b.High - b.Low,
+		upper:   b.High - math.Max(open, b.Close),
+		lower:   math.Min(open, b.Close) - b.Low,
+		bullish: b.Close > open,
+		bearish: b.Close < open,
+	}
+}
+
+// priorTrend: -1 turun, 1 naik, 0 datar, dilihat dari close i-1 vs close 5 bar sebelumnya
+func priorTrend(bars []models.DailyBar, i int) int {
+	if i < 6 {
+		return 0
+	}
+	ref := bars[i-6].Close
+	if ref <= 0 {
+		return 0
+	}
+	chg := (bars[i-1].Close - ref) / ref
+	switch {
+	case chg <= -0.03:
+		return -1
+	case chg >= 0.03:
+		return 1
+	}
+	return 0
+}
+
+// DetectCandlePatterns cek pola candlestick di bar i (bars urut tanggal naik)
+func DetectCandlePatterns(bars []models.DailyBar, i int) []models.PatternHit {
+	if i < 0 || i >= len(bars) || !tradable(bars[i]) {
+		return nil
+	}
+
+	hits := []models.PatternHit{}
+	add := func(name, bias string) {
+		hits = append(hits, models.PatternHit{Name: name, Kind: PatternKindCandle, Bias: bias, Date: bars[i].TradeDate, Confirmed: true})
+	}
+
+	c := candleOf(bars[i])
+	if c.rng <= 0 {
+		return hits
+	}
+	trend := priorTrend(bars, i)
+
+	if c.body <= 0.1*c.rng {
+		add(PatternDoji, BiasNeutral)
+	}
+	// Ekor bawah >= 2x body, ekor atas pendek, muncul setelah turun
+	if c.body > 0 && c.lower >= 2*c.body && c.upper <= c.body && trend < 0 {
+		add(PatternHammer, BiasBullish)
+	}
+	if c.body > 0 && c.upper >= 2*c.body && c.lower <= c.body && trend > 0 {
+		add(PatternShootingStar, BiasBearish)
+	}
+
+	if i >= 1 && tradable(bars[i-1]) {
+		prev := bars[i-1]
+		p := candleOf(prev)
+		open, prevOpen := openPrice(bars[i]), openPrice(prev)
+
+		if p.bearish && c.bullish && open <= prev.Close && bars[i].Close >= prevOpen && c.body > p.body {
+			add(PatternBullishEngulfing, BiasBullish)
+		}
+		if p.bullish && c.bearish && open >= prev.Close && bars[i].Close <= prevOpen && c.body > p.body {
+			add(PatternBearishEngulfing, BiasBearish)
+		}
+		if bars[i].High <= prev.High && bars[i].Low >= prev.Low && (bars[i].High < prev.High || bars[i].Low > prev.Low) {
+			add(PatternInsideBar, BiasNeutral)
+		}
+	}
+
+	// Star: candle panjang, candle kecil, lalu candle balik arah yang tutup lewat tengah body pertama
+	if i >= 2 && tradable(bars[i-1]) && tradable(bars[i-2]) {
+		first, mid := bars[i-2], bars[i-1]
+		f, m := candleOf(first), candleOf(mid)
+		firstOpen := openPrice(first)
+		longFirst := f.rng > 0 && f.body >= 0.6*f.rng
+		smallMid := m.body <= 0.3*f.body
+		midpoint := (firstOpen + first.Close) / 2
+
+		if longFirst && smallMid && f.bearish && c.bullish &&
+			math.Max(openPrice(mid), mid.Close) <= first.Close && bars[i].Close > midpoint {
+			add(PatternMorningStar, BiasBullish)
+		}
+		if longFirst && smallMid && f.bullish && c.bearish &&
+			math.Min(openPrice(mid), mid.Close) >= first.Close && bars[i].Close < midpoint {
+			add(PatternEveningStar, BiasBearish)
+		}
+	}
+
+	return hits
+}
+
+// DetectChartPatterns cek pola multi-hari yang masih "aktif" di bar i
+func DetectChartPatterns(bars []models.DailyBar, i int) []models.PatternHit {
+	if i < 0 || i >= len(bars) {
+		return nil
+	}
+
+	hits := []models.PatternHit{}
+	for _, detect := range []func([]models.DailyBar, int) (models.PatternHit, bool){
+		detectBullFlag, detectDoubleBottom, detectTightConsolidation,
+	} {
+		if hit, ok := detect(bars, i); ok {
+			hit.Kind = PatternKindChart
+			hit.Date = bars[i].TradeDate
+			hits = append(hits, hit)
+		}
+	}
+	return hits
+}
+
+func highLow(bars []models.DailyBar, from, to int) (float64, float64) {
+	hi, lo := 0.0, math.MaxFloat64
+	for j := from; j <= to; j++ {
+		hi = math.Max(hi, bars[j].High)
+		if bars[j].Low > 0 {
+			lo = math.Min(lo, bars[j].Low)
+		}
+	}
+	return hi, lo
+}
+
+func avgVolume(bars []models.DailyBar, from, to int) float64 {
+	if to < from {
+		return 0
+	}
+	sum := 0.0
+	for j := from; j <= to; j++ {
+		sum += bars[j].Volume
+	}
+	return sum / float64(to-from+1)
+}
+
+// detectBullFlag: tiang naik >= 15% dalam <= 10 bar, lalu bendera 3-12 bar yang
+// koreksinya <= 50% tiang dengan volume mengecil. Confirmed kalau close tembus high tiang.
+func detectBullFlag(bars []models.DailyBar, i int) (models.PatternHit, bool) {
+	const poleBars, minGain = 10, 0.15
+
+	for flagLen := 3; flagLen <= 12; flagLen++ {
+		top := i - flagLen
+		if top-poleBars < 0 {
+			break
+		}
+
+		poleHigh := bars[top].High
+		_, poleLow := highLow(bars, top-poleBars, top)
+		if poleLow <= 0 || (poleHigh-poleLow)/poleLow < minGain {
+			continue
+		}
+		// top harus puncak tiang
+		if hi, _ := highLow(bars, top-poleBars, top); hi > poleHigh {
+			continue
+		}
+
+		flagHigh, flagLow := highLow(bars, top+1, i-1)
+		if flagHigh > poleHigh*1.02 || flagLow < poleHigh-0.5*(poleHigh-poleLow) {
+			continue
+		}
+		if avgVolume(bars, top+1, i) >= avgVolume(bars, top-poleBars, top) {
+			continue
+		}
+
+		return models.PatternHit{
+			Name:      PatternBullFlag,
+			Bias:      BiasBullish,
+			Confirmed: bars[i].Close > poleHigh,
+			Detail:    fmt.Sprintf("pole +%.1f%%, flag %d bars, breakout level %.0f", (poleHigh-poleLow)/poleLow*100, flagLen, poleHigh),
+		}, true
+	}
+	return models.PatternHit{}, false
+}
+
+// swingLows = index local minimum (low terendah di +-w bar)
+func swingLows(bars []models.DailyBar, from, to, w int) []int {
+	idx := []int{}
+	for j := from + w; j <= to-w; j++ {
+		low := bars[j].Low
+		if low <= 0 {
+			continue
+		}
+		isMin := true
+		for k := j - w; k <= j+w; k++ {
+			if k != j && bars[k].Low > 0 && bars[k].Low < low {
+				isMin = false
+				break
+			}
+		}
+		if isMin {
+			idx = append(idx, j)
+		}
+	}
+	return idx
+}
+
+// detectDoubleBottom: dua swing low (selisih <= 3%, jarak >= 10 bar) dalam 60 bar terakhir
+// dengan puncak di antaranya >= 5% di atas low. Bottom kedua maksimal 20 bar lalu.
+// Confirmed kalau close sudah di atas neckline (puncak di antara dua bottom).
+func detectDoubleBottom(bars []models.DailyBar, i int) (models.PatternHit, bool) {
+	const lookback, w = 60, 3
+	from := max(0, i-lookback)
+	lows := swingLows(bars, from, i, w)
+
+	for b := len(lows) - 1; b >= 1; b-- {
+		second := lows[b]
+		if i-second > 20 {
+			break
+		}
+		for a := b - 1; a >= 0; a-- {
+			first := lows[a]
+			if second-first < 10 {
+				continue
+			}
+			l1, l2 := bars[first].Low, bars[second].Low
+			if math.Abs(l1-l2)/math.Min(l1, l2) > 0.03 {
+				continue
+			}
+			neckline, _ := highLow(bars, first, second)
+			if neckline < math.Max(l1, l2)*1.05 {
+				continue
+			}
+			// sejak bottom kedua harga nggak boleh tembus bawah bottom
+			if _, lo := highLow(bars, second, i); lo < math.Min(l1, l2) {
+				continue
+			}
+
+			return models.PatternHit{
+				Name:      PatternDoubleBottom,
+				Bias:      BiasBullish,
+				Confirmed: bars[i].Close > neckline,
+				Detail:    fmt.Sprintf("bottoms %.0f / %.0f, neckline %.0f", l1, l2, neckline),
+			}, true
+		}
+	}
+	return models.PatternHit{}, false
+}
+
+// detectTightConsolidation (gaya VCP): 45 bar dibagi 3 segmen, range tiap segmen makin sempit,
+// segmen terakhir <= 10% dan volumenya lebih kecil dari segmen pertama.
+// Confirmed kalau close tembus high segmen terakhir.
+func detectTightConsolidation(bars []models.DailyBar, i int) (models.PatternHit, bool) {
+	const segment, segments = 15, 3
+	start := i - segment*segments + 1
+	if start < 0 {
+		return models.PatternHit{}, false
+	}
+
+	ranges := make([]float64, segments)
+	for s := 0; s < segments; s++ {
+		from := start + s*segment
+		to := from + segment - 1
+		if s == segments-1 {
+			to = i - 1 // bar as_of dipakai buat cek breakout
+		}
+		hi, lo := highLow(bars, from, to)
+		if lo <= 0 || lo == math.MaxFloat64 {
+			return models.PatternHit{}, false
+		}
+		ranges[s] = (hi - lo) / lo * 100
+	}
+
+	if ranges[0] <= ranges[1] || ranges[1] <= ranges[2] || ranges[2] > 10 {
+		return models.PatternHit{}, false
+	}
+
+	lastFrom := start + (segments-1)*segment
+	if avgVolume(bars, lastFrom, i-1) >= avgVolume(bars, start, start+segment-1) {
+		return models.PatternHit{}, false
+	}
+
+	pivot, _ := highLow(bars, lastFrom, i-1)
+	return models.PatternHit{
+		Name:      PatternTightConsolidation,
+		Bias:      BiasBullish,
+		Confirmed: bars[i].Close > pivot,
+		Detail:    fmt.Sprintf("contractions %.1f%% > %.1f%% > %.1f%%, pivot %.0f", ranges[0], ranges[1], ranges[2], pivot),
+	}, true
+}
+
+// DetectPatterns = candle + chart pattern di bar i
+func DetectPatterns(bars []models.DailyBar, i int) []models.PatternHit {
+	return append(DetectCandlePatterns(bars, i), DetectChartPatterns(bars, i)...)
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"indonesia-stocks-api/internal/models"
+	"slices"
+	"testing"
+)
+
+func ohlc(open, high, low, close, volume float64) models.DailyBar {
+	return models.DailyBar{Open: open, High: high, Low: low, Close: close, Volume: volume}
+}
+
+// dated isi tanggal berurutan, bars[0] paling lama
+func dated(bars ...models.DailyBar) []models.DailyBar {
+	for i := range bars {
+		bars[i].TradeDate = testDay(i)
+	}
+	return bars
+}
+
+// trendBars = 6 bar dengan close naik/turun step per hari, buat priorTrend
+func trendBars(from, step float64) []models.DailyBar {
+	bars := []models.DailyBar{}
+	for j := 0; j < 6; j++ {
+		c := from + step*float64(j)
+		bars = append(bars, ohlc(c, c+0.5, c-0.5, c, 1000))
+	}
+	return bars
+}
+
+func TestDetectCandlePatterns(t *testing.T) {
+	down := trendBars(110, -2) // 110 -> 100
+	up := trendBars(90, 2)     // 90 -> 100
+
+	tests := []struct {
+		name    string
+		bars    []models.DailyBar
+		pattern string
+		want    bool
+	}{
+		{"hammer after decline", append(slices.Clone(down), ohlc(100, 101, 94, 101, 1000)), PatternHammer, true},
+		{"hammer shape after rally", append(slices.Clone(up), ohlc(100, 101, 94, 101, 1000)), PatternHammer, false},
+		{"shooting star after rally", append(slices.Clone(up), ohlc(100, 107, 99, 99, 1000)), PatternShootingStar, true},
+		{"shooting star shape after decline", append(slices.Clone(down), ohlc(100, 107, 99, 99, 1000)), PatternShootingStar, false},
+		{"doji", []models.DailyBar{ohlc(100, 103, 97, 100.2, 1000)}, PatternDoji, true},
+		{"full body is no doji", []models.DailyBar{ohlc(97, 103, 97, 103, 1000)}, PatternDoji, false},
+		{"bullish engulfing", []models.DailyBar{ohlc(102, 102.5, 99.5, 100, 1000), ohlc(99.5, 103.5, 99, 103, 1000)}, PatternBullishEngulfing, true},
+		{"bullish close below prior open", []models.DailyBar{ohlc(102, 102.5, 99.5, 100, 1000), ohlc(99.5, 101.5, 99, 101, 1000)}, PatternBullishEngulfing, false},
+		{"bearish engulfing", []models.DailyBar{ohlc(100, 102.5, 99.5, 102, 1000), ohlc(102.5, 103, 98.5, 99, 1000)}, PatternBearishEngulfing, true},
+		{"bearish close above prior open", []models.DailyBar{ohlc(100, 102.5, 99.5, 102, 1000), ohlc(102.5, 103, 100.5, 101, 1000)}, PatternBearishEngulfing, false},
+		{"inside bar", []models.DailyBar{ohlc(95, 110, 90, 105, 1000), ohlc(100, 105, 95, 102, 1000)}, PatternInsideBar, true},
+		{"range breaks prior high", []models.DailyBar{ohlc(95, 110, 90, 105, 1000), ohlc(100, 111, 95, 102, 1000)}, PatternInsideBar, false},
+		{
+			"morning star",
+			[]models.DailyBar{ohlc(110, 111, 99, 100, 1000), ohlc(99, 99.5, 98, 98.5, 1000), ohlc(99, 106.5, 98.5, 106, 1000)},
+			PatternMorningStar, true,
+		},
+		{
+			"morning star closing below midpoint",
+			[]models.DailyBar{ohlc(110, 111, 99, 100, 1000), ohlc(99, 99.5, 98, 98.5, 1000), ohlc(99, 104.5, 98.5, 104, 1000)},
+			PatternMorningStar, false,
+		},
+		{
+			"evening star",
+			[]models.DailyBar{ohlc(100, 111, 99, 110, 1000), ohlc(111, 112, 110.5, 111.5, 1000), ohlc(111, 111.5, 103.5, 104, 1000)},
+			PatternEveningStar, true,
+		},
+		{
+			"evening star closing above midpoint",
+			[]models.DailyBar{ohlc(100, 111, 99, 110, 1000), ohlc(111, 112, 110.5, 111.5, 1000), ohlc(111, 111.5, 105.5, 106, 1000)},
+			PatternEveningStar, false,
+		},
+		{"untraded bar", []models.DailyBar{ohlc(100, 103, 97, 100.2, 0)}, PatternDoji, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bars := dated(tt.bars...)
+			hits := DetectCandlePatterns(bars, len(bars)-1)
+			found := slices.ContainsFunc(hits, func(h models.PatternHit) bool { return h.Name == tt.pattern })
+			if found != tt.want {
+				t.Errorf("%s detected = %v, want %v (hits %+v)", tt.pattern, found, tt.want, hits)
+			}
+		})
+	}
+}
+
+// bullFlagBars: tiang 99 -> 121 dalam 11 bar, bendera 5 bar di flagLow-119 dengan volume kecil, lalu bar terakhir
+func bullFlagBars(flagLow float64, last models.DailyBar) []models.DailyBar {
+	bars := []models.DailyBar{}
+	for j := 0; j <= 10; j++ {
+		c := 100 + 2*float64(j)
+		bars = append(bars, ohlc(c, c+1, c-1, c, 1000))
+	}
+	for j := 0; j < 5; j++ {
+		bars = append(bars, ohlc(116, 119, flagLow, 116, 300))
+	}
+	return dated(append(bars, last)...)
+}
+
+// doubleBottomBars: bottom 100 di bar 5, puncak high 114 di bar 11, bottom kedua di bar 17, lalu naik
+func doubleBottomBars(secondLow float64, last models.DailyBar) []models.DailyBar {
+	lows := []float64{110, 108, 106, 104, 102, 100, 102, 104, 106, 108, 110, 112, 110, 108, 106, 104, 102, secondLow, 103, 105, 107, 109}
+	bars := []models.DailyBar{}
+	for _, l := range lows {
+		bars = append(bars, ohlc(l+1, l+2, l, l+1, 1000))
+	}
+	return dated(append(bars, last)...)
+}
+
+// consolidationBars: 3 segmen 15 bar (bar terakhir segmen 3 = bar breakout), high/low & volume per segmen
+func consolidationBars(segments [3][3]float64, last models.DailyBar) []models.DailyBar {
+	bars := []models.DailyBar{}
+	for s, seg := range segments {
+		n := 15
+		if s == 2 {
+			n = 14
+		}
+		for j := 0; j < n; j++ {
+			mid := (seg[0] + seg[1]) / 2
+			bars = append(bars, ohlc(mid, seg[0], seg[1], mid, seg[2]))
+		}
+	}
+	return dated(append(bars, last)...)
+}
+
+func TestDetectChartPatterns(t *testing.T) {
+	tight := [3][3]float64{{120, 100, 1000}, {115, 105, 700}, {112, 108, 400}}
+	loose := [3][3]float64{{120, 100, 1000}, {115, 105, 700}, {118, 102, 400}}
+
+	tests := []struct {
+		name          string
+		detect        func([]models.DailyBar, int) (models.PatternHit, bool)
+		bars          []models.DailyBar
+		want          bool
+		wantConfirmed bool
+	}{
+		{"bull flag forming", detectBullFlag, bullFlagBars(114, ohlc(116, 119, 115, 118, 300)), true, false},
+		{"bull flag breakout", detectBullFlag, bullFlagBars(114, ohlc(119, 124, 118, 123, 300)), true, true},
+		{"flag retraces over half the pole", detectBullFlag, bullFlagBars(105, ohlc(116, 119, 115, 118, 300)), false, false},
+		{"double bottom below neckline", detectDoubleBottom, doubleBottomBars(101, ohlc(111, 113, 111, 112, 1000)), true, false},
+		{"double bottom above neckline", detectDoubleBottom, doubleBottomBars(101, ohlc(112, 118, 111, 116, 1000)), true, true},
+		{"second bottom 10% lower", detectDoubleBottom, doubleBottomBars(90, ohlc(111, 113, 111, 112, 1000)), false, false},
+		{"tight consolidation breakout", detectTightConsolidation, consolidationBars(tight, ohlc(112, 114, 110, 113, 800)), true, true},
+		{"tight consolidation inside pivot", detectTightConsolidation, consolidationBars(tight, ohlc(110, 111, 109, 110, 300)), true, false},
+		{"last contraction wider", detectTightConsolidation, consolidationBars(loose, ohlc(112, 114, 110, 113, 800)), false, false},
+		{"not enough history", detectTightConsolidation, dated(ohlc(100, 101, 99, 100, 1000)), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, ok := tt.detect(tt.bars, len(tt.bars)-1)
+			if ok != tt.want || hit.Confirmed != tt.wantConfirmed {
+				t.Errorf("detected = %v, confirmed = %v, want %v and %v (%s)", ok, hit.Confirmed, tt.want, tt.wantConfirmed, hit.Detail)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"math"
+	"time"
+)
+
+const (
+	SizingEqualWeight = "equal_weight"
+	SizingFixedRisk   = "fixed_risk"
+	SizingVolatility  = "volatility"
+
+	DefaultMaxPositions = 10
+	DefaultRiskPct      = 1.0
+	DefaultATRPeriod    = 14
+
+	RejectNoCash       = "NOT_FILLED_CASH"
+	RejectMaxPositions = "SKIPPED_MAX_POSITIONS"
+)
+
+// NormalizePortfolioConfig: InitialCapital 0 berarti mode per sinyal (tanpa modal), selain itu isi default
+func NormalizePortfolioConfig(cfg *models.PortfolioConfig) error {
+	if cfg.InitialCapital <= 0 {
+		return nil
+	}
+	if cfg.MaxPositions <= 0 {
+		cfg.MaxPositions = DefaultMaxPositions
+	}
+	if cfg.Sizing == "" {
+		cfg.Sizing = SizingEqualWeight
+	}
+	switch cfg.Sizing {
+	case SizingEqualWeight, SizingFixedRisk, SizingVolatility:
+	default:
+		return fmt.Errorf("invalid sizing %q, use %s, %s or %s", cfg.Sizing, SizingEqualWeight, SizingFixedRisk, SizingVolatility)
+	}
+	if cfg.RiskPct <= 0 {
+		cfg.RiskPct = DefaultRiskPct
+	}
+	if cfg.ATRPeriod <= 0 {
+		cfg.ATRPeriod = DefaultATRPeriod
+	}
+	return nil
+}
+
+// atr = Average True Range sederhana (rata-rata TR N bar terakhir sampai index i)
+func (p *priceSeries) atr(i, period int) float64 {
+	if period <= 0 || i < period {
+		return 0
+	}
+	sum := 0.0
+	for j := i - period + 1; j <= i; j++ {
+		b := p.bars[j]
+		prev := p.bars[j-1].Close
+		tr := math.Max(b.High-b.Low, math.Max(math.Abs(b.High-prev), math.Abs(b.Low-prev)))
+		sum += tr
+	}
+	return sum / float64(period)
+}
+
+// portfolioBook pegang kas dan equity curve selama simulasi portfolio
+type portfolioBook struct {
+	cfg   models.PortfolioConfig
+	exec  models.ExecutionConfig
+	cash  float64
+	curve []models.EquityPoint
+}
+
+func newPortfolioBook(cfg models.PortfolioConfig, exec models.ExecutionConfig) *portfolioBook {
+	return &portfolioBook{cfg: cfg, exec: exec, cash: cfg.InitialCapital}
+}
+
+func (b *portfolioBook) enabled() bool {
+	return b.cfg.InitialCapital > 0
+}
+
+func positionValue(positions map[string]*openPosition) float64 {
+	total := 0.0
+	for _, pos := range positions {
+		total += float64(pos.trade.Lots*LotSize) * pos.lastClose
+	}
+	return total
+}
+
+func (b *portfolioBook) equity(positions map[string]*openPosition) float64 {
+	return b.cash + positionValue(positions)
+}
+
+// lots hitung ukuran posisi sesuai mode sizing, dibatasi kas yang tersedia
+func (b *portfolioBook) lots(pos *openPosition, s *priceSeries, i int, positions map[string]*openPosition) (int, string) {
+	if len(positions) >= b.cfg.MaxPositions {
+		return 0, RejectMaxPositions
+	}
+
+	price := pos.trade.EntryPrice
+	equity := b.equity(positions)
+	targetValue := equity / float64(b.cfg.MaxPositions)
+
+	switch b.cfg.Sizing {
+	case SizingFixedRisk:
+		// Risiko per trade = RiskPct dari equity, dibagi jarak ke stop loss.
+		// Kalau nggak ada stop, balik ke equal weight.
+		if stop := pos.trade.StopLossLevel; stop > 0 && stop < price {
+			shares := (equity * b.cfg.RiskPct / 100) / (price - stop)
+			targetValue = shares * price
+		}
+	case SizingVolatility:
+		// Makin volatil (ATR besar) makin kecil posisinya
+		if atr := s.atr(i, b.cfg.ATRPeriod); atr > 0 {
+			shares := (equity * b.cfg.RiskPct / 100) / atr
+			targetValue = shares * price
+		}
+	}
+
+	if b.cfg.MaxPositionPct > 0 {
+		targetValue = math.Min(targetValue, equity*b.cfg.MaxPositionPct/100)
+	}
+	targetValue = math.Min(targetValue, b.cash)
+
+	lots := lotsFor(targetValue, price, b.exec)
+	if lots <= 0 {
+		return 0, RejectNoCash
+	}
+	return lots, ""
+}
+
+func (b *portfolioBook) open(t models.BacktestTrade) {
+	value := float64(t.Lots*LotSize) * t.EntryPrice
+	b.cash -= value * (1 + feePct(b.exec.BuyFeePct)/100)
+}
+
+func (b *portfolioBook) close(t models.BacktestTrade) {
+	value := float64(t.Lots*LotSize) * t.ExitPrice
+	b.cash += value * (1 - (feePct(b.exec.SellFeePct)+feePct(b.exec.SellTaxPct))/100)
+}
+
+// mark catat equity akhir hari pakai harga close terakhir tiap posisi
+func (b *portfolioBook) mark(day time.Time, positions map[string]*openPosition) {
+	posValue := positionValue(positions)
+	equity := b.cash + posValue
+
+	point := models.EquityPoint{
+		Date:           day,
+		Cash:           b.cash,
+		PositionsValue: posValue,
+		Equity:         equity,
+		OpenPositions:  len(positions),
+	}
+
+	prev := b.cfg.InitialCapital
+	if n := len(b.curve); n > 0 {
+		prev = b.curve[n-1].Equity
+	}
+	if prev > 0 {
+		point.DailyReturnPct = ((equity - prev) / prev) * 100
+	}
+
+	b.curve = append(b.curve, point)
+}
+
+// settle dipanggil setelah likuidasi akhir test biar titik terakhir = kas bersih
+func (b *portfolioBook) settle() {
+	n := len(b.curve)
+	if n == 0 {
+		return
+	}
+	last := &b.curve[n-1]
+	last.Cash = b.cash
+	last.PositionsValue = 0
+	last.Equity = b.cash
+	last.OpenPositions = 0
+
+	prev := b.cfg.InitialCapital
+	if n > 1 {
+		prev = b.curve[n-2].Equity
+	}
+	if prev > 0 {
+		last.DailyReturnPct = ((last.Equity - prev) / prev) * 100
+	}
+}
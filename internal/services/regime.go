@@ -0,0 +1,217 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/constants"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	RegimeBull   = "bull"
+	RegimeBear   = "bear"
+	RegimeChoppy = "choppy"
+
+	RegimeActionSuppress  = "suppress"
+	RegimeActionDowngrade = "downgrade"
+
+	regimeVolPeriod       = 20
+	regimeVolWindow       = 250 // percentile volatilitas vs 1 tahun
+	regimeSlopeBars       = 10  // slope MA50 = MA50 sekarang vs 10 bar lalu
+	regimeHighVolPct      = 80  // di atas ini pasar dianggap terlalu liar buat bull
+	regimeWarmupDays      = 420 // hari kalender buat MA200 + window volatilitas
+	regimeDowngradeStatus = "👀 WATCH (%s MARKET)"
+)
+
+// buySignalCategories = kategori yang dianggap sinyal beli, ini yang di-suppress / downgrade
+var buySignalCategories = []string{"GOLDEN_SIGNAL", "TESTING_RES", "HAKA", "RETRACE", "BOW", "BREAKOUT"}
+
+func RegimeNames() []string {
+	return []string{RegimeBull, RegimeChoppy, RegimeBear}
+}
+
+// NormalizeRegimeConfig pecah hostile_regime comma-separated, default hostile = bear
+func NormalizeRegimeConfig(cfg *models.RegimeConfig) error {
+	cfg.Action = strings.ToLower(strings.TrimSpace(cfg.Action))
+	switch cfg.Action {
+	case "", RegimeActionSuppress, RegimeActionDowngrade:
+	default:
+		return fmt.Errorf("invalid regime_action %q, use %s or %s", cfg.Action, RegimeActionSuppress, RegimeActionDowngrade)
+	}
+
+	hostile := []string{}
+	for _, raw := range cfg.Hostile {
+		for _, r := range strings.Split(raw, ",") {
+			r = strings.ToLower(strings.TrimSpace(r))
+			if r == "" {
+				continue
+			}
+			if !slices.Contains(RegimeNames(), r) {
+				return fmt.Errorf("unknown hostile_regime %q, available: %v", r, RegimeNames())
+			}
+			hostile = append(hostile, r)
+		}
+	}
+	if len(hostile) == 0 && cfg.Action != "" {
+		hostile = []string{RegimeBear}
+	}
+	cfg.Hostile = hostile
+	return nil
+}
+
+// ConditionSignal sesuaikan status screener dengan regime. Balikin status baru dan
+// false kalau sinyal harus dibuang.
+func ConditionSignal(status string, regime *models.MarketRegime, cfg models.RegimeConfig) (string, bool) {
+	if cfg.Action == "" || regime == nil || !slices.Contains(cfg.Hostile, regime.Regime) {
+		return status, true
+	}
+	if !slices.Contains(buySignalCategories, SignalCategory(status)) {
+		return status, true
+	}
+	if cfg.Action == RegimeActionSuppress {
+		return status, false
+	}
+	return fmt.Sprintf(regimeDowngradeStatus, strings.ToUpper(regime.Regime)), true
+}
+
+// classifyRegime: trend IHSG + breadth jadi skor, volatilitas tinggi nahan bull jadi choppy
+func classifyRegime(r *models.MarketRegime) {
+	r.Score = r.TrendScore
+	switch r.BreadthRegime {
+	case RegimeRiskOn:
+		r.Score++
+	case RegimeRiskOff:
+		r.Score--
+	}
+
+	switch {
+	case r.Score >= 2 && r.VolPercentile < regimeHighVolPct:
+		r.Regime = RegimeBull
+	case r.Score <= -2:
+		r.Regime = RegimeBear
+	default:
+		r.Regime = RegimeChoppy
+	}
+}
+
+func closesSMA(closes []float64, i, period int) float64 {
+	if i+1 < period {
+		return 0
+	}
+	sum := 0.0
+	for j := i - period + 1; j <= i; j++ {
+		sum += closes[j]
+	}
+	return sum / float64(period)
+}
+
+// regimeSeries hitung regime tiap hari indeks mulai index from. breadth per tanggal boleh bolong.
+func regimeSeries(index []models.IndexClose, breadth map[string]models.MarketBreadth, from int) []models.MarketRegime {
+	closes := make([]float64, len(index))
+	for i, c := range index {
+		closes[i] = c.Close
+	}
+
+	// volatilitas 20 hari tiap bar, 0 kalau histori kurang
+	vols := make([]float64, len(closes))
+	for i := regimeVolPeriod; i < len(closes); i++ {
+		rets := make([]float64, 0, regimeVolPeriod)
+		for j := i - regimeVolPeriod + 1; j <= i; j++ {
+			if closes[j-1] > 0 && closes[j] > 0 {
+				rets = append(rets, math.Log(closes[j]/closes[j-1]))
+			}
+		}
+		_, std := meanStd(rets)
+		vols[i] = std * math.Sqrt(tradingDaysPerYear) * 100
+	}
+
+	out := make([]models.MarketRegime, 0, len(index)-from)
+	for i := from; i < len(index); i++ {
+		r := models.MarketRegime{
+			TradeDate:     index[i].TradeDate,
+			IndexCode:     constants.IndexComposite,
+			IndexClose:    closes[i],
+			MA50:          closesSMA(closes, i, 50),
+			MA200:         closesSMA(closes, i, 200),
+			VolatilityPct: vols[i],
+		}
+
+		if r.MA200 > 0 {
+			r.TrendScore += sign(r.IndexClose > r.MA200)
+			r.TrendScore += sign(r.MA50 > r.MA200)
+		}
+		if prev := closesSMA(closes, i-regimeSlopeBars, 50); r.MA50 > 0 && prev > 0 {
+			r.TrendScore += sign(r.MA50 > prev)
+		}
+
+		if vols[i] > 0 {
+			below, n := 0, 0
+			for j := max(regimeVolPeriod, i-regimeVolWindow+1); j <= i; j++ {
+				n++
+				if vols[j] <= vols[i] {
+					below++
+				}
+			}
+			r.VolPercentile = float64(below) / float64(n) * 100
+		}
+
+		if b, ok := breadth[r.TradeDate.Format("2006-01-02")]; ok {
+			r.BreadthRegime = BreadthRegime(b)
+			pct := b.PctAboveMA50
+			r.PctAboveMA50 = &pct
+		}
+
+		classifyRegime(&r)
+		out = append(out, r)
+	}
+	return out
+}
+
+func sign(up bool) int {
+	if up {
+		return 1
+	}
+	return -1
+}
+
+// GetMarketRegime = regime N hari bursa terakhir sampai as_of. Breadth cuma dibaca dari yang
+// tersimpan, hari yang belum di-/breadth/compute regime-nya dari trend IHSG saja.
+func GetMarketRegime(asOf string, days int) ([]models.MarketRegime, error) {
+	end, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return nil, err
+	}
+	start := end.AddDate(0, 0, -(days*7/5 + regimeWarmupDays)).Format("2006-01-02")
+
+	index, err := repositories.GetIndexCloses(constants.IndexComposite, start, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if len(index) == 0 {
+		return []models.MarketRegime{}, nil
+	}
+
+	rows, err := repositories.GetMarketBreadth(start, asOf)
+	if err != nil {
+		return nil, err
+	}
+	breadth := make(map[string]models.MarketBreadth, len(rows))
+	for _, b := range rows {
+		breadth[b.TradeDate.Format("2006-01-02")] = b
+	}
+
+	return regimeSeries(index, breadth, max(0, len(index)-days)), nil
+}
+
+// CurrentMarketRegime = regime di hari bursa terakhir <= as_of, nil kalau data IHSG belum ada
+func CurrentMarketRegime(asOf string) (*models.MarketRegime, error) {
+	rows, err := GetMarketRegime(asOf, 1)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return &rows[0], nil
+}
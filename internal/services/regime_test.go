@@ -0,0 +1,166 @@
+package services
+
+import (
+	"indonesia-stocks-api/internal/models"
+	"math"
+	"testing"
+)
+
+func TestClassifyRegime(t *testing.T) {
+	tests := []struct {
+		name      string
+		trend     int
+		breadth   string
+		volPct    float64
+		wantScore int
+		want      string
+	}{
+		{"full uptrend with risk_on breadth", 3, RegimeRiskOn, 50, 4, RegimeBull},
+		{"score 2 without breadth", 2, "", 79.9, 2, RegimeBull},
+		{"weak trend lifted by breadth", 1, RegimeRiskOn, 10, 2, RegimeBull},
+		{"risk_off breadth still leaves score 2", 3, RegimeRiskOff, 0, 2, RegimeBull},
+		{"high volatility holds bull at the threshold", 2, "", regimeHighVolPct, 2, RegimeChoppy},
+		{"high volatility holds strong bull", 3, RegimeRiskOn, 95, 4, RegimeChoppy},
+		{"neutral breadth adds nothing", 1, RegimeNeutral, 10, 1, RegimeChoppy},
+		{"uptrend cancelled by risk_off", 1, RegimeRiskOff, 10, 0, RegimeChoppy},
+		{"downtrend softened by risk_on", -2, RegimeRiskOn, 10, -1, RegimeChoppy},
+		{"weak downtrend with risk_off", -1, RegimeRiskOff, 10, -2, RegimeBear},
+		{"high volatility does not block bear", -3, "", 99, -3, RegimeBear},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := models.MarketRegime{TrendScore: tt.trend, BreadthRegime: tt.breadth, VolPercentile: tt.volPct}
+			classifyRegime(&r)
+			if r.Score != tt.wantScore || r.Regime != tt.want {
+				t.Errorf("score/regime = %d/%s, want %d/%s", r.Score, r.Regime, tt.wantScore, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionSignal(t *testing.T) {
+	bear := &models.MarketRegime{Regime: RegimeBear}
+	bull := &models.MarketRegime{Regime: RegimeBull}
+	suppress := models.RegimeConfig{Action: RegimeActionSuppress, Hostile: []string{RegimeBear}}
+	downgrade := models.RegimeConfig{Action: RegimeActionDowngrade, Hostile: []string{RegimeBear, RegimeChoppy}}
+
+	tests := []struct {
+		name       string
+		status     string
+		regime     *models.MarketRegime
+		cfg        models.RegimeConfig
+		wantStatus string
+		wantKeep   bool
+	}{
+		{"report only leaves signals alone", "🔥 GOLDEN SIGNAL", bear, models.RegimeConfig{Hostile: []string{RegimeBear}}, "🔥 GOLDEN SIGNAL", true},
+		{"no regime data", "🔥 GOLDEN SIGNAL", nil, suppress, "🔥 GOLDEN SIGNAL", true},
+		{"regime not hostile", "🔥 GOLDEN SIGNAL", bull, suppress, "🔥 GOLDEN SIGNAL", true},
+		{"suppress buy signal", "🔥 GOLDEN SIGNAL", bear, suppress, "🔥 GOLDEN SIGNAL", false},
+		{"suppress breakout", "🚀 BREAKOUT", bear, suppress, "🚀 BREAKOUT", false},
+		{"suppress keeps non-buy signal", "🐋 WHALE ONLY", bear, suppress, "🐋 WHALE ONLY", true},
+		{"downgrade buy signal", "⚡ HAKA", bear, downgrade, "👀 WATCH (BEAR MARKET)", true},
+		{"downgrade testing res before haka", "🎯 TESTING RES (SIAP HAKA)", &models.MarketRegime{Regime: RegimeChoppy}, downgrade, "👀 WATCH (CHOPPY MARKET)", true},
+		{"downgrade keeps non-buy signal", "🧘 SIDEWAYS", bear, downgrade, "🧘 SIDEWAYS", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, keep := ConditionSignal(tt.status, tt.regime, tt.cfg)
+			if status != tt.wantStatus || keep != tt.wantKeep {
+				t.Errorf("ConditionSignal = %q/%v, want %q/%v", status, keep, tt.wantStatus, tt.wantKeep)
+			}
+		})
+	}
+}
+
+// regimeIndex = IHSG sintetis, close[i] = 1000 * (1+drift)^i dengan noise selang-seling
+// (+noise / -noise) mulai bar noiseFrom
+func regimeIndex(n int, drift, noise float64, noiseFrom int) []models.IndexClose {
+	index := make([]models.IndexClose, n)
+	for i := range index {
+		c := 1000 * math.Pow(1+drift, float64(i))
+		if i >= noiseFrom {
+			c *= 1 + noise*float64(1-2*(i%2))
+		}
+		index[i] = models.IndexClose{TradeDate: testDay(i), Close: c}
+	}
+	return index
+}
+
+func TestRegimeSeries(t *testing.T) {
+	last := testDay(259).Format("2006-01-02")
+
+	tests := []struct {
+		name      string
+		index     []models.IndexClose
+		breadth   map[string]models.MarketBreadth
+		wantTrend int
+		wantScore int
+		highVol   bool // vol percentile >= regimeHighVolPct
+		want      string
+		wantPct   *float64
+	}{
+		{
+			"steady uptrend without breadth",
+			regimeIndex(260, 0.002, 0, 260), nil,
+			3, 3, false, RegimeBull, nil,
+		},
+		{
+			"steady uptrend with risk_on breadth",
+			regimeIndex(260, 0.002, 0, 260),
+			map[string]models.MarketBreadth{last: {PctAboveMA50: 70, McClellanOsc: 12}},
+			3, 4, false, RegimeBull, float64Ptr(70),
+		},
+		{
+			"steady downtrend with risk_off breadth",
+			regimeIndex(260, -0.002, 0, 260),
+			map[string]models.MarketBreadth{last: {PctAboveMA50: 20, McClellanOsc: -8}},
+			-3, -4, false, RegimeBear, float64Ptr(20),
+		},
+		{
+			"uptrend with a volatility spike turns choppy",
+			regimeIndex(260, 0.002, 0.03, 250), nil,
+			3, 3, true, RegimeChoppy, nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := regimeSeries(tt.index, tt.breadth, 255)
+			if len(got) != 5 {
+				t.Fatalf("len = %d, want 5", len(got))
+			}
+			r := got[len(got)-1]
+			if !r.TradeDate.Equal(testDay(259)) {
+				t.Errorf("last trade date = %v, want %v", r.TradeDate, testDay(259))
+			}
+			if r.MA200 <= 0 || r.MA50 <= 0 {
+				t.Errorf("MA50/MA200 = %v/%v, want both computed", r.MA50, r.MA200)
+			}
+			if r.TrendScore != tt.wantTrend || r.Score != tt.wantScore || r.Regime != tt.want {
+				t.Errorf("trend/score/regime = %d/%d/%s, want %d/%d/%s", r.TrendScore, r.Score, r.Regime, tt.wantTrend, tt.wantScore, tt.want)
+			}
+			if (r.VolPercentile >= regimeHighVolPct) != tt.highVol {
+				t.Errorf("vol percentile = %v, want high volatility %v", r.VolPercentile, tt.highVol)
+			}
+			if !almostEqualPtr(r.PctAboveMA50, tt.wantPct) {
+				t.Errorf("pct above MA50 = %s, want %s", fmtPtr(r.PctAboveMA50), fmtPtr(tt.wantPct))
+			}
+		})
+	}
+}
+
+func TestRegimeSeriesWarmup(t *testing.T) {
+	// 70 bar: MA200 belum ada, trend cuma dari slope MA50
+	got := regimeSeries(regimeIndex(70, 0.002, 0, 70), nil, 0)
+	if len(got) != 70 {
+		t.Fatalf("len = %d, want 70", len(got))
+	}
+	if r := got[10]; r.MA50 != 0 || r.TrendScore != 0 || r.Regime != RegimeChoppy {
+		t.Errorf("bar 10 MA50/trend/regime = %v/%d/%s, want 0/0/choppy", r.MA50, r.TrendScore, r.Regime)
+	}
+	if r := got[69]; r.MA200 != 0 || r.TrendScore != 1 || r.Regime != RegimeChoppy {
+		t.Errorf("bar 69 MA200/trend/regime = %v/%d/%s, want 0/1/choppy", r.MA200, r.TrendScore, r.Regime)
+	}
+}
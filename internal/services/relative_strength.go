@@ -0,0 +1,305 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"indonesia-stocks-api/internal/constants"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	rsLineHighLookback = 252 // 52 minggu
+	rsMinHistory       = 63  // minimal punya return 3 bulan buat masuk universe
+
+	// Histori (hari kalender) sebelum start buat return 12 bulan & high RS line 52 minggu
+	rsWarmupDays = 380
+)
+
+// ErrRSNotComputed = RS hari itu belum pernah di-POST /rs/compute. Endpoint baca nggak menghitung
+// sendiri, sekali hitung butuh bar semua saham ~380 hari ke belakang.
+var ErrRSNotComputed = errors.New("relative strength not computed")
+
+// Bobot gaya IBD: kinerja 3 bulan terakhir dihitung paling berat
+var rsHorizons = []struct {
+	days   int
+	weight float64
+}{
+	{63, 0.4},  // 3 bulan
+	{126, 0.3}, // 6 bulan
+	{252, 0.3}, // 12 bulan
+}
+
+// rsPercentile ubah skor jadi rating 1-99 (99 = paling kuat). Skor sama dapat rating sama
+// (rata-rata rank grupnya), jadi hasilnya nggak tergantung urutan map.
+func rsPercentile(scores []float64) []int {
+	n := len(scores)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return scores[order[a]] < scores[order[b]] })
+
+	ratings := make([]int, n)
+	for start := 0; start < n; {
+		end := start
+		for end+1 < n && scores[order[end+1]] == scores[order[start]] {
+			end++
+		}
+		rating := 99
+		if n > 1 {
+			rank := float64(start+end) / 2
+			rating = 1 + int(math.Round(rank/float64(n-1)*98))
+		}
+		for _, idx := range order[start : end+1] {
+			ratings[idx] = rating
+		}
+		start = end + 1
+	}
+	return ratings
+}
+
+// rsStock = close harian satu saham di kalender bursa (forward fill selama suspend)
+type rsStock struct {
+	name   string
+	close  []float64
+	traded []bool
+}
+
+// computeRelativeStrength hitung RS untuk hari ke-from sampai akhir kalender `days`
+func computeRelativeStrength(days []time.Time, from int, series map[string]*priceSeries, benchmark []models.IndexClose) []models.RelativeStrength {
+	dayIdx := make(map[string]int, len(days))
+	for i, d := range days {
+		dayIdx[d.Format("2006-01-02")] = i
+	}
+
+	bench := make([]float64, len(days))
+	for _, b := range benchmark {
+		if i, ok := dayIdx[b.TradeDate.Format("2006-01-02")]; ok {
+			bench[i] = b.Close
+		}
+	}
+
+	stocks := map[string]*rsStock{}
+	for code, s := range series {
+		st := &rsStock{close: make([]float64, len(days)), traded: make([]bool, len(days))}
+		for _, b := range s.bars {
+			if i, ok := dayIdx[b.TradeDate.Format("2006-01-02")]; ok && b.Close > 0 {
+				st.close[i] = b.Close
+				st.traded[i] = b.Volume > 0
+				st.name = b.StockName
+			}
+		}
+		for i := 1; i < len(days); i++ {
+			if st.close[i] == 0 {
+				st.close[i] = st.close[i-1]
+			}
+		}
+		stocks[code] = st
+	}
+
+	result := []models.RelativeStrength{}
+	for d := from; d < len(days); d++ {
+		rows := []models.RelativeStrength{}
+		scores := []float64{}
+
+		for code, st := range stocks {
+			if !st.traded[d] || d < rsMinHistory || st.close[d-rsMinHistory] <= 0 {
+				continue
+			}
+
+			row := models.RelativeStrength{TradeDate: days[d], StockCode: code, StockName: st.name, Close: st.close[d]}
+			score, weight := 0.0, 0.0
+			for k, h := range rsHorizons {
+				if d < h.days || st.close[d-h.days] <= 0 {
+					continue
+				}
+				ret := (st.close[d]/st.close[d-h.days] - 1) * 100
+				switch k {
+				case 0:
+					row.Return3M = &ret
+				case 1:
+					row.Return6M = &ret
+				case 2:
+					row.Return12M = &ret
+				}
+				score += ret * h.weight
+				weight += h.weight
+			}
+			// horizon yang belum ada (saham baru listing) bobotnya dibagi ke yang ada
+			row.WeightedScore = score / weight
+
+			if bench[d] > 0 {
+				line := st.close[d] / bench[d]
+				row.RSLine = &line
+
+				high, points := 0.0, 0
+				for j := max(0, d-rsLineHighLookback); j < d; j++ {
+					if bench[j] > 0 && st.close[j] > 0 {
+						high = math.Max(high, st.close[j]/bench[j])
+						points++
+					}
+				}
+				row.RSLineNewHigh = points >= rsMinHistory && line >= high
+			}
+
+			rows = append(rows, row)
+			scores = append(scores, row.WeightedScore)
+		}
+
+		for i, rating := range rsPercentile(scores) {
+			rows[i].RSRating = rating
+		}
+		result = append(result, rows...)
+	}
+
+	return result
+}
+
+// NormalizeRSComputeRequest: end_date kosong = satu hari (start_date)
+func NormalizeRSComputeRequest(req *models.RSComputeRequest) error {
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return fmt.Errorf("invalid start_date, format: YYYY-MM-DD")
+	}
+	if req.EndDate == "" {
+		req.EndDate = req.StartDate
+	}
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return fmt.Errorf("invalid end_date, format: YYYY-MM-DD")
+	}
+	if start.After(end) {
+		return fmt.Errorf("start_date > end_date")
+	}
+	return nil
+}
+
+// ComputeRelativeStrength hitung & simpan RS tiap hari bursa di antara start dan end
+func ComputeRelativeStrength(startDate, endDate string) (int, int, error) {
+	req := models.RSComputeRequest{StartDate: startDate, EndDate: endDate}
+	if err := NormalizeRSComputeRequest(&req); err != nil {
+		return 0, 0, err
+	}
+	endDate = req.EndDate
+	start, _ := time.Parse("2006-01-02", startDate)
+	warmup := start.AddDate(0, 0, -rsWarmupDays).Format("2006-01-02")
+
+	days, err := repositories.GetTradingDates(warmup, endDate)
+	if err != nil {
+		return 0, 0, err
+	}
+	from := sort.Search(len(days), func(i int) bool { return !days[i].Before(start) })
+	if from >= len(days) {
+		return 0, 0, fmt.Errorf("no trading data between %s and %s", startDate, endDate)
+	}
+
+	series, err := loadPriceSeries(warmup, endDate)
+	if err != nil {
+		return 0, 0, err
+	}
+	benchmark, err := repositories.GetIndexCloses(constants.IndexComposite, warmup, endDate)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	rows := computeRelativeStrength(days, from, series, benchmark)
+	if err := repositories.UpsertRelativeStrength(rows); err != nil {
+		return 0, 0, err
+	}
+
+	return len(days) - from, len(rows), nil
+}
+
+// relativeStrengthAsOf = RS tersimpan di hari bursa terakhir <= as_of. Saham yang nggak ada
+// barisnya di hari yang sudah dihitung memang nggak masuk universe (histori kurang / nggak transaksi).
+func relativeStrengthAsOf(asOf string, codes []string) ([]models.RelativeStrength, time.Time, error) {
+	date, err := repositories.GetLatestTradingDate(asOf)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	rows, err := repositories.GetRelativeStrengthByDate(date, codes)
+	if err != nil {
+		return nil, date, err
+	}
+	if len(rows) > 0 {
+		return rows, date, nil
+	}
+
+	// cek dulu apakah memang belum dihitung sama sekali di tanggal itu
+	if len(codes) > 0 {
+		all, err := repositories.GetRelativeStrengthByDate(date, nil)
+		if err != nil || len(all) > 0 {
+			return rows, date, err
+		}
+	}
+	return nil, date, fmt.Errorf("%w for %s, run POST /rs/compute first", ErrRSNotComputed, date.Format("2006-01-02"))
+}
+
+// GetRSRanking = ranking RS se-market, rating >= minRating
+func GetRSRanking(asOf string, minRating, limit int) ([]models.RelativeStrength, time.Time, error) {
+	rows, date, err := relativeStrengthAsOf(asOf, nil)
+	if err != nil {
+		return nil, date, err
+	}
+
+	data := []models.RelativeStrength{}
+	for _, r := range rows {
+		if r.RSRating < minRating || len(data) >= limit {
+			break
+		}
+		data = append(data, r)
+	}
+	return data, date, nil
+}
+
+// rsHistoryCalendarDays = kira-kira berapa hari kalender buat dapat N hari bursa
+func rsHistoryCalendarDays(days int) int {
+	return days*7/5 + 30
+}
+
+// GetStockRelativeStrength = histori RS tersimpan satu saham (terbaru dulu). Hari bursa yang belum
+// pernah di-/rs/compute nggak dihitung di sini, jumlahnya dikembalikan sebagai missing.
+func GetStockRelativeStrength(stockCode, asOf string, days int) ([]models.RelativeStrength, int, error) {
+	rows, err := repositories.GetStockRelativeStrength(stockCode, asOf, days)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	end, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return nil, 0, err
+	}
+	start := end.AddDate(0, 0, -rsHistoryCalendarDays(days)).Format("2006-01-02")
+	tradingDays, err := repositories.GetTradingDates(start, asOf)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(tradingDays) > days {
+		tradingDays = tradingDays[len(tradingDays)-days:]
+	}
+	if len(tradingDays) == 0 {
+		return rows, 0, nil
+	}
+
+	computed, err := repositories.GetRelativeStrengthDates(tradingDays[0], tradingDays[len(tradingDays)-1])
+	if err != nil {
+		return nil, 0, err
+	}
+	done := make(map[string]bool, len(computed))
+	for _, d := range computed {
+		done[d.Format("2006-01-02")] = true
+	}
+	missing := 0
+	for _, d := range tradingDays {
+		if !done[d.Format("2006-01-02")] {
+			missing++
+		}
+	}
+
+	return rows, missing, nil
+}
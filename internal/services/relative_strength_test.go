@@ -0,0 +1,27 @@
+package services
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRSPercentile(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []float64
+		want   []int
+	}{
+		{"single stock", []float64{5}, []int{99}},
+		{"distinct", []float64{30, 10, 20}, []int{99, 1, 50}},
+		{"ties share the average rank", []float64{10, 20, 20, 30}, []int{1, 50, 50, 99}},
+		{"all equal", []float64{7, 7, 7}, []int{50, 50, 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rsPercentile(tt.scores); !slices.Equal(got, tt.want) {
+				t.Errorf("rsPercentile(%v) = %v, want %v", tt.scores, got, tt.want)
+			}
+		})
+	}
+}
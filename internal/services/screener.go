@@ -0,0 +1,147 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"sort"
+	"time"
+)
+
+// ScreenerFunc jalankan satu screener per tanggal as_of dan balikin sinyal seragam
+type ScreenerFunc func(asOf string, params models.ScreenerParams) ([]models.ScreenerSignal, error)
+
+// Lookback default tiap screener, samain dengan handler /analyze/*
+const (
+	topAccumulationDays    = 7
+	topAccumulationEODDays = 60
+	silentAccumulationDays = 7
+)
+
+var screeners = map[string]ScreenerFunc{
+	"top_accumulation": func(asOf string, params models.ScreenerParams) ([]models.ScreenerSignal, error) {
+		rows, err := repositories.GetTopAccumulation(topAccumulationDays, asOf, params)
+		if err != nil {
+			return nil, err
+		}
+
+		signals := make([]models.ScreenerSignal, 0, len(rows))
+		for _, r := range rows {
+			signals = append(signals, models.ScreenerSignal{
+				StockCode:     r.StockCode,
+				StockName:     r.StockName,
+				SignalDate:    r.LastTradeDate,
+				Price:         r.LastPrice,
+				DisplayStatus: r.DisplayStatus,
+			})
+		}
+		return signals, nil
+	},
+	"top_accumulation_eod": func(asOf string, params models.ScreenerParams) ([]models.ScreenerSignal, error) {
+		rows, err := repositories.GetTopAccumulationEOD(topAccumulationEODDays, asOf, params)
+		if err != nil {
+			return nil, err
+		}
+
+		signals := make([]models.ScreenerSignal, 0, len(rows))
+		for _, r := range rows {
+			signals = append(signals, models.ScreenerSignal{
+				StockCode:     r.StockCode,
+				StockName:     r.StockName,
+				SignalDate:    r.LastTradeDate,
+				Price:         r.LastPrice,
+				DisplayStatus: r.DisplayStatus,
+			})
+		}
+		return signals, nil
+	},
+	"silent_accumulation": func(asOf string, params models.ScreenerParams) ([]models.ScreenerSignal, error) {
+		rows, err := repositories.GetSilentAccumulation(silentAccumulationDays, asOf, params)
+		if err != nil {
+			return nil, err
+		}
+
+		signals := make([]models.ScreenerSignal, 0, len(rows))
+		for _, r := range rows {
+			signals = append(signals, models.ScreenerSignal{
+				StockCode:     r.StockCode,
+				StockName:     r.StockName,
+				SignalDate:    r.LastTradeDate,
+				Price:         r.LastPrice,
+				DisplayStatus: r.DisplayStatus,
+			})
+		}
+		return signals, nil
+	},
+	"top_swinger": func(asOf string, params models.ScreenerParams) ([]models.ScreenerSignal, error) {
+		rows, err := GetTopSwinger(asOf, params)
+		if err != nil {
+			return nil, err
+		}
+
+		signalDate, _ := time.Parse("2006-01-02", asOf)
+
+		signals := make([]models.ScreenerSignal, 0, len(rows))
+		for _, r := range rows {
+			signals = append(signals, models.ScreenerSignal{
+				StockCode:     r.StockCode,
+				StockName:     r.StockName,
+				SignalDate:    signalDate,
+				Price:         r.ClosePrice,
+				StopLoss:      r.StopLoss,
+				TakeProfit:    r.TakeProfit,
+				DisplayStatus: r.DisplayStatus,
+			})
+		}
+		return signals, nil
+	},
+}
+
+// ScreenerNames = daftar screener yang bisa dipakai backtest
+func ScreenerNames() []string {
+	names := make([]string, 0, len(screeners))
+	for name := range screeners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// screenerParams = default threshold tiap screener (sumbernya di repositories)
+var screenerParams = map[string]models.ScreenerParams{
+	"top_accumulation":     repositories.TopAccumulationParams,
+	"top_accumulation_eod": repositories.TopAccumulationEODParams,
+	"silent_accumulation":  repositories.SilentAccumulationParams,
+	"top_swinger":          repositories.TopSwingerParams,
+}
+
+// ScreenerParamDefaults = param yang bisa di-override/sweep untuk screener tertentu
+func ScreenerParamDefaults(name string) models.ScreenerParams {
+	return screenerParams[name]
+}
+
+func validateScreenerParams(name string, params models.ScreenerParams) error {
+	defaults := screenerParams[name]
+	for key := range params {
+		if _, ok := defaults[key]; !ok {
+			return fmt.Errorf("unknown param %q for screener %s", key, name)
+		}
+	}
+	return nil
+}
+
+func RunScreener(name string, asOf string, params models.ScreenerParams) ([]models.ScreenerSignal, error) {
+	fn, ok := screeners[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown screener %q, available: %v", name, ScreenerNames())
+	}
+
+	signals, err := fn(asOf, params)
+	if err != nil {
+		return nil, err
+	}
+	for i := range signals {
+		signals[i].Category = SignalCategory(signals[i].DisplayStatus)
+	}
+	return signals, nil
+}
@@ -0,0 +1,431 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"slices"
+	"strings"
+	"time"
+)
+
+// NormalizeScreenerFilter pecah nilai comma-separated dan validasi nama pola
+func NormalizeScreenerFilter(f *models.ScreenerFilter) error {
+	patterns := []string{}
+	for _, raw := range f.Patterns {
+		for _, p := range strings.Split(raw, ",") {
+			p = strings.ToLower(strings.TrimSpace(p))
+			if p == "" {
+				continue
+			}
+			if !slices.Contains(PatternNames(), p) {
+				return fmt.Errorf("unknown pattern %q, available: %v", p, PatternNames())
+			}
+			patterns = append(patterns, p)
+		}
+	}
+	f.Patterns = patterns
+
+	if f.PatternWithin <= 0 {
+		f.PatternWithin = 1
+	}
+
+	if f.PositionValue < 0 || f.ParticipationPct < 0 || f.ParticipationPct > 100 {
+		return fmt.Errorf("position_value must be >= 0 and participation 0-100")
+	}
+	if f.LiquidityActive() && f.PositionValue == 0 {
+		f.PositionValue = DefaultLiquidityPosition
+	}
+	if f.LiquidityActive() && f.ParticipationPct == 0 {
+		f.ParticipationPct = DefaultParticipationPct
+	}
+
+	switch f.LevelSource {
+	case "":
+		f.LevelSource = LevelSourceRolling
+	case LevelSourceRolling, LevelSourceZones:
+	default:
+		return fmt.Errorf("invalid level_source %q, use %s or %s", f.LevelSource, LevelSourceRolling, LevelSourceZones)
+	}
+
+	buckets, err := ParseSizeBuckets(f.SizeBuckets)
+	if err != nil {
+		return err
+	}
+	f.SizeBuckets = buckets
+
+	regimes := []string{}
+	for _, raw := range f.BreadthRegimes {
+		for _, r := range strings.Split(raw, ",") {
+			r = strings.ToLower(strings.TrimSpace(r))
+			if r == "" {
+				continue
+			}
+			if !slices.Contains(BreadthRegimes(), r) {
+				return fmt.Errorf("unknown breadth_regime %q, available: %v", r, BreadthRegimes())
+			}
+			regimes = append(regimes, r)
+		}
+	}
+	f.BreadthRegimes = regimes
+	return nil
+}
+
+// ApplyScreenerFilter jalankan filter ke hasil screener. Yang lolos dibalikin beserta anotasinya,
+// saham yang nggak ada di map berarti kebuang.
+func ApplyScreenerFilter(asOf string, codes []string, f models.ScreenerFilter) (map[string]*models.ScreenerAnnotation, error) {
+	result := make(map[string]*models.ScreenerAnnotation, len(codes))
+	for _, code := range codes {
+		result[code] = &models.ScreenerAnnotation{}
+	}
+	if len(result) == 0 {
+		return result, nil
+	}
+
+	// Gate pasar dulu, kalau regime nggak cocok nggak perlu hitung filter per saham
+	if len(f.BreadthRegimes) > 0 {
+		if err := filterBreadthRegime(asOf, f, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(f.Patterns) > 0 && len(result) > 0 {
+		if err := filterPatterns(asOf, f, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.LevelsActive() && len(result) > 0 {
+		if err := filterLevels(asOf, f, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.MinNonRegularRatio != nil && len(result) > 0 {
+		if err := filterNonRegular(asOf, f, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.OrderBookActive() && len(result) > 0 {
+		if err := filterOrderBook(asOf, f, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.RSActive() && len(result) > 0 {
+		if err := filterRelativeStrength(asOf, f, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.MarketCapActive() && len(result) > 0 {
+		if err := filterMarketCap(asOf, f, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.FundamentalsActive() && len(result) > 0 {
+		if err := filterFundamentals(asOf, f, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.LiquidityActive() && len(result) > 0 {
+		if err := filterLiquidity(asOf, f, result); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// filterBreadthRegime: regime pasar as_of harus salah satu yang diminta, berlaku ke semua saham
+func filterBreadthRegime(asOf string, f models.ScreenerFilter, result map[string]*models.ScreenerAnnotation) error {
+	breadth, err := marketBreadthAsOf(asOf)
+	if err != nil {
+		return err
+	}
+
+	allowed := slices.Contains(f.BreadthRegimes, breadth.Regime)
+	for code, ann := range result {
+		if !allowed {
+			delete(result, code)
+			continue
+		}
+		ann.BreadthRegime = breadth.Regime
+	}
+	return nil
+}
+
+func remainingCodes(result map[string]*models.ScreenerAnnotation) []string {
+	codes := make([]string, 0, len(result))
+	for code := range result {
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+// loadSeriesAsOf ambil bar saham-saham tertentu sampai as_of, lookback dalam hari kalender
+func loadSeriesAsOf(codes []string, asOf string, lookbackDays int) (map[string]*priceSeries, error) {
+	end, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return nil, err
+	}
+	start := end.AddDate(0, 0, -lookbackDays).Format("2006-01-02")
+
+	bars, err := repositories.GetStockBars(codes, start, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return buildPriceSeries(bars), nil
+}
+
+// filterPatterns: lolos kalau salah satu pola yang diminta muncul di PatternWithin bar terakhir
+func filterPatterns(asOf string, f models.ScreenerFilter, result map[string]*models.ScreenerAnnotation) error {
+	series, err := loadSeriesAsOf(remainingCodes(result), asOf, patternLookbackDays)
+	if err != nil {
+		return err
+	}
+
+	for code, ann := range result {
+		s, ok := series[code]
+		if !ok || len(s.bars) == 0 {
+			delete(result, code)
+			continue
+		}
+
+		last := len(s.bars) - 1
+		found := []string{}
+		for i := last; i > last-f.PatternWithin && i >= 0; i-- {
+			for _, hit := range DetectPatterns(s.bars, i) {
+				if slices.Contains(f.Patterns, hit.Name) && !slices.Contains(found, hit.Name) {
+					found = append(found, hit.Name)
+				}
+			}
+		}
+
+		if len(found) == 0 {
+			delete(result, code)
+			continue
+		}
+		ann.Patterns = found
+	}
+	return nil
+}
+
+// ErrNoTradingData = saham nggak dikenal atau nggak ada transaksi di rentang yang diminta (client error)
+var ErrNoTradingData = errors.New("no trading data")
+
+// GetStockPatterns = pola di bar terakhir + histori pola N hari bursa terakhir untuk satu saham
+func GetStockPatterns(stockCode, asOf string, days int) (*models.StockPatterns, error) {
+	series, err := loadSeriesAsOf([]string{stockCode}, asOf, patternLookbackDays+days*2)
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := series[stockCode]
+	if !ok || len(s.bars) == 0 {
+		return nil, fmt.Errorf("%w for %s up to %s", ErrNoTradingData, stockCode, asOf)
+	}
+
+	last := len(s.bars) - 1
+	result := &models.StockPatterns{
+		StockCode: stockCode,
+		StockName: s.bars[last].StockName,
+		AsOf:      asOf,
+		Latest:    DetectPatterns(s.bars, last),
+		History:   []models.PatternHit{},
+	}
+	for i := last; i > last-days && i >= 0; i-- {
+		result.History = append(result.History, DetectPatterns(s.bars, i)...)
+	}
+
+	return result, nil
+}
+
+// filterLevels isi jarak ke zona S/R terdekat, lalu buang yang di luar batas jarak
+func filterLevels(asOf string, f models.ScreenerFilter, result map[string]*models.ScreenerAnnotation) error {
+	series, err := loadSeriesAsOf(remainingCodes(result), asOf, levelLookbackDays(DefaultLevelLookback))
+	if err != nil {
+		return err
+	}
+
+	for code, ann := range result {
+		s, ok := series[code]
+		if !ok || len(s.bars) == 0 {
+			delete(result, code)
+			continue
+		}
+
+		levels := StockLevelsAt(s.bars, len(s.bars)-1, DefaultLevelLookback)
+		if z := levels.NearestResistance; z != nil {
+			ann.Resistance = &z.Mid
+		}
+		if z := levels.NearestSupport; z != nil {
+			ann.Support = &z.Mid
+		}
+		ann.DistToResistancePct = levels.DistToResistancePct
+		ann.DistToSupportPct = levels.DistToSupportPct
+
+		res, sup := levels.DistToResistancePct, levels.DistToSupportPct
+		switch {
+		case f.MaxDistResistance != nil && (res == nil || *res > *f.MaxDistResistance),
+			f.MinDistResistance != nil && res != nil && *res < *f.MinDistResistance,
+			f.MaxDistSupport != nil && (sup == nil || *sup > *f.MaxDistSupport):
+			delete(result, code)
+		}
+	}
+	return nil
+}
+
+// filterNonRegular: lolos kalau transaksi nego di hari as_of masuk kategori unusual
+func filterNonRegular(asOf string, f models.ScreenerFilter, result map[string]*models.ScreenerAnnotation) error {
+	activity, _, err := nonRegularAsOf(asOf, DefaultNonRegularHistory, *f.MinNonRegularRatio, DefaultNonRegularMinValue, remainingCodes(result))
+	if err != nil {
+		return err
+	}
+
+	for code, ann := range result {
+		a, ok := activity[code]
+		if !ok || !a.Unusual {
+			delete(result, code)
+			continue
+		}
+		value := a.NonRegularValue
+		ann.NonRegularValue = &value
+		ann.NonRegularRatio = a.Ratio
+		ann.CrossingPrice = a.CrossingPrice
+		ann.CrossingPremiumPct = a.CrossingPremiumPct
+	}
+	return nil
+}
+
+func filterOrderBook(asOf string, f models.ScreenerFilter, result map[string]*models.ScreenerAnnotation) error {
+	book, err := orderBookAsOf(asOf, remainingCodes(result))
+	if err != nil {
+		return err
+	}
+
+	for code, ann := range result {
+		p, ok := book[code]
+		if !ok {
+			delete(result, code)
+			continue
+		}
+		if f.MinOrderBookImbalance != nil && p.ImbalancePct < *f.MinOrderBookImbalance {
+			delete(result, code)
+			continue
+		}
+		imbalance := p.ImbalancePct
+		ann.OrderBookImbalancePct = &imbalance
+		ann.SpreadTicks = p.SpreadTicks
+		ann.OrderBookStatus = p.Status
+	}
+	return nil
+}
+
+func filterRelativeStrength(asOf string, f models.ScreenerFilter, result map[string]*models.ScreenerAnnotation) error {
+	rows, _, err := relativeStrengthAsOf(asOf, remainingCodes(result))
+	if err != nil {
+		return err
+	}
+
+	byCode := make(map[string]models.RelativeStrength, len(rows))
+	for _, r := range rows {
+		byCode[r.StockCode] = r
+	}
+
+	for code, ann := range result {
+		r, ok := byCode[code]
+		if !ok ||
+			(f.MinRSRating != nil && r.RSRating < *f.MinRSRating) ||
+			(f.RSLineNewHigh && !r.RSLineNewHigh) {
+			delete(result, code)
+			continue
+		}
+		ann.RSRating = &r.RSRating
+		ann.RSLineNewHigh = &r.RSLineNewHigh
+	}
+	return nil
+}
+
+// filterLiquidity: skor dihitung relatif ke semua saham, bukan cuma hasil screener
+func filterLiquidity(asOf string, f models.ScreenerFilter, result map[string]*models.ScreenerAnnotation) error {
+	rows, err := marketLiquidity(asOf, DefaultLiquidityWindow)
+	if err != nil {
+		return err
+	}
+
+	for code, ann := range result {
+		l, ok := rows[code]
+		if !ok {
+			delete(result, code)
+			continue
+		}
+		applyPositionSize(l, f.PositionValue, f.ParticipationPct)
+		if (f.MinADV != nil && l.AvgValue < *f.MinADV) ||
+			(f.MinLiquidityScore != nil && l.LiquidityScore < *f.MinLiquidityScore) ||
+			(f.MaxDaysToEnter != nil && (l.DaysToEnter == nil || *l.DaysToEnter > *f.MaxDaysToEnter)) {
+			delete(result, code)
+			continue
+		}
+		ann.AvgValue = &l.AvgValue
+		ann.LiquidityScore = &l.LiquidityScore
+		ann.DaysToEnter = l.DaysToEnter
+	}
+	return nil
+}
+
+func filterMarketCap(asOf string, f models.ScreenerFilter, result map[string]*models.ScreenerAnnotation) error {
+	rows, _, err := marketCapsAsOf(asOf)
+	if err != nil {
+		return err
+	}
+
+	byCode := make(map[string]models.StockMarketCap, len(rows))
+	for _, r := range rows {
+		byCode[r.StockCode] = r
+	}
+
+	for code, ann := range result {
+		m, ok := byCode[code]
+		if !ok ||
+			(len(f.SizeBuckets) > 0 && !slices.Contains(f.SizeBuckets, m.SizeBucket)) ||
+			(f.MinMarketCap != nil && m.MarketCap < *f.MinMarketCap) ||
+			(f.MaxMarketCap != nil && m.MarketCap > *f.MaxMarketCap) ||
+			(f.MinFreeFloatPct != nil && m.FreeFloatPct < *f.MinFreeFloatPct) {
+			delete(result, code)
+			continue
+		}
+		ann.MarketCap = &m.MarketCap
+		ann.FreeFloatPct = &m.FreeFloatPct
+		ann.SizeBucket = m.SizeBucket
+	}
+	return nil
+}
+
+func filterFundamentals(asOf string, f models.ScreenerFilter, result map[string]*models.ScreenerAnnotation) error {
+	rows, err := valuationsAsOf(asOf, remainingCodes(result))
+	if err != nil {
+		return err
+	}
+
+	above := func(v *float64, limit *float64) bool {
+		return limit != nil && (v == nil || *v > *limit)
+	}
+	for code, ann := range result {
+		v, ok := rows[code]
+		if !ok ||
+			above(v.PER, f.MaxPER) ||
+			above(v.PBV, f.MaxPBV) ||
+			above(v.DER, f.MaxDER) ||
+			(f.MinROE != nil && (v.ROE == nil || *v.ROE < *f.MinROE)) {
+			delete(result, code)
+			continue
+		}
+		ann.PER, ann.PBV, ann.ROE, ann.DER = v.PER, v.PBV, v.ROE, v.DER
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/constants"
+	"indonesia-stocks-api/internal/helpers"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	QuadrantLeading   = "leading"
+	QuadrantWeakening = "weakening"
+	QuadrantLagging   = "lagging"
+	QuadrantImproving = "improving"
+
+	sectorVolumeBaseline = 60 // hari bursa pembanding volume expansion
+	rrgRatioPeriod       = 21 // RS-Ratio = RS relatif ke rata-rata 21 hari
+	rrgMomentumPeriod    = 5  // RS-Momentum = perubahan RS-Ratio 5 hari
+
+	// hari bursa yang dibutuhkan: horizon terpanjang + baseline volume
+	sectorHistoryDays = 63 + sectorVolumeBaseline
+)
+
+var sectorHorizons = []struct {
+	name string
+	days int
+}{
+	{"1W", 5},
+	{"1M", 21},
+	{"3M", 63},
+}
+
+// SectorRankOptions = nilai rank_by yang valid
+func SectorRankOptions() []string {
+	opts := []string{"rs_ratio"}
+	for _, h := range sectorHorizons {
+		opts = append(opts, h.name)
+	}
+	return opts
+}
+
+// NormalizeSectorUpload rapikan kode/sektor dan tolak kode saham dobel
+func NormalizeSectorUpload(req *models.SectorUploadRequest) error {
+	seen := map[string]bool{}
+	for i := range req.Data {
+		s := &req.Data[i]
+		s.StockCode = strings.ToUpper(strings.TrimSpace(s.StockCode))
+		s.Sector = strings.TrimSpace(s.Sector)
+		s.SubSector = strings.TrimSpace(s.SubSector)
+		if s.StockCode == "" || s.Sector == "" {
+			return fmt.Errorf("row %d: stock_code and sector are required", i+1)
+		}
+		if seen[s.StockCode] {
+			return fmt.Errorf("duplicate stock_code %s", s.StockCode)
+		}
+		seen[s.StockCode] = true
+	}
+	return nil
+}
+
+// sectorStock = data satu saham yang disejajarkan ke kalender bursa
+type sectorStock struct {
+	sector string
+	close  []float64 // forward fill
+	value  []float64 // 0 kalau nggak transaksi
+	net    []float64 // net foreign (Rp)
+}
+
+func alignSectorBars(days []time.Time, bars []models.SectorBar) map[string]*sectorStock {
+	dayIdx := make(map[string]int, len(days))
+	for i, d := range days {
+		dayIdx[d.Format("2006-01-02")] = i
+	}
+
+	stocks := map[string]*sectorStock{}
+	for _, b := range bars {
+		i, ok := dayIdx[b.TradeDate.Format("2006-01-02")]
+		if !ok || b.Close <= 0 {
+			continue
+		}
+		s, ok := stocks[b.StockCode]
+		if !ok {
+			n := len(days)
+			s = &sectorStock{sector: b.Sector, close: make([]float64, n), value: make([]float64, n), net: make([]float64, n)}
+			stocks[b.StockCode] = s
+		}
+		s.close[i] = b.Close
+		s.value[i] = b.Value
+		s.net[i] = (b.ForeignBuy - b.ForeignSell) * b.Close
+	}
+
+	for _, s := range stocks {
+		for i := 1; i < len(days); i++ {
+			if s.close[i] == 0 {
+				s.close[i] = s.close[i-1]
+			}
+		}
+	}
+	return stocks
+}
+
+func sumRange(xs []float64, from, to int) float64 {
+	total := 0.0
+	for i := max(0, from); i <= to && i < len(xs); i++ {
+		total += xs[i]
+	}
+	return total
+}
+
+// sectorHorizon agregasi satu sektor untuk periode `days` hari bursa yang berakhir di hari ke-d
+func sectorHorizon(stocks []*sectorStock, d, days int, name string) models.SectorHorizonStats {
+	stats := models.SectorHorizonStats{Horizon: name, Days: days}
+	var weighted, weights, equal float64
+	var counted int
+	var valueNow, valueBase float64
+
+	for _, s := range stocks {
+		if d-days < 0 {
+			continue
+		}
+		stats.NetForeignVal += sumRange(s.net, d-days+1, d)
+		valueNow += sumRange(s.value, d-days+1, d)
+		valueBase += sumRange(s.value, d-days-sectorVolumeBaseline+1, d-days)
+
+		from, to := s.close[d-days], s.close[d]
+		if from <= 0 || to <= 0 {
+			continue
+		}
+		ret := (to/from - 1) * 100
+		w := sumRange(s.value, d-days+1, d)
+
+		weighted += ret * w
+		weights += w
+		equal += ret
+		counted++
+		switch {
+		case ret > 0:
+			stats.Advancers++
+		case ret < 0:
+			stats.Decliners++
+		}
+	}
+
+	if weights > 0 {
+		stats.ReturnPct = weighted / weights
+	}
+	if counted > 0 {
+		stats.EqualWeightReturn = equal / float64(counted)
+		stats.BreadthPct = float64(stats.Advancers) / float64(counted) * 100
+	}
+	baseDays := min(sectorVolumeBaseline, d-days+1)
+	if valueBase > 0 && baseDays > 0 {
+		exp := (valueNow / float64(days)) / (valueBase / float64(baseDays))
+		stats.VolumeExpansion = &exp
+	}
+	stats.FormattedForeign = helpers.FormatBigNumber(stats.NetForeignVal)
+	return stats
+}
+
+// sectorIndex = indeks harian sektor (mulai 100), bobot = total value saham selama window
+func sectorIndex(stocks []*sectorStock, from, to int) []float64 {
+	idx := make([]float64, to-from+1)
+	idx[0] = 100
+
+	weights := make([]float64, len(stocks))
+	for k, s := range stocks {
+		weights[k] = sumRange(s.value, from, to)
+	}
+
+	for t := from + 1; t <= to; t++ {
+		var sum, w float64
+		for k, s := range stocks {
+			if s.close[t-1] > 0 && s.close[t] > 0 && weights[k] > 0 {
+				sum += (s.close[t]/s.close[t-1] - 1) * weights[k]
+				w += weights[k]
+			}
+		}
+		ret := 0.0
+		if w > 0 {
+			ret = sum / w
+		}
+		idx[t-from] = idx[t-from-1] * (1 + ret)
+	}
+	return idx
+}
+
+// rrgPoint hitung RS-Ratio & RS-Momentum di titik terakhir (gaya Relative Rotation Graph)
+func rrgPoint(sector, bench []float64) (*float64, *float64) {
+	n := len(sector)
+	if n < rrgRatioPeriod+rrgMomentumPeriod || len(bench) != n {
+		return nil, nil
+	}
+
+	rel := make([]float64, n)
+	for i := range sector {
+		if bench[i] <= 0 {
+			return nil, nil
+		}
+		rel[i] = sector[i] / bench[i]
+	}
+
+	ratioAt := func(i int) float64 {
+		sum := 0.0
+		for j := i - rrgRatioPeriod + 1; j <= i; j++ {
+			sum += rel[j]
+		}
+		return 100 * rel[i] / (sum / rrgRatioPeriod)
+	}
+
+	ratio := ratioAt(n - 1)
+	momentum := 100 * ratio / ratioAt(n-1-rrgMomentumPeriod)
+	return &ratio, &momentum
+}
+
+func rrgQuadrant(ratio, momentum *float64) string {
+	if ratio == nil || momentum == nil {
+		return ""
+	}
+	switch {
+	case *ratio >= 100 && *momentum >= 100:
+		return QuadrantLeading
+	case *ratio >= 100:
+		return QuadrantWeakening
+	case *momentum >= 100:
+		return QuadrantImproving
+	}
+	return QuadrantLagging
+}
+
+// GetSectorRotation = return, breadth, foreign flow, volume expansion per sektor untuk 1W/1M/3M
+// plus kuadran RRG terhadap IHSG (atau rata-rata semua sektor kalau data IHSG nggak ada)
+func GetSectorRotation(asOf, rankBy string) (*models.SectorRotationReport, error) {
+	end, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return nil, err
+	}
+	start := end.AddDate(0, 0, -(sectorHistoryDays*7/5 + 30)).Format("2006-01-02")
+
+	days, err := repositories.GetTradingDates(start, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if len(days) == 0 {
+		return nil, fmt.Errorf("%w up to %s", ErrNoTradingData, asOf)
+	}
+	if len(days) > sectorHistoryDays+1 {
+		days = days[len(days)-sectorHistoryDays-1:]
+	}
+	first := days[0].Format("2006-01-02")
+
+	bars, err := repositories.GetSectorBars(first, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("no sector mapping, upload via POST /sectors/upload first")
+	}
+
+	bySector := map[string][]*sectorStock{}
+	for _, s := range alignSectorBars(days, bars) {
+		bySector[s.sector] = append(bySector[s.sector], s)
+	}
+
+	d := len(days) - 1
+	rrgFrom := max(0, d-rrgRatioPeriod-rrgMomentumPeriod-5)
+
+	report := &models.SectorRotationReport{
+		AsOf:      asOf,
+		TradeDate: days[d],
+		Benchmark: constants.IndexComposite,
+		RankBy:    rankBy,
+		Sectors:   []models.SectorRotation{},
+	}
+
+	indices := map[string][]float64{}
+	for sector, stocks := range bySector {
+		row := models.SectorRotation{Sector: sector, Stocks: len(stocks)}
+		for _, h := range sectorHorizons {
+			row.Horizons = append(row.Horizons, sectorHorizon(stocks, d, h.days, h.name))
+		}
+		indices[sector] = sectorIndex(stocks, rrgFrom, d)
+		report.Sectors = append(report.Sectors, row)
+	}
+
+	bench, err := rrgBenchmark(days[rrgFrom:], indices)
+	if err != nil {
+		return nil, err
+	}
+	if bench == nil {
+		report.Benchmark = "SECTOR_AVERAGE"
+		bench = averageIndex(indices)
+	}
+
+	for i := range report.Sectors {
+		row := &report.Sectors[i]
+		row.RSRatio, row.RSMomentum = rrgPoint(indices[row.Sector], bench)
+		row.Quadrant = rrgQuadrant(row.RSRatio, row.RSMomentum)
+	}
+
+	rankSectors(report.Sectors, rankBy)
+	return report, nil
+}
+
+// rrgBenchmark = close IHSG sejajar kalender, nil kalau ada hari yang bolong
+func rrgBenchmark(days []time.Time, indices map[string][]float64) ([]float64, error) {
+	closes, err := repositories.GetIndexCloses(constants.IndexComposite, days[0].Format("2006-01-02"), days[len(days)-1].Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	byDate := make(map[string]float64, len(closes))
+	for _, c := range closes {
+		byDate[c.TradeDate.Format("2006-01-02")] = c.Close
+	}
+
+	bench := make([]float64, len(days))
+	for i, d := range days {
+		v, ok := byDate[d.Format("2006-01-02")]
+		if !ok || v <= 0 {
+			return nil, nil
+		}
+		bench[i] = v
+	}
+	return bench, nil
+}
+
+func averageIndex(indices map[string][]float64) []float64 {
+	var avg []float64
+	for _, idx := range indices {
+		if avg == nil {
+			avg = make([]float64, len(idx))
+		}
+		for i, v := range idx {
+			avg[i] += v / float64(len(indices))
+		}
+	}
+	return avg
+}
+
+// rankSectors isi rank per horizon, lalu urutkan & isi rank utama sesuai rankBy
+func rankSectors(sectors []models.SectorRotation, rankBy string) {
+	for h := range sectorHorizons {
+		order := make([]int, len(sectors))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(a, b int) bool {
+			return sectors[order[a]].Horizons[h].ReturnPct > sectors[order[b]].Horizons[h].ReturnPct
+		})
+		for rank, i := range order {
+			sectors[i].Horizons[h].Rank = rank + 1
+		}
+	}
+
+	key := func(s models.SectorRotation) float64 {
+		if rankBy == "rs_ratio" {
+			if s.RSRatio == nil {
+				return 0
+			}
+			return *s.RSRatio
+		}
+		for h, hz := range sectorHorizons {
+			if hz.name == rankBy {
+				return s.Horizons[h].ReturnPct
+			}
+		}
+		return 0
+	}
+	sort.SliceStable(sectors, func(a, b int) bool { return key(sectors[a]) > key(sectors[b]) })
+	for i := range sectors {
+		sectors[i].Rank = i + 1
+	}
+}
@@ -0,0 +1,34 @@
+package services
+
+import "strings"
+
+// signalCategories urutan penting: label yang lebih spesifik dicek duluan
+// (contoh "TESTING RES (SIAP HAKA)" harus jadi TESTING_RES, bukan HAKA)
+var signalCategories = []struct {
+	keyword  string
+	category string
+}{
+	{"GOLDEN SIGNAL", "GOLDEN_SIGNAL"},
+	{"TESTING RES", "TESTING_RES"},
+	{"HAKA", "HAKA"},
+	{"RETRACE", "RETRACE"},
+	{"BOW", "BOW"},
+	{"BREAKOUT", "BREAKOUT"},
+	{"WHALE ONLY", "WHALE_ONLY"},
+	{"COLLECTIONS", "COLLECTIONS"},
+	{"BOOM VOLUME", "BOOM_VOLUME"},
+	{"UPTREND", "UPTREND"},
+	{"SIDEWAYS", "SIDEWAYS"},
+	{"WATCH", "WATCH"},
+}
+
+// SignalCategory ambil kategori aksi dari DisplayStatus screener
+func SignalCategory(status string) string {
+	upper := strings.ToUpper(status)
+	for _, c := range signalCategories {
+		if strings.Contains(upper, c.keyword) {
+			return c.category
+		}
+	}
+	return "OTHER"
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"slices"
+	"sort"
+	"time"
+)
+
+var defaultStudyHorizons = []int{1, 3, 5, 10, 20}
+
+func NormalizeSignalStudyConfig(cfg *models.SignalStudyConfig) error {
+	start, err := time.Parse("2006-01-02", cfg.StartDate)
+	if err != nil {
+		return fmt.Errorf("invalid start_date, format: YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", cfg.EndDate)
+	if err != nil {
+		return fmt.Errorf("invalid end_date, format: YYYY-MM-DD")
+	}
+	if start.After(end) {
+		return fmt.Errorf("start_date > end_date")
+	}
+	if _, ok := screeners[cfg.Screener]; !ok {
+		return fmt.Errorf("unknown screener %q, available: %v", cfg.Screener, ScreenerNames())
+	}
+	if err := validateScreenerParams(cfg.Screener, cfg.Params); err != nil {
+		return err
+	}
+
+	if len(cfg.Horizons) == 0 {
+		cfg.Horizons = defaultStudyHorizons
+	}
+	for _, h := range cfg.Horizons {
+		if h <= 0 {
+			return fmt.Errorf("horizons must be > 0")
+		}
+	}
+
+	// Copy dulu biar default global nggak ikut berubah, lalu buang horizon dobel (5,5)
+	horizons := append([]int(nil), cfg.Horizons...)
+	sort.Ints(horizons)
+	cfg.Horizons = slices.Compact(horizons)
+	return nil
+}
+
+// RunSignalStudy: untuk tiap hari sinyal, ukur forward return dari close hari sinyal
+// ke close H hari bursa berikutnya, plus MFE/MAE selama H hari itu.
+func RunSignalStudy(cfg models.SignalStudyConfig) (*models.SignalStudyReport, error) {
+	if err := NormalizeSignalStudyConfig(&cfg); err != nil {
+		return nil, err
+	}
+
+	days, err := repositories.GetTradingDates(cfg.StartDate, cfg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if len(days) == 0 {
+		return nil, fmt.Errorf("no trading data between %s and %s", cfg.StartDate, cfg.EndDate)
+	}
+
+	// Butuh bar sesudah end_date buat horizon terpanjang (kalender x2 biar aman libur panjang)
+	maxHorizon := cfg.Horizons[len(cfg.Horizons)-1]
+	end, _ := time.Parse("2006-01-02", cfg.EndDate)
+	series, err := loadPriceSeries(cfg.StartDate, end.AddDate(0, 0, maxHorizon*2+14).Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	events := []models.SignalEvent{}
+	for _, day := range days {
+		signals, err := RunScreener(cfg.Screener, day.Format("2006-01-02"), cfg.Params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sig := range signals {
+			s, ok := series[sig.StockCode]
+			if !ok {
+				continue
+			}
+			i, ok := s.at(day)
+			if !ok || s.bars[i].Close <= 0 {
+				continue
+			}
+			events = append(events, models.SignalEvent{
+				StockCode:     sig.StockCode,
+				SignalDate:    day,
+				Category:      sig.Category,
+				DisplayStatus: sig.DisplayStatus,
+				EntryPrice:    s.bars[i].Close,
+				Forward:       forwardReturns(s, i, cfg.Horizons),
+			})
+		}
+	}
+
+	report := &models.SignalStudyReport{
+		Config:       cfg,
+		SignalDays:   len(days),
+		TotalSignals: len(events),
+		Overall:      studyCategory("ALL", events, cfg.Horizons),
+	}
+
+	byCategory := map[string][]models.SignalEvent{}
+	for _, e := range events {
+		byCategory[e.Category] = append(byCategory[e.Category], e)
+	}
+	for category, list := range byCategory {
+		report.Categories = append(report.Categories, studyCategory(category, list, cfg.Horizons))
+	}
+	sort.Slice(report.Categories, func(i, j int) bool {
+		return report.Categories[i].Signals > report.Categories[j].Signals
+	})
+
+	if cfg.IncludeEvents {
+		report.Events = events
+	}
+
+	return report, nil
+}
+
+// forwardReturns pakai index bar saham itu sendiri, jadi hari suspend nggak dihitung.
+// Horizon yang belum kejadian (data belum ada) di-skip.
+func forwardReturns(s *priceSeries, i int, horizons []int) []models.ForwardReturn {
+	entry := s.bars[i].Close
+	result := []models.ForwardReturn{}
+
+	high, low := entry, entry
+	step := i
+	for _, h := range horizons {
+		target := i + h
+		if target >= len(s.bars) {
+			break
+		}
+		for step < target {
+			step++
+			high = max(high, s.bars[step].High)
+			if s.bars[step].Low > 0 {
+				low = min(low, s.bars[step].Low)
+			}
+		}
+		result = append(result, models.ForwardReturn{
+			Horizon:   h,
+			ReturnPct: ((s.bars[target].Close - entry) / entry) * 100,
+			MFEPct:    ((high - entry) / entry) * 100,
+			MAEPct:    ((low - entry) / entry) * 100,
+		})
+	}
+	return result
+}
+
+func studyCategory(category string, events []models.SignalEvent, horizons []int) models.CategoryStudy {
+	study := models.CategoryStudy{Category: category, Signals: len(events)}
+
+	for _, h := range horizons {
+		returns := []float64{}
+		var sumMFE, sumMAE float64
+		hits := 0
+
+		for _, e := range events {
+			for _, f := range e.Forward {
+				if f.Horizon != h {
+					continue
+				}
+				returns = append(returns, f.ReturnPct)
+				sumMFE += f.MFEPct
+				sumMAE += f.MAEPct
+				if f.ReturnPct > 0 {
+					hits++
+				}
+			}
+		}
+
+		stats := models.HorizonStats{Horizon: h, Samples: len(returns)}
+		if len(returns) > 0 {
+			p := percentiles(returns)
+			n := float64(len(returns))
+			stats.AvgReturnPct = p.Mean
+			stats.MedReturnPct = p.P50
+			stats.BestPct = p.Max
+			stats.WorstPct = p.Min
+			stats.HitRatePct = float64(hits) / n * 100
+			stats.AvgMFEPct = sumMFE / n
+			stats.AvgMAEPct = sumMAE / n
+		}
+		study.Horizons = append(study.Horizons, stats)
+	}
+
+	return study
+}
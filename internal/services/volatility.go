@@ -0,0 +1,211 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	volATRPeriod        = 14
+	volBBPeriod         = 20
+	volBBStdDev         = 2.0
+	volBBWWindow        = 120 // percentile bandwidth vs 120 hari bursa
+	volSqueezePct       = 10  // bandwidth di 10% terbawah = squeeze
+	volStopATRMult      = 2.0
+	volTargetATRMult    = 3.0
+	volatilityWarmupBar = volBBWWindow + volBBPeriod + 60
+
+	DefaultSqueezeMinValue = 1_000_000_000
+)
+
+// hv = standar deviasi log return N bar terakhir sampai i, disetahunkan dalam %
+func (p *priceSeries) hv(i, period int) float64 {
+	if i < period {
+		return 0
+	}
+	rets := make([]float64, 0, period)
+	for j := i - period + 1; j <= i; j++ {
+		prev, cur := p.bars[j-1].Close, p.bars[j].Close
+		if prev > 0 && cur > 0 {
+			rets = append(rets, math.Log(cur/prev))
+		}
+	}
+	_, std := meanStd(rets)
+	return std * math.Sqrt(tradingDaysPerYear) * 100
+}
+
+// bollinger = SMA N +- k standar deviasi (populasi), 0 semua kalau histori kurang
+func (p *priceSeries) bollinger(i, period int, k float64) (float64, float64, float64) {
+	mid := p.sma(i, period)
+	if mid <= 0 {
+		return 0, 0, 0
+	}
+	sq := 0.0
+	for j := i - period + 1; j <= i; j++ {
+		d := p.bars[j].Close - mid
+		sq += d * d
+	}
+	std := math.Sqrt(sq / float64(period))
+	return mid, mid + k*std, mid - k*std
+}
+
+func (p *priceSeries) bandwidth(i int) float64 {
+	mid, upper, lower := p.bollinger(i, volBBPeriod, volBBStdDev)
+	if mid <= 0 {
+		return 0
+	}
+	return (upper - lower) / mid * 100
+}
+
+func (p *priceSeries) bandwidthPercentile(i int) float64 {
+	bw := p.bandwidth(i)
+	if bw <= 0 {
+		return 0
+	}
+	below, n := 0, 0
+	for j := max(volBBPeriod-1, i-volBBWWindow+1); j <= i; j++ {
+		if x := p.bandwidth(j); x > 0 {
+			n++
+			if x <= bw {
+				below++
+			}
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return float64(below) / float64(n) * 100
+}
+
+func (p *priceSeries) inSqueeze(i int) bool {
+	return i+1 >= volBBPeriod+volBBWWindow/2 && p.bandwidthPercentile(i) <= volSqueezePct
+}
+
+func volatilityAt(code string, s *priceSeries, i int) models.StockVolatility {
+	bar := s.bars[i]
+	v := models.StockVolatility{
+		StockCode: code,
+		TradeDate: bar.TradeDate.Format("2006-01-02"),
+		Close:     bar.Close,
+		HV20:      s.hv(i, 20),
+		HV60:      s.hv(i, 60),
+		ATR14:     s.atr(i, volATRPeriod),
+	}
+	if bar.Close > 0 {
+		v.ATRPct = v.ATR14 / bar.Close * 100
+	}
+	v.BBMiddle, v.BBUpper, v.BBLower = s.bollinger(i, volBBPeriod, volBBStdDev)
+	v.BBBandwidth = s.bandwidth(i)
+	v.BBWPercentile = s.bandwidthPercentile(i)
+
+	v.Squeeze = s.inSqueeze(i)
+	for j := i; j >= 0 && s.inSqueeze(j); j-- {
+		v.SqueezeDays++
+	}
+	v.Released = !v.Squeeze && i > 0 && s.inSqueeze(i-1)
+
+	if v.ATR14 > 0 {
+		v.StopLoss = RoundToTick(math.Max(bar.Close-volStopATRMult*v.ATR14, 1), false)
+		v.TakeProfit = RoundToTick(bar.Close+volTargetATRMult*v.ATR14, true)
+	}
+
+	if i+1 >= volBBPeriod {
+		sum := 0.0
+		for j := i - volBBPeriod + 1; j <= i; j++ {
+			sum += s.bars[j].Value
+		}
+		v.AvgValue20 = sum / volBBPeriod
+	}
+	return v
+}
+
+// GetStockVolatility = time series volatilitas N hari bursa terakhir sampai as_of
+func GetStockVolatility(stockCode, asOf string, days int) ([]models.StockVolatility, error) {
+	series, err := loadSeriesAsOf([]string{stockCode}, asOf, levelLookbackDays(days+volatilityWarmupBar))
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := series[stockCode]
+	if !ok || len(s.bars) == 0 {
+		return nil, fmt.Errorf("%w for %s up to %s", ErrNoTradingData, stockCode, asOf)
+	}
+
+	out := []models.StockVolatility{}
+	for i := max(0, len(s.bars)-days); i < len(s.bars); i++ {
+		out = append(out, volatilityAt(stockCode, s, i))
+	}
+	return out, nil
+}
+
+// ScanSqueeze = saham likuid yang lagi squeeze (atau baru lepas squeeze) di hari bursa terakhir <= as_of
+func ScanSqueeze(asOf string, minValue float64, includeReleased bool) ([]models.StockVolatility, error) {
+	end, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return nil, err
+	}
+	start := end.AddDate(0, 0, -levelLookbackDays(volatilityWarmupBar)).Format("2006-01-02")
+
+	series, err := loadPriceSeries(start, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest time.Time
+	for _, s := range series {
+		if d := s.bars[len(s.bars)-1].TradeDate; d.After(latest) {
+			latest = d
+		}
+	}
+
+	out := []models.StockVolatility{}
+	for code, s := range series {
+		i := len(s.bars) - 1
+		if !s.bars[i].TradeDate.Equal(latest) || !tradable(s.bars[i]) {
+			continue
+		}
+		v := volatilityAt(code, s, i)
+		if v.AvgValue20 < minValue {
+			continue
+		}
+		if v.Squeeze || (includeReleased && v.Released) {
+			out = append(out, v)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].SqueezeDays != out[j].SqueezeDays {
+			return out[i].SqueezeDays > out[j].SqueezeDays
+		}
+		return out[i].BBWPercentile < out[j].BBWPercentile
+	})
+	return out, nil
+}
+
+// GetTopSwinger = screener top swinger. Stop/target kelipatan ATR dibulatkan ke fraksi harga IDX
+// (stop ke bawah, target ke atas), sama seperti level di /analyze/volatility. Level persen tetap
+// (multiplier 0 atau ATR nggak ada) dibiarkan apa adanya dari SQL.
+func GetTopSwinger(asOf string, params models.ScreenerParams) ([]models.TopSwinger, error) {
+	rows, err := repositories.GetTopSwinger(asOf, params)
+	if err != nil {
+		return nil, err
+	}
+	stopMult := params.Get("atr_stop_mult", repositories.TopSwingerParams["atr_stop_mult"])
+	targetMult := params.Get("atr_target_mult", repositories.TopSwingerParams["atr_target_mult"])
+	for i := range rows {
+		if rows[i].ATR14 <= 0 {
+			continue
+		}
+		if stopMult > 0 {
+			rows[i].StopLoss = RoundToTick(rows[i].StopLoss, false)
+		}
+		if targetMult > 0 {
+			rows[i].TakeProfit = RoundToTick(rows[i].TakeProfit, true)
+		}
+	}
+	return rows, nil
+}
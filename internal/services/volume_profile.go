@@ -0,0 +1,319 @@
+package services
+
+import (
+	"fmt"
+	"indonesia-stocks-api/internal/models"
+	"indonesia-stocks-api/internal/repositories"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultProfileWindow = 60
+	DefaultProfileBins   = 24
+	DefaultAVWAPAnchors  = "ipo,52w_low"
+
+	profileValueAreaPct = 0.70
+
+	AnchorIPO           = "ipo"
+	Anchor52WLow        = "52w_low"
+	Anchor52WHigh       = "52w_high"
+	avwapMaxHistoryDays = 365 * 30 // anchor paling jauh yang dicari datanya
+
+	ProfileAboveValue = "above_value"
+	ProfileInValue    = "in_value"
+	ProfileBelowValue = "below_value"
+)
+
+// VolumeProfileOf bagi volume tiap bar rata ke rentang low-high-nya (pendekatan dari data harian).
+// Value area = POC diperluas ke bin tetangga yang volumenya lebih besar sampai 70% volume.
+func VolumeProfileOf(bars []models.DailyBar, bins int) models.VolumeProfile {
+	profile := models.VolumeProfile{Bars: len(bars), Bins: []models.VolumeProfileBin{}}
+	if len(bars) == 0 {
+		return profile
+	}
+	profile.StartDate = bars[0].TradeDate.Format("2006-01-02")
+	profile.EndDate = bars[len(bars)-1].TradeDate.Format("2006-01-02")
+	profile.Close = bars[len(bars)-1].Close
+
+	lo, hi := math.MaxFloat64, 0.0
+	for _, b := range bars {
+		if b.Volume <= 0 || b.Low <= 0 {
+			continue
+		}
+		lo = math.Min(lo, b.Low)
+		hi = math.Max(hi, b.High)
+	}
+	if hi <= 0 || lo > hi {
+		return profile
+	}
+	if hi == lo {
+		bins = 1
+	}
+	step := (hi - lo) / float64(bins)
+
+	volumes := make([]float64, bins)
+	binOf := func(price float64) int {
+		if step == 0 {
+			return 0
+		}
+		return min(bins-1, int((price-lo)/step))
+	}
+	total := 0.0
+	for _, b := range bars {
+		if b.Volume <= 0 || b.Low <= 0 {
+			continue
+		}
+		total += b.Volume
+		if b.High <= b.Low {
+			volumes[binOf(b.Close)] += b.Volume
+			continue
+		}
+		for k := binOf(b.Low); k <= binOf(b.High); k++ {
+			binLo, binHi := lo+float64(k)*step, lo+float64(k+1)*step
+			overlap := math.Min(b.High, binHi) - math.Max(b.Low, binLo)
+			if overlap > 0 {
+				volumes[k] += b.Volume * overlap / (b.High - b.Low)
+			}
+		}
+	}
+	if total == 0 {
+		return profile
+	}
+
+	poc := 0
+	for k := range volumes {
+		if volumes[k] > volumes[poc] {
+			poc = k
+		}
+	}
+
+	inValue := make([]bool, bins)
+	inValue[poc] = true
+	covered := volumes[poc]
+	down, up := poc-1, poc+1
+	for covered < total*profileValueAreaPct && (down >= 0 || up < bins) {
+		if up >= bins || (down >= 0 && volumes[down] > volumes[up]) {
+			inValue[down] = true
+			covered += volumes[down]
+			down--
+		} else {
+			inValue[up] = true
+			covered += volumes[up]
+			up++
+		}
+	}
+
+	overhead := 0.0
+	for k := range volumes {
+		binLo, binHi := lo+float64(k)*step, lo+float64(k+1)*step
+		if k == bins-1 {
+			binHi = hi
+		}
+		profile.Bins = append(profile.Bins, models.VolumeProfileBin{
+			PriceLow:    binLo,
+			PriceHigh:   binHi,
+			Volume:      volumes[k],
+			Pct:         volumes[k] / total * 100,
+			InValueArea: inValue[k],
+		})
+		switch {
+		case binLo >= profile.Close:
+			overhead += volumes[k]
+		case binHi > profile.Close && binHi > binLo:
+			overhead += volumes[k] * (binHi - profile.Close) / (binHi - binLo)
+		}
+	}
+
+	profile.POC = lo + (float64(poc)+0.5)*step
+	profile.VAL = lo + float64(down+1)*step
+	profile.VAH = lo + float64(up)*step
+	profile.OverheadSupplyPct = overhead / total * 100
+
+	switch {
+	case profile.Close > profile.VAH:
+		profile.ClosePosition = ProfileAboveValue
+	case profile.Close < profile.VAL:
+		profile.ClosePosition = ProfileBelowValue
+	default:
+		profile.ClosePosition = ProfileInValue
+	}
+	return profile
+}
+
+// GetVolumeProfile = profil volume `window` hari bursa terakhir sampai as_of
+func GetVolumeProfile(stockCode, asOf string, window, bins int) (*models.VolumeProfile, error) {
+	series, err := loadSeriesAsOf([]string{stockCode}, asOf, levelLookbackDays(window))
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := series[stockCode]
+	if !ok || len(s.bars) == 0 {
+		return nil, fmt.Errorf("%w for %s up to %s", ErrNoTradingData, stockCode, asOf)
+	}
+
+	bars := s.bars[max(0, len(s.bars)-window):]
+	profile := VolumeProfileOf(bars, bins)
+	profile.StockCode = stockCode
+	return &profile, nil
+}
+
+// ParseAVWAPAnchors validasi daftar anchor: ipo, 52w_low, 52w_high atau tanggal YYYY-MM-DD
+func ParseAVWAPAnchors(raw string) ([]string, error) {
+	anchors := []string{}
+	for _, a := range strings.Split(raw, ",") {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == "" {
+			continue
+		}
+		switch a {
+		case AnchorIPO, Anchor52WLow, Anchor52WHigh:
+		default:
+			if _, err := time.Parse("2006-01-02", a); err != nil {
+				return nil, fmt.Errorf("invalid anchor %q, use %s, %s, %s or YYYY-MM-DD", a, AnchorIPO, Anchor52WLow, Anchor52WHigh)
+			}
+		}
+		anchors = append(anchors, a)
+	}
+	if len(anchors) == 0 {
+		return nil, fmt.Errorf("anchors is empty")
+	}
+	return anchors, nil
+}
+
+// barVWAPValue pakai value transaksi asli kalau ada, selain itu typical price x volume
+func barVWAPValue(b models.DailyBar) float64 {
+	if b.Value > 0 {
+		return b.Value
+	}
+	return (b.High + b.Low + b.Close) / 3 * b.Volume
+}
+
+// anchoredVWAP hitung VWAP kumulatif dari bar index from sampai akhir, series cuma `days` titik terakhir
+func anchoredVWAP(bars []models.DailyBar, from, days int) (float64, []models.AVWAPPoint) {
+	var value, volume float64
+	series := []models.AVWAPPoint{}
+	vwap := 0.0
+	for i := from; i < len(bars); i++ {
+		value += barVWAPValue(bars[i])
+		volume += bars[i].Volume
+		if volume > 0 {
+			vwap = value / volume
+		}
+		if i >= len(bars)-days {
+			series = append(series, models.AVWAPPoint{
+				Date:  bars[i].TradeDate.Format("2006-01-02"),
+				VWAP:  vwap,
+				Close: bars[i].Close,
+			})
+		}
+	}
+	return vwap, series
+}
+
+// extremeBar = index bar dengan low terendah (high = true: high tertinggi) mulai dari index from
+func extremeBar(bars []models.DailyBar, from int, high bool) int {
+	best := -1
+	for i := from; i < len(bars); i++ {
+		if bars[i].Low <= 0 {
+			continue
+		}
+		if best < 0 || (high && bars[i].High > bars[best].High) || (!high && bars[i].Low < bars[best].Low) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return from
+	}
+	return best
+}
+
+// firstBarFrom = bar pertama di tanggal anchor atau sesudahnya (anchor hari libur geser ke hari bursa berikutnya)
+func firstBarFrom(bars []models.DailyBar, date time.Time) int {
+	for i, b := range bars {
+		if !b.TradeDate.Before(date) {
+			return i
+		}
+	}
+	return len(bars) - 1
+}
+
+// GetAnchoredVWAP hitung AVWAP dari tiap anchor sampai as_of
+func GetAnchoredVWAP(stockCode, asOf string, anchors []string, days int) ([]models.AnchoredVWAP, error) {
+	end, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	// Histori yang perlu diambil = anchor paling jauh
+	start := end.AddDate(0, 0, -levelLookbackDays(tradingDaysPerYear))
+	var listing *time.Time
+	for _, a := range anchors {
+		switch a {
+		case AnchorIPO:
+			if listing, err = repositories.GetStockListingDate(stockCode); err != nil {
+				return nil, err
+			}
+			if listing == nil {
+				return nil, fmt.Errorf("listing date for %s not found in m_list_stocks", stockCode)
+			}
+			if listing.Before(start) {
+				start = *listing
+			}
+		case Anchor52WLow, Anchor52WHigh:
+		default:
+			d, _ := time.Parse("2006-01-02", a)
+			if d.After(end) {
+				return nil, fmt.Errorf("anchor %s is after as_of", a)
+			}
+			if d.Before(start) {
+				start = d
+			}
+		}
+	}
+	if oldest := end.AddDate(0, 0, -avwapMaxHistoryDays); start.Before(oldest) {
+		start = oldest
+	}
+
+	bars, err := repositories.GetStockBars([]string{stockCode}, start.Format("2006-01-02"), asOf)
+	if err != nil {
+		return nil, err
+	}
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("%w for %s up to %s", ErrNoTradingData, stockCode, asOf)
+	}
+
+	out := make([]models.AnchoredVWAP, 0, len(anchors))
+	for _, a := range anchors {
+		var from int
+		var anchorDate time.Time
+		switch a {
+		case Anchor52WLow, Anchor52WHigh:
+			from = extremeBar(bars, max(0, len(bars)-tradingDaysPerYear), a == Anchor52WHigh)
+			anchorDate = bars[from].TradeDate
+		case AnchorIPO:
+			anchorDate = *listing
+			from = firstBarFrom(bars, anchorDate)
+		default:
+			anchorDate, _ = time.Parse("2006-01-02", a)
+			from = firstBarFrom(bars, anchorDate)
+		}
+
+		vwap, series := anchoredVWAP(bars, from, days)
+		row := models.AnchoredVWAP{
+			Anchor:     a,
+			AnchorDate: anchorDate.Format("2006-01-02"),
+			Truncated:  bars[from].TradeDate.After(anchorDate) && from == 0,
+			VWAP:       vwap,
+			Close:      bars[len(bars)-1].Close,
+			Series:     series,
+		}
+		if vwap > 0 {
+			row.DistancePct = (row.Close - vwap) / vwap * 100
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
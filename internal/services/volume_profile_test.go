@@ -0,0 +1,72 @@
+package services
+
+import (
+	"indonesia-stocks-api/internal/models"
+	"testing"
+)
+
+// flatBar = bar tanpa range, semua volume jatuh di bin harga close
+func flatBar(day int, price, volume float64) models.DailyBar {
+	return models.DailyBar{TradeDate: testDay(day), Open: price, High: price, Low: price, Close: price, Volume: volume}
+}
+
+func TestVolumeProfileOf(t *testing.T) {
+	tests := []struct {
+		name         string
+		bars         []models.DailyBar
+		bins         int
+		wantBins     int
+		wantPOC      float64
+		wantVAL      float64
+		wantVAH      float64
+		wantPosition string
+		wantOverhead float64
+	}{
+		{"no bars", nil, 4, 0, 0, 0, 0, "", 0},
+		{
+			"volume spread over the bar range",
+			[]models.DailyBar{
+				{TradeDate: testDay(0), Low: 100, High: 200, Close: 150, Volume: 1000},
+				{TradeDate: testDay(1), Low: 100, High: 125, Close: 110, Volume: 3000},
+			},
+			4, 4, 112.5, 100, 125, ProfileInValue, 67.5,
+		},
+		{
+			"value area grows toward the heavier neighbour",
+			[]models.DailyBar{
+				flatBar(0, 100, 100),
+				flatBar(1, 130, 300),
+				flatBar(2, 500, 0), // tanpa volume, nggak ikut range
+				flatBar(3, 150, 400),
+				flatBar(4, 180, 200),
+			},
+			4, 4, 150, 120, 160, ProfileAboveValue, 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := VolumeProfileOf(tt.bars, tt.bins)
+			if p.Bars != len(tt.bars) || len(p.Bins) != tt.wantBins {
+				t.Fatalf("bars = %d, bins = %d, want %d and %d", p.Bars, len(p.Bins), len(tt.bars), tt.wantBins)
+			}
+			if !almostEqual(p.POC, tt.wantPOC) || !almostEqual(p.VAL, tt.wantVAL) || !almostEqual(p.VAH, tt.wantVAH) {
+				t.Errorf("POC/VAL/VAH = %v/%v/%v, want %v/%v/%v", p.POC, p.VAL, p.VAH, tt.wantPOC, tt.wantVAL, tt.wantVAH)
+			}
+			if p.ClosePosition != tt.wantPosition {
+				t.Errorf("close position = %q, want %q", p.ClosePosition, tt.wantPosition)
+			}
+			if !almostEqual(p.OverheadSupplyPct, tt.wantOverhead) {
+				t.Errorf("overhead supply = %v%%, want %v%%", p.OverheadSupplyPct, tt.wantOverhead)
+			}
+
+			total := 0.0
+			for _, b := range p.Bins {
+				total += b.Pct
+			}
+			if len(p.Bins) > 0 && !almostEqual(total, 100) {
+				t.Errorf("bin pct sums to %v, want 100", total)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+-- Ringkasan harian indeks (IHSG = COMPOSITE), dipakai buat benchmark backtest
+CREATE TABLE IF NOT EXISTS t_index_summary (
+    id              BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    trade_date      DATE            NOT NULL,
+    index_code      VARCHAR(32)     NOT NULL,
+    previous_price  DECIMAL(18,4)   NOT NULL DEFAULT 0,
+    high_price      DECIMAL(18,4)   NOT NULL DEFAULT 0,
+    low_price       DECIMAL(18,4)   NOT NULL DEFAULT 0,
+    close_price     DECIMAL(18,4)   NOT NULL DEFAULT 0,
+    change_price    DECIMAL(18,4)   NOT NULL DEFAULT 0,
+    volume          BIGINT          NOT NULL DEFAULT 0,
+    value           DECIMAL(24,2)   NOT NULL DEFAULT 0,
+    frequency       BIGINT          NOT NULL DEFAULT 0,
+    market_capital  DECIMAL(24,2)   NOT NULL DEFAULT 0,
+    created_at      DATETIME        NOT NULL,
+    updated_at      DATETIME        NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_index_summary (trade_date, index_code),
+    KEY idx_index_code_date (index_code, trade_date)
+);
@@ -0,0 +1,56 @@
+-- Hasil backtest yang disimpan biar bisa dibandingkan & dijalankan ulang
+CREATE TABLE IF NOT EXISTS t_backtest_run (
+    id            BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    screener      VARCHAR(64)     NOT NULL,
+    config_json   JSON            NOT NULL,
+    code_version  VARCHAR(64)     NOT NULL,
+    data_start    DATE            NOT NULL,
+    data_end      DATE            NOT NULL,
+    data_hash     CHAR(64)        NOT NULL,
+    summary_json  JSON            NOT NULL,
+    metrics_json  JSON            NOT NULL,
+    total_trades  INT             NOT NULL DEFAULT 0,
+    rerun_of      BIGINT UNSIGNED NULL,
+    duration_ms   BIGINT          NOT NULL DEFAULT 0,
+    created_at    DATETIME        NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_backtest_run_screener (screener, created_at)
+);
+
+CREATE TABLE IF NOT EXISTS t_backtest_trade (
+    id                 BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    run_id             BIGINT UNSIGNED NOT NULL,
+    stock_code         VARCHAR(16)     NOT NULL,
+    stock_name         VARCHAR(255)    NOT NULL,
+    signal_date        DATE            NOT NULL,
+    signal_status      VARCHAR(512)    NOT NULL,
+    entry_date         DATE            NOT NULL,
+    entry_price        DECIMAL(18,4)   NOT NULL,
+    exit_date          DATE            NOT NULL,
+    exit_price         DECIMAL(18,4)   NOT NULL,
+    exit_reason        VARCHAR(32)     NOT NULL,
+    holding_days       INT             NOT NULL,
+    return_pct         DOUBLE          NOT NULL,
+    stop_loss_level    DECIMAL(18,4)   NOT NULL DEFAULT 0,
+    take_profit_level  DECIMAL(18,4)   NOT NULL DEFAULT 0,
+    lots               INT             NOT NULL DEFAULT 0,
+    buy_fee            DECIMAL(24,2)   NOT NULL DEFAULT 0,
+    sell_fee           DECIMAL(24,2)   NOT NULL DEFAULT 0,
+    net_pnl            DECIMAL(24,2)   NOT NULL DEFAULT 0,
+    net_return_pct     DOUBLE          NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    KEY idx_backtest_trade_run (run_id),
+    CONSTRAINT fk_backtest_trade_run FOREIGN KEY (run_id) REFERENCES t_backtest_run (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS t_backtest_equity (
+    run_id            BIGINT UNSIGNED NOT NULL,
+    trade_date        DATE            NOT NULL,
+    cash              DECIMAL(24,2)   NOT NULL,
+    positions_value   DECIMAL(24,2)   NOT NULL,
+    equity            DECIMAL(24,2)   NOT NULL,
+    open_positions    INT             NOT NULL,
+    daily_return_pct  DOUBLE          NOT NULL,
+    PRIMARY KEY (run_id, trade_date),
+    CONSTRAINT fk_backtest_equity_run FOREIGN KEY (run_id) REFERENCES t_backtest_run (id) ON DELETE CASCADE
+);
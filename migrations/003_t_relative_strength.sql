@@ -0,0 +1,18 @@
+-- RS rating harian (gaya IBD) + RS line terhadap IHSG
+CREATE TABLE IF NOT EXISTS t_relative_strength (
+    trade_date        DATE            NOT NULL,
+    stock_code        VARCHAR(16)     NOT NULL,
+    stock_name        VARCHAR(255)    NOT NULL,
+    close_price       DECIMAL(18,4)   NOT NULL,
+    return_3m         DOUBLE          NULL,
+    return_6m         DOUBLE          NULL,
+    return_12m        DOUBLE          NULL,
+    weighted_score    DOUBLE          NOT NULL,
+    rs_rating         TINYINT         NOT NULL, -- persentil 1-99 di universe hari itu
+    rs_line           DOUBLE          NULL,     -- close / close IHSG
+    rs_line_new_high  TINYINT(1)      NOT NULL DEFAULT 0,
+    created_at        DATETIME        NOT NULL,
+    PRIMARY KEY (trade_date, stock_code),
+    KEY idx_rs_stock_date (stock_code, trade_date),
+    KEY idx_rs_rating (trade_date, rs_rating)
+);
@@ -0,0 +1,9 @@
+-- Mapping saham ke sektor (IDX-IC), diisi lewat POST /sectors/upload
+CREATE TABLE IF NOT EXISTS m_stock_sector (
+    stock_code   VARCHAR(16)   NOT NULL,
+    sector       VARCHAR(128)  NOT NULL,
+    sub_sector   VARCHAR(128)  NOT NULL DEFAULT '',
+    updated_at   DATETIME      NOT NULL,
+    PRIMARY KEY (stock_code),
+    KEY idx_stock_sector (sector)
+);
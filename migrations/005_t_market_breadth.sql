@@ -0,0 +1,22 @@
+-- Breadth market harian dari t_trading_summary
+CREATE TABLE IF NOT EXISTS t_market_breadth (
+    trade_date        DATE      NOT NULL,
+    total_stocks      INT       NOT NULL,
+    advancers         INT       NOT NULL,
+    decliners         INT       NOT NULL,
+    unchanged         INT       NOT NULL,
+    ad_line           BIGINT    NOT NULL, -- kumulatif (advancers - decliners)
+    new_highs_52w     INT       NOT NULL,
+    new_lows_52w      INT       NOT NULL,
+    pct_above_ma20    DOUBLE    NOT NULL,
+    pct_above_ma50    DOUBLE    NOT NULL,
+    pct_above_ma200   DOUBLE    NOT NULL,
+    ema19             DOUBLE    NOT NULL, -- disimpan biar McClellan bisa lanjut dihitung per hari
+    ema39             DOUBLE    NOT NULL,
+    mcclellan_osc     DOUBLE    NOT NULL,
+    mcclellan_sum     DOUBLE    NOT NULL,
+    ara_count         INT       NOT NULL,
+    arb_count         INT       NOT NULL,
+    created_at        DATETIME  NOT NULL,
+    PRIMARY KEY (trade_date)
+);
@@ -0,0 +1,7 @@
+-- Daftar saham per universe (LQ45, IDX30, watchlist), diisi lewat POST /universes/upload
+CREATE TABLE IF NOT EXISTS m_stock_universe (
+    universe_code  VARCHAR(64)  NOT NULL,
+    stock_code     VARCHAR(16)  NOT NULL,
+    updated_at     DATETIME     NOT NULL,
+    PRIMARY KEY (universe_code, stock_code)
+);
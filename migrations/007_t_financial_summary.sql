@@ -0,0 +1,18 @@
+-- Ringkasan laporan keuangan kuartalan, diisi lewat POST /fundamentals/upload (JSON / CSV)
+-- Angka revenue, net_income, eps = kuartal itu saja (bukan kumulatif YTD)
+CREATE TABLE IF NOT EXISTS t_financial_summary (
+    stock_code      VARCHAR(16)     NOT NULL,
+    fiscal_year     SMALLINT        NOT NULL,
+    fiscal_quarter  TINYINT         NOT NULL, -- 1-4
+    period_end      DATE            NOT NULL,
+    published_date  DATE            NOT NULL, -- baru boleh dipakai screener mulai tanggal ini
+    revenue         DECIMAL(24,2)   NOT NULL,
+    net_income      DECIMAL(24,2)   NOT NULL, -- laba bersih atribusi pemilik entitas induk
+    total_equity    DECIMAL(24,2)   NOT NULL,
+    eps             DOUBLE          NULL,
+    der             DOUBLE          NULL,
+    source          VARCHAR(32)     NOT NULL DEFAULT 'upload',
+    updated_at      DATETIME        NOT NULL,
+    PRIMARY KEY (stock_code, fiscal_year, fiscal_quarter),
+    KEY idx_fin_published (published_date)
+);